@@ -8,6 +8,7 @@ import (
 	"github.com/bl4ko/netbox-ssot/internal/logger"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
 	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/resolver"
 	"github.com/bl4ko/netbox-ssot/internal/source"
 )
 
@@ -47,6 +48,10 @@ func main() {
 	}
 	mainLogger.Debug("Netbox inventory initialized: ", netboxInventory)
 
+	// Dns resolver is shared between all sources, so its cache lasts for the whole run
+	dnsResolver := resolver.New(config.DNS.Servers, time.Duration(config.DNS.Timeout)*time.Second, config.DNS.Concurrency)
+	mainLogger.Debug("Parsed DNS config: ", config.DNS)
+
 	// Go through all sources and sync data
 	for i := range config.Sources {
 		sourceConfig := &config.Sources[i]
@@ -56,7 +61,7 @@ func main() {
 		if err != nil {
 			mainLogger.Errorf("source logger: %s", err)
 		}
-		source, err := source.NewSource(sourceConfig, sourceLogger, netboxInventory)
+		source, err := source.NewSource(sourceConfig, sourceLogger, netboxInventory, dnsResolver)
 		if err != nil {
 			sourceLogger.Error(err)
 			return
@@ -4,8 +4,11 @@ go 1.21
 
 require (
 	github.com/cisco-en-programmability/dnacenter-go-sdk/v5 v5.0.25
+	github.com/gosnmp/gosnmp v1.38.0
+	github.com/kolo/xmlrpc v0.0.0-20220921171641-a4b6fa1dd06b
 	github.com/ovirt/go-ovirt v4.3.4+incompatible
 	github.com/vmware/govmomi v0.35.0
+	golang.org/x/net v0.20.0
 	golang.org/x/text v0.14.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -13,6 +16,6 @@ require (
 require (
 	github.com/go-resty/resty/v2 v2.11.0 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
 	github.com/rogpeppe/go-internal v1.12.0 // indirect
-	golang.org/x/net v0.20.0 // indirect
 )
@@ -5,9 +5,14 @@ import "github.com/bl4ko/netbox-ssot/internal/netbox/objects"
 type SourceType string
 
 const (
-	Ovirt  SourceType = "ovirt"
-	Vmware SourceType = "vmware"
-	Dnac   SourceType = "dnac"
+	Ovirt   SourceType = "ovirt"
+	Vmware  SourceType = "vmware"
+	Dnac    SourceType = "dnac"
+	Nutanix SourceType = "nutanix"
+	Xen     SourceType = "xen"
+	Redfish SourceType = "redfish"
+	SNMP    SourceType = "snmp"
+	Netbox  SourceType = "netbox"
 )
 
 const (
@@ -19,20 +24,50 @@ const (
 
 // Default mappings of sources to colors (for tags).
 var DefaultSourceToTagColorMap = map[SourceType]string{
-	Ovirt:  objects.ColorDarkRed,
-	Vmware: objects.ColorLightGreen,
-	Dnac:   objects.ColorLightBlue,
+	Ovirt:   objects.ColorDarkRed,
+	Vmware:  objects.ColorLightGreen,
+	Dnac:    objects.ColorLightBlue,
+	Nutanix: objects.ColorAmber,
+	Xen:     objects.ColorCyan,
+	Redfish: objects.ColorGrey,
+	SNMP:    objects.ColorPurple,
+	Netbox:  objects.ColorIndigo,
 }
 
 // Object for mapping source type to tag color.
 var SourceTypeToTagColorMap = map[SourceType]string{
-	Ovirt:  objects.ColorRed,
-	Vmware: objects.ColorGreen,
-	Dnac:   objects.ColorBlue,
+	Ovirt:   objects.ColorRed,
+	Vmware:  objects.ColorGreen,
+	Dnac:    objects.ColorBlue,
+	Nutanix: objects.ColorOrange,
+	Xen:     objects.ColorTeal,
+	Redfish: objects.ColorDarkGrey,
+	SNMP:    objects.ColorDarkPurple,
+	Netbox:  objects.ColorBrown,
 }
 
 const (
 	DefaultTimeout = 10
+	// DefaultDNSTimeout is timeout of a single dns query in seconds.
+	DefaultDNSTimeout = 2
+	// DefaultDNSConcurrency is the maximum number of concurrent dns queries.
+	DefaultDNSConcurrency = 10
+	DNSDefaultPort        = "53"
+	// DefaultPageSize is the number of objects requested per page from Netbox's API.
+	DefaultPageSize = 100
+	// MaxPageSize is Netbox's default MAX_PAGE_SIZE. Larger pages are silently truncated by Netbox.
+	MaxPageSize = 1000
+	// DefaultSnapshotMaxAge is the age in hours, after which the inventory snapshot is discarded.
+	DefaultSnapshotMaxAge = 24
+)
+
+// Provisioning types of virtual disks, stored in CustomFieldProvisioningTypeName.
+const (
+	DiskProvisioningThin             = "thin"
+	DiskProvisioningThick            = "thick"
+	DiskProvisioningThickEagerZeroed = "thick-eager-zeroed"
+	DiskProvisioningThickLazyZeroed  = "thick-lazy-zeroed"
+	DiskProvisioningRDM              = "rdm"
 )
 
 // Magic numbers for dealing with bytes.
@@ -56,6 +91,7 @@ const (
 
 const (
 	HTTPSDefaultPort = 443
+	SNMPDefaultPort  = 161
 )
 
 // Names used for netbox objects custom fields attribute.
@@ -80,4 +116,39 @@ const (
 	CustomFieldHostMemoryName        = "host_memory"
 	CustomFieldHostMemoryLabel       = "Host memory"
 	CustomFieldHostMemoryDescription = "Amount of memory on the host"
+
+	// Custom field for dcim.device, so we can add bios version for each server.
+	CustomFieldBIOSVersionName        = "bios_version"
+	CustomFieldBIOSVersionLabel       = "BIOS version"
+	CustomFieldBIOSVersionDescription = "Version of the host's BIOS"
+
+	// Custom field for dcim.device, so we can add firmware version of each server's BMC.
+	CustomFieldBMCFirmwareName        = "bmc_firmware"
+	CustomFieldBMCFirmwareLabel       = "BMC firmware"
+	CustomFieldBMCFirmwareDescription = "Firmware version of the host's BMC (e.g. iDRAC, iLO, XCC)"
+
+	// Custom field for dcim.device, so we can add wireless lan controller of each access point.
+	CustomFieldWLCName        = "wlc"
+	CustomFieldWLCLabel       = "WLC"
+	CustomFieldWLCDescription = "Wireless LAN controller, with which the access point is associated"
+
+	// Custom field for virtualization.virtualmachine, so we can add vmware folder path of each vm.
+	CustomFieldVMFolderName        = "vmware_folder"
+	CustomFieldVMFolderLabel       = "VMware folder"
+	CustomFieldVMFolderDescription = "Inventory folder path of the vm in vCenter"
+
+	// Custom field for virtualization.virtualmachine, so we can add vmware resource pool of each vm.
+	CustomFieldVMResourcePoolName        = "vmware_resource_pool"
+	CustomFieldVMResourcePoolLabel       = "VMware resource pool"
+	CustomFieldVMResourcePoolDescription = "Resource pool path of the vm in vCenter"
+
+	// Custom field for virtualization.virtualdisk, so we can add datastore (storage domain) of each virtual disk.
+	CustomFieldDatastoreName        = "datastore"
+	CustomFieldDatastoreLabel       = "Datastore"
+	CustomFieldDatastoreDescription = "Datastore or storage domain on which the virtual disk is stored"
+
+	// Custom field for virtualization.virtualdisk, so we can add provisioning type of each virtual disk.
+	CustomFieldProvisioningTypeName        = "provisioning_type"
+	CustomFieldProvisioningTypeLabel       = "Provisioning type"
+	CustomFieldProvisioningTypeDescription = "Provisioning type of the virtual disk (e.g. thin, thick)"
 )
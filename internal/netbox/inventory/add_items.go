@@ -1,8 +1,11 @@
 package inventory
 
 import (
+	"fmt"
 	"slices"
+	"strings"
 
+	"github.com/bl4ko/netbox-ssot/internal/constants"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
 	"github.com/bl4ko/netbox-ssot/internal/utils"
@@ -39,165 +42,39 @@ func (nbi *NetboxInventory) AddTag(newTag *objects.Tag) (*objects.Tag, error) {
 	return oldTag, nil
 }
 
-// AddContact adds a contact to the local netbox inventory.
+// AddRegion adds the newRegion to the local netbox inventory.
+func (nbi *NetboxInventory) AddRegion(newRegion *objects.Region) (*objects.Region, error) {
+	return nbi.regions.Add(newRegion)
+}
+
+// AddSite adds the newSite to the local netbox inventory.
 func (nbi *NetboxInventory) AddSite(newSite *objects.Site) (*objects.Site, error) {
-	newSite.Tags = append(newSite.Tags, nbi.SsotTag)
-	if _, ok := nbi.SitesIndexByName[newSite.Name]; ok {
-		oldSite := nbi.SitesIndexByName[newSite.Name]
-		diffMap, err := utils.JSONDiffMapExceptID(newSite, oldSite, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Site ", newSite.Name, " already exists in Netbox but is out of date. Patching it... ")
-			patchedSite, err := service.Patch[objects.Site](nbi.NetboxAPI, oldSite.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.SitesIndexByName[newSite.Name] = patchedSite
-		} else {
-			nbi.Logger.Debug("Site ", newSite.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Site ", newSite.Name, " does not exist in Netbox. Creating it...")
-		createdContact, err := service.Create[objects.Site](nbi.NetboxAPI, newSite)
-		if err != nil {
-			return nil, err
-		}
-		nbi.SitesIndexByName[newSite.Name] = createdContact
-	}
-	return nbi.SitesIndexByName[newSite.Name], nil
+	return nbi.sites.Add(newSite)
+}
+
+// AddLocation adds the newLocation to the local netbox inventory.
+func (nbi *NetboxInventory) AddLocation(newLocation *objects.Location) (*objects.Location, error) {
+	return nbi.locations.Add(newLocation)
 }
 
 // AddContactRole adds the newContactRole to the local netbox inventory.
 func (nbi *NetboxInventory) AddContactRole(newContactRole *objects.ContactRole) (*objects.ContactRole, error) {
-	newContactRole.NetboxObject.Tags = []*objects.Tag{nbi.SsotTag}
-	if _, ok := nbi.ContactRolesIndexByName[newContactRole.Name]; ok {
-		oldContactRole := nbi.ContactRolesIndexByName[newContactRole.Name]
-		diffMap, err := utils.JSONDiffMapExceptID(newContactRole, oldContactRole, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Contact role ", newContactRole.Name, " already exists in Netbox but is out of date. Patching it... ")
-			patchedContactRole, err := service.Patch[objects.ContactRole](nbi.NetboxAPI, oldContactRole.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.ContactRolesIndexByName[newContactRole.Name] = patchedContactRole
-		} else {
-			nbi.Logger.Debug("Contact role ", newContactRole.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Contact role ", newContactRole.Name, " does not exist in Netbox. Creating it...")
-		newContactRole, err := service.Create[objects.ContactRole](nbi.NetboxAPI, newContactRole)
-		if err != nil {
-			return nil, err
-		}
-		nbi.ContactRolesIndexByName[newContactRole.Name] = newContactRole
-	}
-	return nbi.ContactRolesIndexByName[newContactRole.Name], nil
+	return nbi.contactRoles.Add(newContactRole)
 }
 
 // AddContactGroup adds contact group to the local netbox inventory.
 func (nbi *NetboxInventory) AddContactGroup(newContactGroup *objects.ContactGroup) (*objects.ContactGroup, error) {
-	if _, ok := nbi.ContactGroupsIndexByName[newContactGroup.Name]; ok {
-		oldContactGroup := nbi.ContactGroupsIndexByName[newContactGroup.Name]
-		diffMap, err := utils.JSONDiffMapExceptID(newContactGroup, oldContactGroup, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Contact group ", newContactGroup.Name, " already exists in Netbox but is out of date. Patching it... ")
-			patchedContactGroup, err := service.Patch[objects.ContactGroup](nbi.NetboxAPI, oldContactGroup.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.ContactGroupsIndexByName[newContactGroup.Name] = patchedContactGroup
-		} else {
-			nbi.Logger.Debug("Contact group ", newContactGroup.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Contact group ", newContactGroup.Name, " does not exist in Netbox. Creating it...")
-		newContactGroup, err := service.Create[objects.ContactGroup](nbi.NetboxAPI, newContactGroup)
-		if err != nil {
-			return nil, err
-		}
-		nbi.ContactGroupsIndexByName[newContactGroup.Name] = newContactGroup
-	}
-	return nbi.ContactGroupsIndexByName[newContactGroup.Name], nil
+	return nbi.contactGroups.Add(newContactGroup)
 }
 
 // AddContact adds a contact to the local netbox inventory.
 func (nbi *NetboxInventory) AddContact(newContact *objects.Contact) (*objects.Contact, error) {
-	newContact.Tags = append(newContact.Tags, nbi.SsotTag)
-	if _, ok := nbi.ContactsIndexByName[newContact.Name]; ok {
-		oldContact := nbi.ContactsIndexByName[newContact.Name]
-		delete(nbi.OrphanManager[service.ContactsAPIPath], oldContact.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newContact, oldContact, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Contact ", newContact.Name, " already exists in Netbox but is out of date. Patching it... ")
-			patchedContact, err := service.Patch[objects.Contact](nbi.NetboxAPI, oldContact.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.ContactsIndexByName[newContact.Name] = patchedContact
-		} else {
-			nbi.Logger.Debug("Contact ", newContact.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Contact ", newContact.Name, " does not exist in Netbox. Creating it...")
-		createdContact, err := service.Create[objects.Contact](nbi.NetboxAPI, newContact)
-		if err != nil {
-			return nil, err
-		}
-		nbi.ContactsIndexByName[newContact.Name] = createdContact
-	}
-	return nbi.ContactsIndexByName[newContact.Name], nil
+	return nbi.contacts.Add(newContact)
 }
 
-// AddContact assignment adds a contact assignment to the local netbox inventory.
-// TODO: Make index check less code and more universal, checking each level is ugly.
+// AddContactAssignment adds a contact assignment to the local netbox inventory.
 func (nbi *NetboxInventory) AddContactAssignment(newCA *objects.ContactAssignment) (*objects.ContactAssignment, error) {
-	if nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType] == nil {
-		nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType] = make(map[int]map[int]map[int]*objects.ContactAssignment)
-	}
-	if nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType][newCA.ObjectID] == nil {
-		nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType][newCA.ObjectID] = make(map[int]map[int]*objects.ContactAssignment)
-	}
-	if nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType][newCA.ObjectID][newCA.Contact.ID] == nil {
-		nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType][newCA.ObjectID][newCA.Contact.ID] = make(map[int]*objects.ContactAssignment)
-	}
-	newCA.Tags = append(newCA.Tags, nbi.SsotTag)
-	if _, ok := nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType][newCA.ObjectID][newCA.Contact.ID][newCA.Role.ID]; ok {
-		oldCA := nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType][newCA.ObjectID][newCA.Contact.ID][newCA.Role.ID]
-		delete(nbi.OrphanManager[service.ContactAssignmentsAPIPath], oldCA.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newCA, oldCA, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("ContactAssignment ", newCA.ID, " already exists in Netbox but is out of date. Patching it... ")
-			patchedCA, err := service.Patch[objects.ContactAssignment](nbi.NetboxAPI, oldCA.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType][newCA.ObjectID][newCA.Contact.ID][newCA.Role.ID] = patchedCA
-		} else {
-			nbi.Logger.Debug("ContactAssignment ", newCA.ID, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debugf("ContactAssignment %s does not exist in Netbox. Creating it...", newCA)
-		newCA, err := service.Create[objects.ContactAssignment](nbi.NetboxAPI, newCA)
-		if err != nil {
-			return nil, err
-		}
-		nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType][newCA.ObjectID][newCA.Contact.ID][newCA.Role.ID] = newCA
-	}
-	return nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[newCA.ContentType][newCA.ObjectID][newCA.Contact.ID][newCA.Role.ID], nil
+	return nbi.contactAssignments.Add(newCA)
 }
 
 func (nbi *NetboxInventory) AddCustomField(newCf *objects.CustomField) error {
@@ -228,483 +105,281 @@ func (nbi *NetboxInventory) AddCustomField(newCf *objects.CustomField) error {
 	return nil
 }
 
+// AddClusterGroup adds the newCg to the local netbox inventory.
 func (nbi *NetboxInventory) AddClusterGroup(newCg *objects.ClusterGroup) (*objects.ClusterGroup, error) {
-	newCg.Tags = append(newCg.Tags, nbi.SsotTag)
-	if _, ok := nbi.ClusterGroupsIndexByName[newCg.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		oldCg := nbi.ClusterGroupsIndexByName[newCg.Name]
-		delete(nbi.OrphanManager[service.ClusterGroupsAPIPath], oldCg.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newCg, oldCg, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Cluster group ", newCg.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedCg, err := service.Patch[objects.ClusterGroup](nbi.NetboxAPI, oldCg.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.ClusterGroupsIndexByName[newCg.Name] = patchedCg
-		} else {
-			nbi.Logger.Debug("Cluster group ", newCg.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Cluster group ", newCg.Name, " does not exist in Netbox. Creating it...")
-		newCg, err := service.Create[objects.ClusterGroup](nbi.NetboxAPI, newCg)
-		if err != nil {
-			return nil, err
-		}
-		nbi.ClusterGroupsIndexByName[newCg.Name] = newCg
-	}
-	// Delete id from orphan manager
-	return nbi.ClusterGroupsIndexByName[newCg.Name], nil
+	return nbi.clusterGroups.Add(newCg)
 }
 
+// AddClusterType adds the newClusterType to the local netbox inventory.
 func (nbi *NetboxInventory) AddClusterType(newClusterType *objects.ClusterType) (*objects.ClusterType, error) {
-	newClusterType.Tags = append(newClusterType.Tags, nbi.SsotTag)
-	if _, ok := nbi.ClusterTypesIndexByName[newClusterType.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		oldClusterType := nbi.ClusterTypesIndexByName[newClusterType.Name]
-		delete(nbi.OrphanManager[service.ClusterTypesAPIPath], oldClusterType.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newClusterType, oldClusterType, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Cluster type ", newClusterType.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedClusterType, err := service.Patch[objects.ClusterType](nbi.NetboxAPI, oldClusterType.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.ClusterTypesIndexByName[newClusterType.Name] = patchedClusterType
-			return patchedClusterType, nil
-		}
-		nbi.Logger.Debug("Cluster type ", newClusterType.Name, " already exists in Netbox and is up to date...")
-		existingClusterType := nbi.ClusterTypesIndexByName[newClusterType.Name]
-		return existingClusterType, nil
-	}
-	nbi.Logger.Debug("Cluster type ", newClusterType.Name, " does not exist in Netbox. Creating it...")
-	newClusterType, err := service.Create[objects.ClusterType](nbi.NetboxAPI, newClusterType)
-	if err != nil {
-		return nil, err
-	}
-	nbi.ClusterTypesIndexByName[newClusterType.Name] = newClusterType
-	return newClusterType, nil
+	return nbi.clusterTypes.Add(newClusterType)
 }
 
+// AddCluster adds the newCluster to the local netbox inventory.
 func (nbi *NetboxInventory) AddCluster(newCluster *objects.Cluster) error {
-	newCluster.Tags = append(newCluster.Tags, nbi.SsotTag)
-	if _, ok := nbi.ClustersIndexByName[newCluster.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		oldCluster := nbi.ClustersIndexByName[newCluster.Name]
-		delete(nbi.OrphanManager[service.ClustersAPIPath], oldCluster.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newCluster, oldCluster, false, nbi.SourcePriority)
-		if err != nil {
-			return err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Cluster ", newCluster.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedCluster, err := service.Patch[objects.Cluster](nbi.NetboxAPI, oldCluster.ID, diffMap)
-			if err != nil {
-				return err
-			}
-			nbi.ClustersIndexByName[newCluster.Name] = patchedCluster
-		} else {
-			nbi.Logger.Debug("Cluster ", newCluster.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Cluster ", newCluster.Name, " does not exist in Netbox. Creating it...")
-		newCluster, err := service.Create[objects.Cluster](nbi.NetboxAPI, newCluster)
-		if err != nil {
-			return err
-		}
-		nbi.ClustersIndexByName[newCluster.Name] = newCluster
-	}
-	return nil
+	_, err := nbi.clusters.Add(newCluster)
+	return err
 }
 
+// AddDeviceRole adds the newDeviceRole to the local netbox inventory.
 func (nbi *NetboxInventory) AddDeviceRole(newDeviceRole *objects.DeviceRole) (*objects.DeviceRole, error) {
-	newDeviceRole.Tags = append(newDeviceRole.Tags, nbi.SsotTag)
-	if _, ok := nbi.DeviceRolesIndexByName[newDeviceRole.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		oldDeviceRole := nbi.DeviceRolesIndexByName[newDeviceRole.Name]
-		delete(nbi.OrphanManager[service.DeviceRolesAPIPath], nbi.DeviceRolesIndexByName[newDeviceRole.Name].ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newDeviceRole, oldDeviceRole, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Device role ", newDeviceRole.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedDeviceRole, err := service.Patch[objects.DeviceRole](nbi.NetboxAPI, oldDeviceRole.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.DeviceRolesIndexByName[newDeviceRole.Name] = patchedDeviceRole
-		} else {
-			nbi.Logger.Debug("Device role ", newDeviceRole.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Device role ", newDeviceRole.Name, " does not exist in Netbox. Creating it...")
-		newDeviceRole, err := service.Create[objects.DeviceRole](nbi.NetboxAPI, newDeviceRole)
-		if err != nil {
-			return nil, err
-		}
-		nbi.DeviceRolesIndexByName[newDeviceRole.Name] = newDeviceRole
-	}
-	return nbi.DeviceRolesIndexByName[newDeviceRole.Name], nil
+	return nbi.deviceRoles.Add(newDeviceRole)
 }
 
+// AddManufacturer adds the newManufacturer to the local netbox inventory.
 func (nbi *NetboxInventory) AddManufacturer(newManufacturer *objects.Manufacturer) (*objects.Manufacturer, error) {
-	newManufacturer.Tags = append(newManufacturer.Tags, nbi.SsotTag)
-	if _, ok := nbi.ManufacturersIndexByName[newManufacturer.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		oldManufacturer := nbi.ManufacturersIndexByName[newManufacturer.Name]
-		delete(nbi.OrphanManager[service.ManufacturersAPIPath], oldManufacturer.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newManufacturer, oldManufacturer, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Manufacturer ", newManufacturer.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedManufacturer, err := service.Patch[objects.Manufacturer](nbi.NetboxAPI, oldManufacturer.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.ManufacturersIndexByName[newManufacturer.Name] = patchedManufacturer
-		} else {
-			nbi.Logger.Debug("Manufacturer ", newManufacturer.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Manufacturer ", newManufacturer.Name, " does not exist in Netbox. Creating it...")
-		newManufacturer, err := service.Create[objects.Manufacturer](nbi.NetboxAPI, newManufacturer)
-		if err != nil {
-			return nil, err
-		}
-		nbi.ManufacturersIndexByName[newManufacturer.Name] = newManufacturer
-	}
-	return nbi.ManufacturersIndexByName[newManufacturer.Name], nil
+	return nbi.manufacturers.Add(newManufacturer)
 }
 
+// AddDeviceType adds the newDeviceType to the local netbox inventory.
 func (nbi *NetboxInventory) AddDeviceType(newDeviceType *objects.DeviceType) (*objects.DeviceType, error) {
-	newDeviceType.Tags = append(newDeviceType.Tags, nbi.SsotTag)
-	if _, ok := nbi.DeviceTypesIndexByModel[newDeviceType.Model]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		oldDeviceType := nbi.DeviceTypesIndexByModel[newDeviceType.Model]
-		delete(nbi.OrphanManager[service.DeviceTypesAPIPath], oldDeviceType.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newDeviceType, oldDeviceType, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Device type ", newDeviceType.Model, " already exists in Netbox but is out of date. Patching it...")
-			patchedDeviceType, err := service.Patch[objects.DeviceType](nbi.NetboxAPI, oldDeviceType.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.DeviceTypesIndexByModel[newDeviceType.Model] = patchedDeviceType
-		} else {
-			nbi.Logger.Debug("Device type ", newDeviceType.Model, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Device type ", newDeviceType.Model, " does not exist in Netbox. Creating it...")
-		newDeviceType, err := service.Create[objects.DeviceType](nbi.NetboxAPI, newDeviceType)
-		if err != nil {
-			return nil, err
-		}
-		nbi.DeviceTypesIndexByModel[newDeviceType.Model] = newDeviceType
-	}
-	return nbi.DeviceTypesIndexByModel[newDeviceType.Model], nil
+	return nbi.deviceTypes.Add(newDeviceType)
 }
 
+// AddPlatform adds the newPlatform to the local netbox inventory.
 func (nbi *NetboxInventory) AddPlatform(newPlatform *objects.Platform) (*objects.Platform, error) {
-	newPlatform.Tags = append(newPlatform.Tags, nbi.SsotTag)
-	if _, ok := nbi.PlatformsIndexByName[newPlatform.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		oldPlatform := nbi.PlatformsIndexByName[newPlatform.Name]
-		delete(nbi.OrphanManager[service.PlatformsAPIPath], oldPlatform.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newPlatform, oldPlatform, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Platform ", newPlatform.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedPlatform, err := service.Patch[objects.Platform](nbi.NetboxAPI, oldPlatform.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.PlatformsIndexByName[newPlatform.Name] = patchedPlatform
-		} else {
-			nbi.Logger.Debug("Platform ", newPlatform.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Platform ", newPlatform.Name, " does not exist in Netbox. Creating it...")
-		newPlatform, err := service.Create[objects.Platform](nbi.NetboxAPI, newPlatform)
-		if err != nil {
-			return nil, err
-		}
-		nbi.PlatformsIndexByName[newPlatform.Name] = newPlatform
+	return nbi.platforms.Add(newPlatform)
+}
+
+// sourceID returns values of the object's source and source_id custom fields,
+// which identify the object in its source. If any of them is not set, empty strings are returned.
+func sourceID(obj objects.NetboxObject) (string, string) {
+	source, _ := obj.CustomFields[constants.CustomFieldSourceName].(string)
+	id, _ := obj.CustomFields[constants.CustomFieldSourceIDName].(string)
+	if source == "" || id == "" {
+		return "", ""
 	}
-	return nbi.PlatformsIndexByName[newPlatform.Name], nil
+	return source, id
+}
+
+// GetDevice returns existing netbox device, that matches device. Devices are matched by:
+//  1. source and source_id custom fields,
+//  2. name and site,
+//  3. serial number,
+//  4. asset tag.
+//
+// That way renamed devices are matched to their existing netbox device, which is patched
+// instead of creating a new device. Devices with the same source, but different source_id
+// are never matched by serial number or asset tag.
+func (nbi *NetboxInventory) GetDevice(device *objects.Device) (*objects.Device, bool) {
+	return nbi.devices.Get(device)
 }
 
+// AddDevice adds the newDevice to the local netbox inventory. Existing device is matched with GetDevice.
 func (nbi *NetboxInventory) AddDevice(newDevice *objects.Device) (*objects.Device, error) {
-	newDevice.Tags = append(newDevice.Tags, nbi.SsotTag)
-	if _, ok := nbi.DevicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID]; ok {
-		oldDevice := nbi.DevicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID]
-		delete(nbi.OrphanManager[service.DevicesAPIPath], oldDevice.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newDevice, oldDevice, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Device ", newDevice.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedDevice, err := service.Patch[objects.Device](nbi.NetboxAPI, oldDevice.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.DevicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID] = patchedDevice
-		} else {
-			nbi.Logger.Debug("Device ", newDevice.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Device ", newDevice.Name, " does not exist in Netbox. Creating it...")
-		newDevice, err := service.Create[objects.Device](nbi.NetboxAPI, newDevice)
-		if err != nil {
-			return nil, err
-		}
-		if nbi.DevicesIndexByNameAndSiteID[newDevice.Name] == nil {
-			nbi.DevicesIndexByNameAndSiteID[newDevice.Name] = make(map[int]*objects.Device)
-		}
-		nbi.DevicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID] = newDevice
-	}
-	return nbi.DevicesIndexByNameAndSiteID[newDevice.Name][newDevice.Site.ID], nil
+	return nbi.devices.Add(newDevice)
 }
 
+// AddVlanGroup adds the newVlanGroup to the local netbox inventory.
 func (nbi *NetboxInventory) AddVlanGroup(newVlanGroup *objects.VlanGroup) (*objects.VlanGroup, error) {
-	newVlanGroup.Tags = append(newVlanGroup.Tags, nbi.SsotTag)
-	if _, ok := nbi.VlanGroupsIndexByName[newVlanGroup.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		oldVlanGroup := nbi.VlanGroupsIndexByName[newVlanGroup.Name]
-		delete(nbi.OrphanManager[service.VlanGroupsAPIPath], oldVlanGroup.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newVlanGroup, oldVlanGroup, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("VlanGroup ", newVlanGroup.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedVlanGroup, err := service.Patch[objects.VlanGroup](nbi.NetboxAPI, oldVlanGroup.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.VlanGroupsIndexByName[newVlanGroup.Name] = patchedVlanGroup
-		} else {
-			nbi.Logger.Debug("Vlan ", newVlanGroup.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Vlan ", newVlanGroup.Name, " does not exist in Netbox. Creating it...")
-		newVlan, err := service.Create[objects.VlanGroup](nbi.NetboxAPI, newVlanGroup)
-		if err != nil {
-			return nil, err
+	return nbi.vlanGroups.Add(newVlanGroup)
+}
+
+// AddVlan adds the newVlan to the local netbox inventory.
+func (nbi *NetboxInventory) AddVlan(newVlan *objects.Vlan) (*objects.Vlan, error) {
+	return nbi.vlans.Add(newVlan)
+}
+
+// GetInterface returns existing netbox interface of the same device, that matches intf.
+// Interfaces are primarily matched by their source and source_id custom fields, so renamed
+// interfaces are still matched, and then by their name.
+func (nbi *NetboxInventory) GetInterface(intf *objects.Interface) (*objects.Interface, bool) {
+	return nbi.interfaces.Get(intf)
+}
+
+// GetDeviceInterfaces returns all interfaces of the device indexed by their name. In scoped
+// init, interfaces of the device are looked up in Netbox, since the device can be outside of the scope.
+func (nbi *NetboxInventory) GetDeviceInterfaces(device *objects.Device) map[string]*objects.Interface {
+	if nbi.interfaces.scoped() {
+		if err := nbi.interfaces.collect([]string{fmt.Sprintf("&device_id=%d", device.ID)}); err != nil {
+			nbi.Logger.Warningf("failed looking up interfaces of device %s: %s", device.Name, err)
 		}
-		nbi.VlanGroupsIndexByName[newVlan.Name] = newVlan
 	}
-	return nbi.VlanGroupsIndexByName[newVlanGroup.Name], nil
+	return nbi.InterfacesIndexByDeviceIDAndName[device.ID]
 }
 
-func (nbi *NetboxInventory) AddVlan(newVlan *objects.Vlan) (*objects.Vlan, error) {
-	newVlan.Tags = append(newVlan.Tags, nbi.SsotTag)
-	if _, ok := nbi.VlansIndexByVlanGroupIDAndVID[newVlan.Group.ID][newVlan.Vid]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		oldVlan := nbi.VlansIndexByVlanGroupIDAndVID[newVlan.Group.ID][newVlan.Vid]
-		delete(nbi.OrphanManager[service.VlansAPIPath], oldVlan.ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newVlan, oldVlan, false, nbi.SourcePriority)
-		if err != nil {
-			return nil, err
+// GetMgmtIPAddresses returns ip addresses within subnets, that are assigned to management
+// interfaces (mgmt_only) of devices. In scoped init, management interfaces and ip addresses
+// within subnets are looked up in Netbox, since their devices can be outside of the scope.
+func (nbi *NetboxInventory) GetMgmtIPAddresses(subnets []string) ([]*objects.IPAddress, error) {
+	if nbi.interfaces.scoped() {
+		if err := nbi.interfaces.collect([]string{"&mgmt_only=true"}); err != nil {
+			return nil, fmt.Errorf("management interfaces: %s", err)
 		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Vlan ", newVlan.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedVlan, err := service.Patch[objects.Vlan](nbi.NetboxAPI, oldVlan.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.VlansIndexByVlanGroupIDAndVID[newVlan.Group.ID][newVlan.Vid] = patchedVlan
-		} else {
-			nbi.Logger.Debug("Vlan ", newVlan.Name, " already exists in Netbox and is up to date...")
+	}
+	if nbi.ipAddresses.scoped() {
+		queries := make([]string, 0, len(subnets))
+		for _, subnet := range subnets {
+			queries = append(queries, "&assigned_object_type="+objects.AssignedObjectTypeDeviceInterface+queryParam("parent", subnet))
 		}
-	} else {
-		nbi.Logger.Debug("Vlan ", newVlan.Name, " does not exist in Netbox. Creating it...")
-		newVlan, err := service.Create[objects.Vlan](nbi.NetboxAPI, newVlan)
-		if err != nil {
-			return nil, err
+		if err := nbi.ipAddresses.collect(queries); err != nil {
+			return nil, fmt.Errorf("management ip addresses: %s", err)
+		}
+	}
+	mgmtInterfaces := make(map[int]bool)
+	for _, nbInterface := range nbi.interfaces.All() {
+		if nbInterface.MgmtOnly {
+			mgmtInterfaces[nbInterface.ID] = true
+		}
+	}
+	mgmtIPAddresses := make([]*objects.IPAddress, 0)
+	for _, ipAddress := range nbi.ipAddresses.All() {
+		if ipAddress.AssignedObjectType != objects.AssignedObjectTypeDeviceInterface || !mgmtInterfaces[ipAddress.AssignedObjectID] {
+			continue
 		}
-		if nbi.VlansIndexByVlanGroupIDAndVID[newVlan.Group.ID] == nil {
-			nbi.VlansIndexByVlanGroupIDAndVID[newVlan.Group.ID] = make(map[int]*objects.Vlan)
+		address := strings.Split(ipAddress.Address, "/")[0]
+		if slices.ContainsFunc(subnets, func(subnet string) bool { return utils.SubnetContainsIPAddress(address, subnet) }) {
+			mgmtIPAddresses = append(mgmtIPAddresses, ipAddress)
 		}
-		nbi.VlansIndexByVlanGroupIDAndVID[newVlan.Group.ID][newVlan.Vid] = newVlan
 	}
-	return nbi.VlansIndexByVlanGroupIDAndVID[newVlan.Group.ID][newVlan.Vid], nil
+	return mgmtIPAddresses, nil
 }
 
+// AddInterface adds the newInterface to the local netbox inventory. Existing interface is matched with GetInterface.
 func (nbi *NetboxInventory) AddInterface(newInterface *objects.Interface) (*objects.Interface, error) {
-	newInterface.Tags = append(newInterface.Tags, nbi.SsotTag)
-	if _, ok := nbi.InterfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		delete(nbi.OrphanManager[service.InterfacesAPIPath], nbi.InterfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name].ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newInterface, nbi.InterfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name], false, nbi.SourcePriority)
-		oldIntf := nbi.InterfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name]
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Interface ", newInterface.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedInterface, err := service.Patch[objects.Interface](nbi.NetboxAPI, oldIntf.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.InterfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name] = patchedInterface
-		} else {
-			nbi.Logger.Debug("Interface ", newInterface.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("Interface ", newInterface.Name, " does not exist in Netbox. Creating it...")
-		newInterface, err := service.Create[objects.Interface](nbi.NetboxAPI, newInterface)
-		if err != nil {
-			return nil, err
-		}
-		if nbi.InterfacesIndexByDeviceIDAndName[newInterface.Device.ID] == nil {
-			nbi.InterfacesIndexByDeviceIDAndName[newInterface.Device.ID] = make(map[string]*objects.Interface)
+	return nbi.interfaces.Add(newInterface)
+}
+
+// AddPowerPort adds the newPowerPort to the local netbox inventory.
+func (nbi *NetboxInventory) AddPowerPort(newPowerPort *objects.PowerPort) (*objects.PowerPort, error) {
+	return nbi.powerPorts.Add(newPowerPort)
+}
+
+// AddVirtualChassis adds the newVirtualChassis to the local netbox inventory.
+func (nbi *NetboxInventory) AddVirtualChassis(newVirtualChassis *objects.VirtualChassis) (*objects.VirtualChassis, error) {
+	return nbi.virtualChassis.Add(newVirtualChassis)
+}
+
+// AddInventoryItem adds the newInventoryItem to the local netbox inventory.
+func (nbi *NetboxInventory) AddInventoryItem(newInventoryItem *objects.InventoryItem) (*objects.InventoryItem, error) {
+	return nbi.inventoryItems.Add(newInventoryItem)
+}
+
+// AddCable adds cable between two interfaces. If any of the interfaces is
+// already connected with the cable, that cable is updated instead.
+//
+// Netbox refuses to connect an interface, that already has a cable. So when both
+// interfaces are connected with different cables, the cable of the bInterface is
+// deleted, if it was created by netbox-ssot. Cables created by others are kept,
+// and the link is skipped, so existing cable of the bInterface is returned.
+func (nbi *NetboxInventory) AddCable(aInterface, bInterface *objects.Interface, newCable *objects.Cable) (*objects.Cable, error) {
+	newCable.ATerminations = []*objects.CableTermination{{ObjectType: objects.CableTerminationTypeInterface, ObjectID: aInterface.ID}}
+	newCable.BTerminations = []*objects.CableTermination{{ObjectType: objects.CableTerminationTypeInterface, ObjectID: bInterface.ID}}
+	aCable, aOk := nbi.cables.Get(&objects.Cable{ATerminations: newCable.ATerminations})
+	bCable, bOk := nbi.cables.Get(&objects.Cable{BTerminations: newCable.BTerminations})
+	if aOk && bOk && aCable.ID != bCable.ID {
+		if !hasTag(bCable.Tags, nbi.SsotTag) {
+			nbi.Logger.Warningf("Interface %s is already connected with cable %d, that is not managed by netbox-ssot. Skipping cable to interface %s...", bInterface.Name, bCable.ID, aInterface.Name)
+			return bCable, nil
+		}
+		nbi.Logger.Infof("Interface %s was reconnected to interface %s in the source. Deleting its old cable %d...", bInterface.Name, aInterface.Name, bCable.ID)
+		if err := nbi.cables.delete(bCable); err != nil {
+			return nil, fmt.Errorf("deleting conflicting cable %d: %s", bCable.ID, err)
 		}
-		nbi.InterfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name] = newInterface
 	}
-	return nbi.InterfacesIndexByDeviceIDAndName[newInterface.Device.ID][newInterface.Name], nil
+	return nbi.cables.Add(newCable)
+}
+
+// cableDiff returns diff of the newCable and the oldCable. Terminations don't have ids,
+// so they are compared separately, and are patched only if the cable is reconnected.
+func (nbi *NetboxInventory) cableDiff(newCable, oldCable *objects.Cable) (map[string]interface{}, error) {
+	newCableAttrs, oldCableAttrs := *newCable, *oldCable
+	newCableAttrs.ATerminations, newCableAttrs.BTerminations = nil, nil
+	oldCableAttrs.ATerminations, oldCableAttrs.BTerminations = nil, nil
+	diffMap, err := utils.JSONDiffMapExceptID(&newCableAttrs, &oldCableAttrs, false, nbi.SourcePriority)
+	if err != nil {
+		return nil, err
+	}
+	if !cableConnects(oldCable, newCable.ATerminations[0].ObjectID, newCable.BTerminations[0].ObjectID) {
+		diffMap["a_terminations"] = newCable.ATerminations
+		diffMap["b_terminations"] = newCable.BTerminations
+	}
+	return diffMap, nil
+}
+
+// cableConnects returns true if cable connects exactly the given interfaces.
+func cableConnects(cable *objects.Cable, aInterfaceID, bInterfaceID int) bool {
+	if len(cable.ATerminations) != 1 || len(cable.BTerminations) != 1 {
+		return false
+	}
+	a, b := cable.ATerminations[0], cable.BTerminations[0]
+	if a.ObjectType != objects.CableTerminationTypeInterface || b.ObjectType != objects.CableTerminationTypeInterface {
+		return false
+	}
+	return (a.ObjectID == aInterfaceID && b.ObjectID == bInterfaceID) || (a.ObjectID == bInterfaceID && b.ObjectID == aInterfaceID)
+}
+
+// clusterID returns id of the cluster, which is used as a key of cluster aware indexes.
+// Objects without cluster have key 0.
+func clusterID(cluster *objects.Cluster) int {
+	if cluster == nil {
+		return 0
+	}
+	return cluster.ID
 }
 
+// GetVM returns existing netbox vm, that matches vm. Vms are primarily matched by their
+// source and source_id custom fields, so renamed vms are still matched, and then by
+// their cluster and name.
+func (nbi *NetboxInventory) GetVM(vm *objects.VM) (*objects.VM, bool) {
+	return nbi.vms.Get(vm)
+}
+
+// AddVM adds the newVM to the local netbox inventory. Existing vm is matched with GetVM.
 func (nbi *NetboxInventory) AddVM(newVM *objects.VM) (*objects.VM, error) {
-	newVM.Tags = append(newVM.Tags, nbi.SsotTag)
-	if _, ok := nbi.VMsIndexByName[newVM.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		delete(nbi.OrphanManager[service.VirtualMachinesAPIPath], nbi.VMsIndexByName[newVM.Name].ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newVM, nbi.VMsIndexByName[newVM.Name], false, nbi.SourcePriority)
-		oldVM := nbi.VMsIndexByName[newVM.Name]
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("VM ", newVM.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedVM, err := service.Patch[objects.VM](nbi.NetboxAPI, oldVM.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.VMsIndexByName[newVM.Name] = patchedVM
-		} else {
-			nbi.Logger.Debug("VM ", newVM.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("VM ", newVM.Name, " does not exist in Netbox. Creating it...")
-		newVM, err := service.Create[objects.VM](nbi.NetboxAPI, newVM)
-		if err != nil {
-			return nil, err
-		}
-		nbi.VMsIndexByName[newVM.Name] = newVM
-		return newVM, nil
+	return nbi.vms.Add(newVM)
+}
+
+// vmDiff returns diff of the newVM and the oldVM. Disk size of a vm with virtual disks is
+// computed by Netbox from its virtual disks, and Netbox rejects disk sizes, that don't match
+// the sum of the existing virtual disks. That is why disk is never patched on such vms, and
+// changed disk sizes are synced by patching their virtual disks instead.
+func (nbi *NetboxInventory) vmDiff(newVM, oldVM *objects.VM) (map[string]interface{}, error) {
+	diffMap, err := utils.JSONDiffMapExceptID(newVM, oldVM, false, nbi.SourcePriority)
+	if err != nil {
+		return nil, err
 	}
-	return nbi.VMsIndexByName[newVM.Name], nil
+	if len(nbi.VirtualDisksIndexByVMIDAndName[oldVM.ID]) > 0 {
+		delete(diffMap, "disk")
+	}
+	return diffMap, nil
 }
 
+// AddVMInterface adds the newVMInterface to the local netbox inventory.
 func (nbi *NetboxInventory) AddVMInterface(newVMInterface *objects.VMInterface) (*objects.VMInterface, error) {
-	newVMInterface.Tags = append(newVMInterface.Tags, nbi.SsotTag)
-	if _, ok := nbi.VMInterfacesIndexByVMIdAndName[newVMInterface.VM.ID][newVMInterface.Name]; ok {
-		// Remove id from orphan manager, because it still exists in the sources
-		delete(nbi.OrphanManager[service.VMInterfacesAPIPath], nbi.VMInterfacesIndexByVMIdAndName[newVMInterface.VM.ID][newVMInterface.Name].ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newVMInterface, nbi.VMInterfacesIndexByVMIdAndName[newVMInterface.VM.ID][newVMInterface.Name], false, nbi.SourcePriority)
-		oldVMIface := nbi.VMInterfacesIndexByVMIdAndName[newVMInterface.VM.ID][newVMInterface.Name]
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("VM interface ", newVMInterface.Name, " already exists in Netbox but is out of date. Patching it...")
-			patchedVMInterface, err := service.Patch[objects.VMInterface](nbi.NetboxAPI, oldVMIface.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.VMInterfacesIndexByVMIdAndName[newVMInterface.VM.ID][newVMInterface.Name] = patchedVMInterface
-		} else {
-			nbi.Logger.Debug("VM interface ", newVMInterface.Name, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("VM interface ", newVMInterface.Name, " does not exist in Netbox. Creating it...")
-		newVMInterface, err := service.Create[objects.VMInterface](nbi.NetboxAPI, newVMInterface)
-		if err != nil {
-			return nil, err
-		}
-		if nbi.VMInterfacesIndexByVMIdAndName[newVMInterface.VM.ID] == nil {
-			nbi.VMInterfacesIndexByVMIdAndName[newVMInterface.VM.ID] = make(map[string]*objects.VMInterface)
-		}
-		nbi.VMInterfacesIndexByVMIdAndName[newVMInterface.VM.ID][newVMInterface.Name] = newVMInterface
+	return nbi.vmInterfaces.Add(newVMInterface)
+}
+
+// AddVirtualDisk adds the newVirtualDisk to the local netbox inventory.
+func (nbi *NetboxInventory) AddVirtualDisk(newVirtualDisk *objects.VirtualDisk) (*objects.VirtualDisk, error) {
+	return nbi.virtualDisks.Add(newVirtualDisk)
+}
+
+// vrfID returns id of the vrf, which is used as a key of VRF aware indexes.
+// IP addresses and prefixes without VRF (global table) have key 0.
+func vrfID(vrf *objects.VRF) int {
+	if vrf == nil {
+		return 0
 	}
-	return nbi.VMInterfacesIndexByVMIdAndName[newVMInterface.VM.ID][newVMInterface.Name], nil
+	return vrf.ID
+}
+
+// AddVRF adds the newVRF to the local netbox inventory.
+func (nbi *NetboxInventory) AddVRF(newVRF *objects.VRF) (*objects.VRF, error) {
+	return nbi.vrfs.Add(newVRF)
 }
 
+// AddIPAddress adds the newIPAddress to the local netbox inventory.
 func (nbi *NetboxInventory) AddIPAddress(newIPAddress *objects.IPAddress) (*objects.IPAddress, error) {
-	newIPAddress.Tags = append(newIPAddress.Tags, nbi.SsotTag)
-	if _, ok := nbi.IPAdressesIndexByAddress[newIPAddress.Address]; ok {
-		// Delete id from orphan manager, because it still exists in the sources
-		delete(nbi.OrphanManager[service.IPAddressesAPIPath], nbi.IPAdressesIndexByAddress[newIPAddress.Address].ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newIPAddress, nbi.IPAdressesIndexByAddress[newIPAddress.Address], false, nbi.SourcePriority)
-		oldIPAddress := nbi.IPAdressesIndexByAddress[newIPAddress.Address]
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("IP address ", newIPAddress.Address, " already exists in Netbox but is out of date. Patching it...")
-			patchedIPAddress, err := service.Patch[objects.IPAddress](nbi.NetboxAPI, oldIPAddress.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.IPAdressesIndexByAddress[newIPAddress.Address] = patchedIPAddress
-		} else {
-			nbi.Logger.Debug("IP address ", newIPAddress.Address, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("IP address ", newIPAddress.Address, " does not exist in Netbox. Creating it...")
-		newIPAddress, err := service.Create[objects.IPAddress](nbi.NetboxAPI, newIPAddress)
-		if err != nil {
-			return nil, err
-		}
-		nbi.IPAdressesIndexByAddress[newIPAddress.Address] = newIPAddress
-		return newIPAddress, nil
-	}
-	return nbi.IPAdressesIndexByAddress[newIPAddress.Address], nil
+	return nbi.ipAddresses.Add(newIPAddress)
 }
 
+// AddPrefix adds the newPrefix to the local netbox inventory.
 func (nbi *NetboxInventory) AddPrefix(newPrefix *objects.Prefix) (*objects.Prefix, error) {
-	newPrefix.Tags = append(newPrefix.Tags, nbi.SsotTag)
-	if _, ok := nbi.PrefixesIndexByPrefix[newPrefix.Prefix]; ok {
-		// Delete id from orphan manager, because it still exists in the sources
-		delete(nbi.OrphanManager[service.PrefixesAPIPath], nbi.PrefixesIndexByPrefix[newPrefix.Prefix].ID)
-		diffMap, err := utils.JSONDiffMapExceptID(newPrefix, nbi.PrefixesIndexByPrefix[newPrefix.Prefix], false, nbi.SourcePriority)
-		oldPrefix := nbi.PrefixesIndexByPrefix[newPrefix.Prefix]
-		if err != nil {
-			return nil, err
-		}
-		if len(diffMap) > 0 {
-			nbi.Logger.Debug("Prefix ", newPrefix.Prefix, " already exists in Netbox but is out of date. Patching it...")
-			patchedPrefix, err := service.Patch[objects.Prefix](nbi.NetboxAPI, oldPrefix.ID, diffMap)
-			if err != nil {
-				return nil, err
-			}
-			nbi.PrefixesIndexByPrefix[newPrefix.Prefix] = patchedPrefix
-		} else {
-			nbi.Logger.Debug("IP address ", newPrefix.Prefix, " already exists in Netbox and is up to date...")
-		}
-	} else {
-		nbi.Logger.Debug("IP address ", newPrefix.Prefix, " does not exist in Netbox. Creating it...")
-		newPrefix, err := service.Create[objects.Prefix](nbi.NetboxAPI, newPrefix)
-		if err != nil {
-			return nil, err
-		}
-		nbi.PrefixesIndexByPrefix[newPrefix.Prefix] = newPrefix
-		return newPrefix, nil
-	}
-	return nbi.PrefixesIndexByPrefix[newPrefix.Prefix], nil
+	return nbi.prefixes.Add(newPrefix)
+}
+
+// AddWirelessLAN adds the newWirelessLAN to the local netbox inventory.
+func (nbi *NetboxInventory) AddWirelessLAN(newWirelessLAN *objects.WirelessLAN) (*objects.WirelessLAN, error) {
+	return nbi.wirelessLANs.Add(newWirelessLAN)
 }
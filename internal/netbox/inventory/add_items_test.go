@@ -0,0 +1,98 @@
+package inventory
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+)
+
+func TestAddVMWithResizedDisk(t *testing.T) {
+	patches := map[string]map[string]interface{}{}
+	nbi := newTestInventory(t, &parser.NetboxConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		patches[r.URL.Path] = body
+		switch {
+		case strings.HasPrefix(r.URL.Path, service.VirtualMachinesAPIPath):
+			_ = json.NewEncoder(w).Encode(objects.VM{NetboxObject: objects.NetboxObject{ID: 1}, Name: "vm1", Disk: 30})
+		case strings.HasPrefix(r.URL.Path, service.VirtualDisksAPIPath):
+			_ = json.NewEncoder(w).Encode(objects.VirtualDisk{NetboxObject: objects.NetboxObject{ID: 2}, VM: &objects.VM{NetboxObject: objects.NetboxObject{ID: 1}}, Name: "Disk 1", Size: 40})
+		}
+	})
+	oldVM := &objects.VM{NetboxObject: objects.NetboxObject{ID: 1, Tags: []*objects.Tag{nbi.SsotTag}}, Name: "vm1", Disk: 30}
+	nbi.vms.put(oldVM)
+	nbi.virtualDisks.put(&objects.VirtualDisk{NetboxObject: objects.NetboxObject{ID: 2, Tags: []*objects.Tag{nbi.SsotTag}}, VM: oldVM, Name: "Disk 1", Size: 30})
+
+	// Disk was resized from 30 to 40 GB, and vm's description has changed
+	newVM, err := nbi.AddVM(&objects.VM{NetboxObject: objects.NetboxObject{Description: "resized"}, Name: "vm1", Disk: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nbi.AddVirtualDisk(&objects.VirtualDisk{VM: newVM, Name: "Disk 1", Size: 40}); err != nil {
+		t.Fatal(err)
+	}
+
+	vmPatch := patches[service.VirtualMachinesAPIPath+"1/"]
+	if _, ok := vmPatch["disk"]; ok || vmPatch["description"] != "resized" {
+		t.Errorf("vm patch = %v, want only description without disk", vmPatch)
+	}
+	if diskPatch := patches[service.VirtualDisksAPIPath+"2/"]; diskPatch["size"] != float64(40) {
+		t.Errorf("virtual disk patch = %v, want size 40", diskPatch)
+	}
+}
+
+func TestAddCableReconnected(t *testing.T) {
+	interfaceTermination := func(id int) []*objects.CableTermination {
+		return []*objects.CableTermination{{ObjectType: objects.CableTerminationTypeInterface, ObjectID: id}}
+	}
+	for _, managed := range []bool{true, false} {
+		var requests []string
+		nbi := newTestInventory(t, &parser.NetboxConfig{}, func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			switch r.Method {
+			case http.MethodDelete:
+				w.WriteHeader(http.StatusNoContent)
+			case http.MethodPatch:
+				_ = json.NewEncoder(w).Encode(objects.Cable{NetboxObject: objects.NetboxObject{ID: 1}, ATerminations: interfaceTermination(1), BTerminations: interfaceTermination(2)})
+			}
+		})
+		// Interface 2 was moved from interface 4 to interface 1, which was connected to interface 3
+		nbi.cables.put(&objects.Cable{NetboxObject: objects.NetboxObject{ID: 1, Tags: []*objects.Tag{nbi.SsotTag}}, ATerminations: interfaceTermination(1), BTerminations: interfaceTermination(3)})
+		oldCable := &objects.Cable{NetboxObject: objects.NetboxObject{ID: 2}, ATerminations: interfaceTermination(2), BTerminations: interfaceTermination(4)}
+		if managed {
+			oldCable.Tags = []*objects.Tag{nbi.SsotTag}
+		}
+		nbi.cables.put(oldCable)
+
+		cable, err := nbi.AddCable(&objects.Interface{NetboxObject: objects.NetboxObject{ID: 1}}, &objects.Interface{NetboxObject: objects.NetboxObject{ID: 2}}, &objects.Cable{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if managed {
+			expected := []string{http.MethodDelete + " " + service.CablesAPIPath, http.MethodPatch + " " + service.CablesAPIPath + "1/"}
+			if !reflect.DeepEqual(requests, expected) {
+				t.Errorf("requests = %v, want %v", requests, expected)
+			}
+			if cable.ID != 1 || nbi.CablesIndexByInterfaceID[2] != cable || nbi.CablesIndexByInterfaceID[4] != nil {
+				t.Errorf("cables by interface = %v, want only cable 1 on interfaces 1 and 2", nbi.CablesIndexByInterfaceID)
+			}
+			continue
+		}
+		// Cables, that are not managed by netbox-ssot are kept, and the link is skipped
+		if len(requests) != 0 || cable != oldCable {
+			t.Errorf("AddCable() = %v with requests %v, want existing cable 2 without requests", cable, requests)
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package inventory
 
+import (
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+)
+
 func (nbi *NetboxInventory) DeleteOrphans() error {
 	// Ensure OrphanObjectPriority and OrphanManager lengths are the same,
 	// if not, there are missing entries somewhere and need to be fixed.
@@ -21,3 +26,39 @@ func (nbi *NetboxInventory) DeleteOrphans() error {
 	}
 	return nil
 }
+
+// RetainVMs removes vms and objects that depend on them (interfaces, ip addresses,
+// virtual disks, platforms and contacts) from the orphan manager.
+//
+// It is used by sources that sync incrementally, for vms that haven't changed since
+// the previous run and are therefore not added to the inventory again.
+func (nbi *NetboxInventory) RetainVMs(vms []*objects.VM) {
+	vmInterfaceIDs := make(map[int]bool)
+	for _, vm := range vms {
+		delete(nbi.OrphanManager[service.VirtualMachinesAPIPath], vm.ID)
+		if vm.Platform != nil {
+			delete(nbi.OrphanManager[service.PlatformsAPIPath], vm.Platform.ID)
+		}
+		for _, vmInterface := range nbi.VMInterfacesIndexByVMIdAndName[vm.ID] {
+			delete(nbi.OrphanManager[service.VMInterfacesAPIPath], vmInterface.ID)
+			vmInterfaceIDs[vmInterface.ID] = true
+		}
+		for _, virtualDisk := range nbi.VirtualDisksIndexByVMIDAndName[vm.ID] {
+			delete(nbi.OrphanManager[service.VirtualDisksAPIPath], virtualDisk.ID)
+		}
+		for contactID, contactAssignments := range nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID["virtualization.virtualmachine"][vm.ID] {
+			delete(nbi.OrphanManager[service.ContactsAPIPath], contactID)
+			for _, contactAssignment := range contactAssignments {
+				delete(nbi.OrphanManager[service.ContactAssignmentsAPIPath], contactAssignment.ID)
+			}
+		}
+	}
+	// Ip addresses are indexed by address, so we go through them only once
+	for _, vrfIPAddresses := range nbi.IPAddressesIndexByVRFIDAndAddress {
+		for _, ipAddress := range vrfIPAddresses {
+			if ipAddress.AssignedObjectType == objects.AssignedObjectTypeVMInterface && vmInterfaceIDs[ipAddress.AssignedObjectID] {
+				delete(nbi.OrphanManager[service.IPAddressesAPIPath], ipAddress.ID)
+			}
+		}
+	}
+}
@@ -2,7 +2,6 @@ package inventory
 
 import (
 	"fmt"
-	"slices"
 
 	"github.com/bl4ko/netbox-ssot/internal/constants"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
@@ -43,87 +42,22 @@ func (nbi *NetboxInventory) InitTags() error {
 
 // Collects all tenants from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) InitTenants() error {
-	nbTenants, err := service.GetAll[objects.Tenant](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// We also create an index of tenants by name for easier access
-	nbi.TenantsIndexByName = make(map[string]*objects.Tenant)
-	for i := range nbTenants {
-		tenant := &nbTenants[i]
-		nbi.TenantsIndexByName[tenant.Name] = tenant
-	}
-	nbi.Logger.Debug("Successfully collected tenants from Netbox: ", nbi.TenantsIndexByName)
-	return nil
+	return nbi.tenants.Init()
 }
 
 // Collects all contacts from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) InitContacts() error {
-	nbContacts, err := service.GetAll[objects.Contact](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// We also create an index of contacts by name for easier access
-	nbi.ContactsIndexByName = make(map[string]*objects.Contact)
-	nbi.OrphanManager[service.ContactsAPIPath] = make(map[int]bool, len(nbContacts))
-	for i := range nbContacts {
-		contact := &nbContacts[i]
-		nbi.ContactsIndexByName[contact.Name] = contact
-		if slices.IndexFunc(contact.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.ContactsAPIPath][contact.ID] = true
-		}
-	}
-	nbi.Logger.Debug("Successfully collected contacts from Netbox: ", nbi.ContactsIndexByName)
-	return nil
+	return nbi.contacts.Init()
 }
 
 // Collects all contact roles from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) InitContactRoles() error {
-	nbContactRoles, err := service.GetAll[objects.ContactRole](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// We also create an index of contact roles by name for easier access
-	nbi.ContactRolesIndexByName = make(map[string]*objects.ContactRole)
-	for i := range nbContactRoles {
-		contactRole := &nbContactRoles[i]
-		nbi.ContactRolesIndexByName[contactRole.Name] = contactRole
-	}
-	nbi.Logger.Debug("Successfully collected ContactRoles from Netbox: ", nbi.ContactRolesIndexByName)
-	return nil
+	return nbi.contactRoles.Init()
 }
 
+// Collects all contact assignments from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) InitContactAssignments() error {
-	nbCAs, err := service.GetAll[objects.ContactAssignment](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// We also create an index of contacts by name for easier access
-	nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID = make(map[string]map[int]map[int]map[int]*objects.ContactAssignment)
-	nbi.OrphanManager[service.ContactAssignmentsAPIPath] = make(map[int]bool, len(nbCAs))
-	debugIDs := map[int]bool{} // Netbox pagination bug duplicates
-	for i := range nbCAs {
-		cA := &nbCAs[i]
-		if _, ok := debugIDs[cA.ID]; ok {
-			fmt.Printf("Already been here: %d", cA.ID)
-		}
-		debugIDs[cA.ID] = true
-		if nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[cA.ContentType] == nil {
-			nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[cA.ContentType] = make(map[int]map[int]map[int]*objects.ContactAssignment)
-		}
-		if nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[cA.ContentType][cA.ObjectID] == nil {
-			nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[cA.ContentType][cA.ObjectID] = make(map[int]map[int]*objects.ContactAssignment)
-		}
-		if nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[cA.ContentType][cA.ObjectID][cA.Contact.ID] == nil {
-			nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[cA.ContentType][cA.ObjectID][cA.Contact.ID] = make(map[int]*objects.ContactAssignment)
-		}
-		nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[cA.ContentType][cA.ObjectID][cA.Contact.ID][cA.Role.ID] = cA
-		if slices.IndexFunc(cA.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.ContactAssignmentsAPIPath][cA.ID] = true
-		}
-	}
-	nbi.Logger.Debug("Successfully collected contacts from Netbox: ", nbi.ContactsIndexByName)
-	return nil
+	return nbi.contactAssignments.Init()
 }
 
 // Initializes default admin contact role used for adding admin contacts of vms.
@@ -131,7 +65,7 @@ func (nbi *NetboxInventory) InitAdminContactRole() error {
 	_, err := nbi.AddContactRole(&objects.ContactRole{
 		NetboxObject: objects.NetboxObject{
 			Description: "Auto generated contact role by netbox-ssot for admins of vms.",
-			CustomFields: map[string]string{
+			CustomFields: map[string]interface{}{
 				constants.CustomFieldSourceName: nbi.SsotTag.Name,
 			},
 		},
@@ -146,128 +80,45 @@ func (nbi *NetboxInventory) InitAdminContactRole() error {
 
 // Collects all contact groups from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) InitContactGroups() error {
-	nbContactGroups, err := service.GetAll[objects.ContactGroup](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// We also create an index of contact groups by name for easier access
-	nbi.ContactGroupsIndexByName = make(map[string]*objects.ContactGroup)
-	for i := range nbContactGroups {
-		contactGroup := &nbContactGroups[i]
-		nbi.ContactGroupsIndexByName[contactGroup.Name] = contactGroup
-	}
-	nbi.Logger.Debug("Successfully collected ContactGroups from Netbox: ", nbi.ContactGroupsIndexByName)
-	return nil
+	return nbi.contactGroups.Init()
+}
+
+// Collects all regions from Netbox API and stores them in the
+// NetBoxInventory.RegionsIndexByParentIDAndName.
+func (nbi *NetboxInventory) InitRegions() error {
+	return nbi.regions.Init()
 }
 
 // Collects all sites from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) InitSites() error {
-	nbSites, err := service.GetAll[objects.Site](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// We also create an index of sites by name for easier access
-	nbi.SitesIndexByName = make(map[string]*objects.Site)
-	for i := range nbSites {
-		site := &nbSites[i]
-		nbi.SitesIndexByName[site.Name] = site
-	}
-	nbi.Logger.Debug("Successfully collected sites from Netbox: ", nbi.SitesIndexByName)
-	return nil
+	return nbi.sites.Init()
+}
+
+// Collects all locations from Netbox API and stores them in the
+// NetBoxInventory.LocationsIndexBySiteIDAndName.
+func (nbi *NetboxInventory) InitLocations() error {
+	return nbi.locations.Init()
 }
 
 // Collects all manufacturers from Netbox API and store them in NetBoxInventory.
 func (nbi *NetboxInventory) InitManufacturers() error {
-	nbManufacturers, err := service.GetAll[objects.Manufacturer](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// Initialize internal index of manufacturers by name
-	nbi.ManufacturersIndexByName = make(map[string]*objects.Manufacturer)
-	// OrphanManager takes care of all manufacturers created by netbox-ssot
-	nbi.OrphanManager[service.ManufacturersAPIPath] = make(map[int]bool)
-
-	for i := range nbManufacturers {
-		manufacturer := &nbManufacturers[i]
-		nbi.ManufacturersIndexByName[manufacturer.Name] = manufacturer
-		if slices.IndexFunc(manufacturer.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.ManufacturersAPIPath][manufacturer.ID] = true
-		}
-	}
-
-	nbi.Logger.Debug("Successfully collected manufacturers from Netbox: ", nbi.ManufacturersIndexByName)
-	return nil
+	return nbi.manufacturers.Init()
 }
 
 // Collects all platforms from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) InitPlatforms() error {
-	nbPlatforms, err := service.GetAll[objects.Platform](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// Initialize internal index of platforms by name
-	nbi.PlatformsIndexByName = make(map[string]*objects.Platform)
-	// OrphanManager takes care of all platforms created by netbox-ssot
-	nbi.OrphanManager[service.PlatformsAPIPath] = make(map[int]bool, 0)
-
-	for i, platform := range nbPlatforms {
-		nbi.PlatformsIndexByName[platform.Name] = &nbPlatforms[i]
-		if slices.IndexFunc(platform.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.PlatformsAPIPath][platform.ID] = true
-		}
-	}
-
-	nbi.Logger.Debug("Successfully collected platforms from Netbox: ", nbi.PlatformsIndexByName)
-	return nil
+	return nbi.platforms.Init()
 }
 
 // Collect all devices from Netbox API and store them in the NetBoxInventory.
 func (nbi *NetboxInventory) InitDevices() error {
-	nbDevices, err := service.GetAll[objects.Device](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// Initialize internal index of devices by Name and SiteId
-	nbi.DevicesIndexByNameAndSiteID = make(map[string]map[int]*objects.Device)
-	// OrphanManager takes care of all devices created by netbox-ssot
-	nbi.OrphanManager[service.DevicesAPIPath] = make(map[int]bool)
-
-	for i, device := range nbDevices {
-		if nbi.DevicesIndexByNameAndSiteID[device.Name] == nil {
-			nbi.DevicesIndexByNameAndSiteID[device.Name] = make(map[int]*objects.Device)
-		}
-		nbi.DevicesIndexByNameAndSiteID[device.Name][device.Site.ID] = &nbDevices[i]
-		if slices.IndexFunc(device.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.DevicesAPIPath][device.ID] = true
-		}
-	}
-
-	nbi.Logger.Debug("Successfully collected devices from Netbox: ", nbi.DevicesIndexByNameAndSiteID)
-	return nil
+	return nbi.devices.Init()
 }
 
 // Collects all deviceRoles from Netbox API and store them in the
 // NetBoxInventory.
 func (nbi *NetboxInventory) InitDeviceRoles() error {
-	nbDeviceRoles, err := service.GetAll[objects.DeviceRole](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-	// We also create an index of device roles by name for easier access
-	nbi.DeviceRolesIndexByName = make(map[string]*objects.DeviceRole)
-	// OrphanManager takes care of all device roles created by netbox-ssot
-	nbi.OrphanManager[service.DeviceRolesAPIPath] = make(map[int]bool, 0)
-
-	for i := range nbDeviceRoles {
-		deviceRole := &nbDeviceRoles[i]
-		nbi.DeviceRolesIndexByName[deviceRole.Name] = deviceRole
-		if slices.IndexFunc(deviceRole.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.DeviceRolesAPIPath][deviceRole.ID] = true
-		}
-	}
-
-	nbi.Logger.Debug("Successfully collected device roles from Netbox: ", nbi.DeviceRolesIndexByName)
-	return nil
+	return nbi.deviceRoles.Init()
 }
 
 // Ensures that attribute ServerDeviceRole is proper initialized.
@@ -310,7 +161,7 @@ func (nbi *NetboxInventory) InitSsotCustomFields() error {
 		DisplayWeight:         objects.DisplayWeightDefault,
 		Description:           constants.CustomFieldSourceDescription,
 		SearchWeight:          objects.SearchWeightDefault,
-		ContentTypes:          []string{"dcim.device", "dcim.devicerole", "dcim.devicetype", "dcim.interface", "dcim.location", "dcim.manufacturer", "dcim.platform", "dcim.region", "dcim.site", "ipam.ipaddress", "ipam.vlangroup", "ipam.vlan", "ipam.prefix", "tenancy.tenantgroup", "tenancy.tenant", "tenancy.contact", "tenancy.contactassignment", "tenancy.contactgroup", "tenancy.contactrole", "virtualization.cluster", "virtualization.clustergroup", "virtualization.clustertype", "virtualization.virtualmachine", "virtualization.vminterface"},
+		ContentTypes:          []string{"dcim.cable", "dcim.device", "dcim.devicerole", "dcim.devicetype", "dcim.interface", "dcim.inventoryitem", "dcim.location", "dcim.manufacturer", "dcim.platform", "dcim.powerport", "dcim.region", "dcim.site", "dcim.virtualchassis", "ipam.ipaddress", "ipam.vlangroup", "ipam.vlan", "ipam.prefix", "ipam.vrf", "tenancy.tenantgroup", "tenancy.tenant", "tenancy.contact", "tenancy.contactassignment", "tenancy.contactgroup", "tenancy.contactrole", "virtualization.cluster", "virtualization.clustergroup", "virtualization.clustertype", "virtualization.virtualdisk", "virtualization.virtualmachine", "virtualization.vminterface", "wireless.wirelesslan"},
 	})
 	if err != nil {
 		return err
@@ -325,7 +176,7 @@ func (nbi *NetboxInventory) InitSsotCustomFields() error {
 		DisplayWeight:         objects.DisplayWeightDefault,
 		Description:           constants.CustomFieldSourceIDDescription,
 		SearchWeight:          objects.SearchWeightDefault,
-		ContentTypes:          []string{"dcim.device", "dcim.devicerole", "dcim.devicetype", "dcim.interface", "dcim.location", "dcim.manufacturer", "dcim.platform", "dcim.region", "dcim.site", "ipam.ipaddress", "ipam.vlangroup", "ipam.vlan", "ipam.prefix", "tenancy.tenantgroup", "tenancy.tenant", "tenancy.contact", "tenancy.contactassignment", "tenancy.contactgroup", "tenancy.contactrole", "virtualization.cluster", "virtualization.clustergroup", "virtualization.clustertype", "virtualization.virtualmachine", "virtualization.vminterface"},
+		ContentTypes:          []string{"dcim.cable", "dcim.device", "dcim.devicerole", "dcim.devicetype", "dcim.interface", "dcim.inventoryitem", "dcim.location", "dcim.manufacturer", "dcim.platform", "dcim.powerport", "dcim.region", "dcim.site", "dcim.virtualchassis", "ipam.ipaddress", "ipam.vlangroup", "ipam.vlan", "ipam.prefix", "ipam.vrf", "tenancy.tenantgroup", "tenancy.tenant", "tenancy.contact", "tenancy.contactassignment", "tenancy.contactgroup", "tenancy.contactrole", "virtualization.cluster", "virtualization.clustergroup", "virtualization.clustertype", "virtualization.virtualdisk", "virtualization.virtualmachine", "virtualization.vminterface", "wireless.wirelesslan"},
 	})
 	if err != nil {
 		return err
@@ -360,127 +211,157 @@ func (nbi *NetboxInventory) InitSsotCustomFields() error {
 	if err != nil {
 		return err
 	}
-	return nil
-}
-
-// Collects all nbClusters from Netbox API and stores them in the NetBoxInventory.
-func (nbi *NetboxInventory) InitClusterGroups() error {
-	nbClusterGroups, err := service.GetAll[objects.ClusterGroup](nbi.NetboxAPI, "")
+	err = nbi.AddCustomField(&objects.CustomField{
+		Name:                  constants.CustomFieldBIOSVersionName,
+		Label:                 constants.CustomFieldBIOSVersionLabel,
+		Type:                  objects.CustomFieldTypeText,
+		FilterLogic:           objects.FilterLogicLoose,
+		CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
+		CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
+		DisplayWeight:         objects.DisplayWeightDefault,
+		Description:           constants.CustomFieldBIOSVersionDescription,
+		SearchWeight:          objects.SearchWeightDefault,
+		ContentTypes:          []string{"dcim.device"},
+	})
 	if err != nil {
 		return err
 	}
-	// Initialize internal index of cluster groups by name
-	nbi.ClusterGroupsIndexByName = make(map[string]*objects.ClusterGroup)
-	// OrphanManager takes care of all cluster groups created by netbox-ssot
-	nbi.OrphanManager[service.ClusterGroupsAPIPath] = make(map[int]bool, 0)
-
-	for i := range nbClusterGroups {
-		clusterGroup := &nbClusterGroups[i]
-		nbi.ClusterGroupsIndexByName[clusterGroup.Name] = clusterGroup
-		if slices.IndexFunc(clusterGroup.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.ClusterGroupsAPIPath][clusterGroup.ID] = true
-		}
+	err = nbi.AddCustomField(&objects.CustomField{
+		Name:                  constants.CustomFieldBMCFirmwareName,
+		Label:                 constants.CustomFieldBMCFirmwareLabel,
+		Type:                  objects.CustomFieldTypeText,
+		FilterLogic:           objects.FilterLogicLoose,
+		CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
+		CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
+		DisplayWeight:         objects.DisplayWeightDefault,
+		Description:           constants.CustomFieldBMCFirmwareDescription,
+		SearchWeight:          objects.SearchWeightDefault,
+		ContentTypes:          []string{"dcim.device"},
+	})
+	if err != nil {
+		return err
 	}
-	nbi.Logger.Debug("Successfully collected cluster groups from Netbox: ", nbi.ClusterGroupsIndexByName)
-	return nil
-}
-
-// Collects all ClusterTypes from Netbox API and stores them in the NetBoxInventory.
-func (nbi *NetboxInventory) InitClusterTypes() error {
-	nbClusterTypes, err := service.GetAll[objects.ClusterType](nbi.NetboxAPI, "")
+	err = nbi.AddCustomField(&objects.CustomField{
+		Name:                  constants.CustomFieldWLCName,
+		Label:                 constants.CustomFieldWLCLabel,
+		Type:                  objects.CustomFieldTypeText,
+		FilterLogic:           objects.FilterLogicLoose,
+		CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
+		CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
+		DisplayWeight:         objects.DisplayWeightDefault,
+		Description:           constants.CustomFieldWLCDescription,
+		SearchWeight:          objects.SearchWeightDefault,
+		ContentTypes:          []string{"dcim.device"},
+	})
 	if err != nil {
 		return err
 	}
-
-	// Initialize internal index of cluster types by name
-	nbi.ClusterTypesIndexByName = make(map[string]*objects.ClusterType)
-	// OrphanManager takes care of all cluster types created by netbox-ssot
-	nbi.OrphanManager[service.ClusterTypesAPIPath] = make(map[int]bool, 0)
-
-	for i := range nbClusterTypes {
-		clusterType := &nbClusterTypes[i]
-		nbi.ClusterTypesIndexByName[clusterType.Name] = clusterType
-		if slices.IndexFunc(clusterType.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.ClusterTypesAPIPath][clusterType.ID] = true
-		}
+	err = nbi.AddCustomField(&objects.CustomField{
+		Name:                  constants.CustomFieldVMFolderName,
+		Label:                 constants.CustomFieldVMFolderLabel,
+		Type:                  objects.CustomFieldTypeText,
+		FilterLogic:           objects.FilterLogicLoose,
+		CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
+		CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
+		DisplayWeight:         objects.DisplayWeightDefault,
+		Description:           constants.CustomFieldVMFolderDescription,
+		SearchWeight:          objects.SearchWeightDefault,
+		ContentTypes:          []string{"virtualization.virtualmachine"},
+	})
+	if err != nil {
+		return err
 	}
-
-	nbi.Logger.Debug("Successfully collected cluster types from Netbox: ", nbi.ClusterTypesIndexByName)
-	return nil
-}
-
-// Collects all clusters from Netbox API and stores them to local inventory.
-func (nbi *NetboxInventory) InitClusters() error {
-	nbClusters, err := service.GetAll[objects.Cluster](nbi.NetboxAPI, "")
+	err = nbi.AddCustomField(&objects.CustomField{
+		Name:                  constants.CustomFieldVMResourcePoolName,
+		Label:                 constants.CustomFieldVMResourcePoolLabel,
+		Type:                  objects.CustomFieldTypeText,
+		FilterLogic:           objects.FilterLogicLoose,
+		CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
+		CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
+		DisplayWeight:         objects.DisplayWeightDefault,
+		Description:           constants.CustomFieldVMResourcePoolDescription,
+		SearchWeight:          objects.SearchWeightDefault,
+		ContentTypes:          []string{"virtualization.virtualmachine"},
+	})
 	if err != nil {
 		return err
 	}
-
-	// Initialize internal index of clusters by name
-	nbi.ClustersIndexByName = make(map[string]*objects.Cluster)
-	// OrphanManager takes care of all clusters created by netbox-ssot
-	nbi.OrphanManager[service.ClustersAPIPath] = make(map[int]bool, 0)
-
-	for i := range nbClusters {
-		cluster := &nbClusters[i]
-		nbi.ClustersIndexByName[cluster.Name] = cluster
-		if slices.IndexFunc(cluster.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.ClustersAPIPath][cluster.ID] = true
-		}
+	err = nbi.AddCustomField(&objects.CustomField{
+		Name:                  constants.CustomFieldDatastoreName,
+		Label:                 constants.CustomFieldDatastoreLabel,
+		Type:                  objects.CustomFieldTypeText,
+		FilterLogic:           objects.FilterLogicLoose,
+		CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
+		CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
+		DisplayWeight:         objects.DisplayWeightDefault,
+		Description:           constants.CustomFieldDatastoreDescription,
+		SearchWeight:          objects.SearchWeightDefault,
+		ContentTypes:          []string{"virtualization.virtualdisk"},
+	})
+	if err != nil {
+		return err
 	}
-
-	nbi.Logger.Debug("Successfully collected clusters from Netbox: ", nbi.ClustersIndexByName)
-	return nil
-}
-
-func (nbi *NetboxInventory) InitDeviceTypes() error {
-	nbDeviceTypes, err := service.GetAll[objects.DeviceType](nbi.NetboxAPI, "")
+	err = nbi.AddCustomField(&objects.CustomField{
+		Name:                  constants.CustomFieldProvisioningTypeName,
+		Label:                 constants.CustomFieldProvisioningTypeLabel,
+		Type:                  objects.CustomFieldTypeText,
+		FilterLogic:           objects.FilterLogicLoose,
+		CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
+		CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
+		DisplayWeight:         objects.DisplayWeightDefault,
+		Description:           constants.CustomFieldProvisioningTypeDescription,
+		SearchWeight:          objects.SearchWeightDefault,
+		ContentTypes:          []string{"virtualization.virtualdisk"},
+	})
 	if err != nil {
 		return err
 	}
+	return nil
+}
 
-	// Initialize internal index of device types by model
-	nbi.DeviceTypesIndexByModel = make(map[string]*objects.DeviceType)
-	// OrphanManager takes care of all device types created by netbox-ssot
-	nbi.OrphanManager[service.DeviceTypesAPIPath] = make(map[int]bool, 0)
+// Collects all nbClusters from Netbox API and stores them in the NetBoxInventory.
+func (nbi *NetboxInventory) InitClusterGroups() error {
+	return nbi.clusterGroups.Init()
+}
 
-	for i := range nbDeviceTypes {
-		deviceType := &nbDeviceTypes[i]
-		nbi.DeviceTypesIndexByModel[deviceType.Model] = deviceType
-		if slices.IndexFunc(deviceType.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.DeviceTypesAPIPath][deviceType.ID] = true
-		}
-	}
+// Collects all ClusterTypes from Netbox API and stores them in the NetBoxInventory.
+func (nbi *NetboxInventory) InitClusterTypes() error {
+	return nbi.clusterTypes.Init()
+}
 
-	nbi.Logger.Debug("Successfully collected device types from Netbox: ", nbi.DeviceTypesIndexByModel)
-	return nil
+// Collects all clusters from Netbox API and stores them to local inventory.
+func (nbi *NetboxInventory) InitClusters() error {
+	return nbi.clusters.Init()
+}
+
+func (nbi *NetboxInventory) InitDeviceTypes() error {
+	return nbi.deviceTypes.Init()
 }
 
 // Collects all interfaces from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) InitInterfaces() error {
-	nbInterfaces, err := service.GetAll[objects.Interface](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
+	return nbi.interfaces.Init()
+}
 
-	// Initialize internal index of interfaces by device id and name
-	nbi.InterfacesIndexByDeviceIDAndName = make(map[int]map[string]*objects.Interface)
-	// OrphanManager takes care of all interfaces created by netbox-ssot
-	nbi.OrphanManager[service.InterfacesAPIPath] = make(map[int]bool, 0)
+// Collects all power ports from Netbox API and stores them in the NetBoxInventory.
+func (nbi *NetboxInventory) InitPowerPorts() error {
+	return nbi.powerPorts.Init()
+}
 
-	for i := range nbInterfaces {
-		intf := &nbInterfaces[i]
-		if nbi.InterfacesIndexByDeviceIDAndName[intf.Device.ID] == nil {
-			nbi.InterfacesIndexByDeviceIDAndName[intf.Device.ID] = make(map[string]*objects.Interface)
-		}
-		nbi.InterfacesIndexByDeviceIDAndName[intf.Device.ID][intf.Name] = intf
-		if slices.IndexFunc(intf.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.InterfacesAPIPath][intf.ID] = true
-		}
-	}
+// Collects all virtual chassis from Netbox API and stores them in the NetBoxInventory.
+func (nbi *NetboxInventory) InitVirtualChassis() error {
+	return nbi.virtualChassis.Init()
+}
 
-	nbi.Logger.Debug("Successfully collected interfaces from Netbox: ", nbi.InterfacesIndexByDeviceIDAndName)
-	return nil
+// Collects all inventory items from Netbox API and stores them in the NetBoxInventory.
+func (nbi *NetboxInventory) InitInventoryItems() error {
+	return nbi.inventoryItems.Init()
+}
+
+// Collects all cables from Netbox API and stores them in the
+// NetBoxInventory.CablesIndexByInterfaceID.
+func (nbi *NetboxInventory) InitCables() error {
+	return nbi.cables.Init()
 }
 
 // Inits default VlanGroup, which is required to group all Vlans that are not part of other
@@ -490,7 +371,7 @@ func (nbi *NetboxInventory) InitDefaultVlanGroup() error {
 		NetboxObject: objects.NetboxObject{
 			Tags:        []*objects.Tag{nbi.SsotTag},
 			Description: "Default netbox-ssot VlanGroup for all vlans that are not part of any other vlanGroup. This group is required for netbox-ssot vlan index to work.",
-			CustomFields: map[string]string{
+			CustomFields: map[string]interface{}{
 				constants.CustomFieldSourceName: nbi.SsotTag.Name,
 			},
 		},
@@ -507,159 +388,67 @@ func (nbi *NetboxInventory) InitDefaultVlanGroup() error {
 
 // Collects all vlans from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) InitVlanGroups() error {
-	nbVlanGroups, err := service.GetAll[objects.VlanGroup](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-
-	// Initialize internal index of vlans by name
-	nbi.VlanGroupsIndexByName = make(map[string]*objects.VlanGroup)
-	// Add VlanGroups to orphan manager
-	nbi.OrphanManager[service.VlanGroupsAPIPath] = make(map[int]bool, 0)
-
-	for i := range nbVlanGroups {
-		vlanGroup := &nbVlanGroups[i]
-		nbi.VlanGroupsIndexByName[vlanGroup.Name] = vlanGroup
-		if slices.IndexFunc(vlanGroup.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.VlanGroupsAPIPath][vlanGroup.ID] = true
-		}
-	}
-
-	nbi.Logger.Debug("Successfully collected vlans from Netbox: ", nbi.VlanGroupsIndexByName)
-	return nil
+	return nbi.vlanGroups.Init()
 }
 
 // Collects all vlans from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) InitVlans() error {
-	nbVlans, err := service.GetAll[objects.Vlan](nbi.NetboxAPI, "")
-	if err != nil {
+	if err := nbi.vlans.Init(); err != nil {
 		return err
 	}
-
-	// Initialize internal index of vlans by VlanGroupId and Vid
-	nbi.VlansIndexByVlanGroupIDAndVID = make(map[int]map[int]*objects.Vlan)
-	// Add vlans to orphan manager
-	nbi.OrphanManager[service.VlansAPIPath] = make(map[int]bool, 0)
-
-	for i := range nbVlans {
-		vlan := &nbVlans[i]
-		if vlan.Group == nil {
-			// Update all existing vlans with default vlanGroup. This only happens
-			// when there are predefined vlans in netbox.
-			vlan.Group = nbi.VlanGroupsIndexByName[objects.DefaultVlanGroupName] // This should not fail, because InitDefaultVlanGroup executes before InitVlans
-			vlan, err = nbi.AddVlan(vlan)
-			if err != nil {
-				return err
-			}
-		}
-		if nbi.VlansIndexByVlanGroupIDAndVID[vlan.Group.ID] == nil {
-			nbi.VlansIndexByVlanGroupIDAndVID[vlan.Group.ID] = make(map[int]*objects.Vlan)
+	// Update all existing vlans without vlanGroup with default vlanGroup. This only happens
+	// when there are predefined vlans in netbox. InitDefaultVlanGroup executes before InitVlans.
+	defaultVlanGroup := nbi.VlanGroupsIndexByName[objects.DefaultVlanGroupName]
+	for _, vlan := range nbi.vlans.All() {
+		if vlan.Group != nil {
+			continue
 		}
-		nbi.VlansIndexByVlanGroupIDAndVID[vlan.Group.ID][vlan.Vid] = vlan
-		if slices.IndexFunc(vlan.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.VlansAPIPath][vlan.ID] = true
+		nbi.Logger.Debugf("Vlan %s has no vlan group. Adding it to %s...", vlan.Name, defaultVlanGroup.Name)
+		if _, err := nbi.vlans.patch(vlan, map[string]interface{}{"group": defaultVlanGroup.ID}); err != nil {
+			return fmt.Errorf("init vlans: %s", err)
 		}
 	}
-
-	nbi.Logger.Debug("Successfully collected vlans from Netbox: ", nbi.VlansIndexByVlanGroupIDAndVID)
 	return nil
 }
 
 // Collects all vms from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) InitVMs() error {
-	nbVMs, err := service.GetAll[objects.VM](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-
-	// Initialize internal index of VMs by name
-	nbi.VMsIndexByName = make(map[string]*objects.VM)
-	// Add VMs to orphan manager
-	nbi.OrphanManager[service.VirtualMachinesAPIPath] = make(map[int]bool, 0)
-
-	for i := range nbVMs {
-		vm := &nbVMs[i]
-		nbi.VMsIndexByName[vm.Name] = vm
-		if slices.IndexFunc(vm.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.VirtualMachinesAPIPath][vm.ID] = true
-		}
-	}
-
-	nbi.Logger.Debug("Successfully collected VMs from Netbox: ", nbi.VMsIndexByName)
-	return nil
+	return nbi.vms.Init()
 }
 
 // Collects all VMInterfaces from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) InitVMInterfaces() error {
-	nbVMInterfaces, err := service.GetAll[objects.VMInterface](nbi.NetboxAPI, "")
-	if err != nil {
-		return fmt.Errorf("Init vm interfaces: %s", err)
+	if err := nbi.vmInterfaces.Init(); err != nil {
+		return fmt.Errorf("init vm interfaces: %s", err)
 	}
+	return nil
+}
 
-	// Initialize internal index of VM interfaces by VM id and name
-	nbi.VMInterfacesIndexByVMIdAndName = make(map[int]map[string]*objects.VMInterface)
-	// Add VMInterfaces to orphan manager
-	nbi.OrphanManager[service.VMInterfacesAPIPath] = make(map[int]bool)
-
-	for i := range nbVMInterfaces {
-		vmIntf := &nbVMInterfaces[i]
-		if nbi.VMInterfacesIndexByVMIdAndName[vmIntf.VM.ID] == nil {
-			nbi.VMInterfacesIndexByVMIdAndName[vmIntf.VM.ID] = make(map[string]*objects.VMInterface)
-		}
-		nbi.VMInterfacesIndexByVMIdAndName[vmIntf.VM.ID][vmIntf.Name] = vmIntf
-		if slices.IndexFunc(vmIntf.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.VMInterfacesAPIPath][vmIntf.ID] = true
-		}
+// Collects all VirtualDisks from Netbox API and stores them to local inventory.
+func (nbi *NetboxInventory) InitVirtualDisks() error {
+	if err := nbi.virtualDisks.Init(); err != nil {
+		return fmt.Errorf("init virtual disks: %s", err)
 	}
-
-	nbi.Logger.Debug("Successfully collected VM interfaces from Netbox: ", nbi.VMInterfacesIndexByVMIdAndName)
 	return nil
 }
 
 // Collects all IP addresses from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) InitIPAddresses() error {
-	ipAddresses, err := service.GetAll[objects.IPAddress](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-
-	// Initializes internal index of IP addresses by address
-	nbi.IPAdressesIndexByAddress = make(map[string]*objects.IPAddress)
-	// Add IP addresses to orphan manager
-	nbi.OrphanManager[service.IPAddressesAPIPath] = make(map[int]bool, 0)
-
-	for i := range ipAddresses {
-		ipAddr := &ipAddresses[i]
-		nbi.IPAdressesIndexByAddress[ipAddr.Address] = ipAddr
-		if slices.IndexFunc(ipAddr.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.IPAddressesAPIPath][ipAddr.ID] = true
-		}
-	}
+	return nbi.ipAddresses.Init()
+}
 
-	nbi.Logger.Debug("Successfully collected IP addresses from Netbox: ", nbi.IPAdressesIndexByAddress)
-	return nil
+// Collects all VRFs from Netbox API and stores them to local inventory.
+func (nbi *NetboxInventory) InitVRFs() error {
+	return nbi.vrfs.Init()
 }
 
 // Collects all Prefixes from Netbox API and stores them to local inventory.
 func (nbi *NetboxInventory) InitPrefixes() error {
-	prefixes, err := service.GetAll[objects.Prefix](nbi.NetboxAPI, "")
-	if err != nil {
-		return err
-	}
-
-	// Initializes internal index of prefixes by prefix
-	nbi.PrefixesIndexByPrefix = make(map[string]*objects.Prefix)
-	// Add prefixes to orphan manager
-	nbi.OrphanManager[service.PrefixesAPIPath] = make(map[int]bool, 0)
-
-	for i := range prefixes {
-		prefix := &prefixes[i]
-		nbi.PrefixesIndexByPrefix[prefix.Prefix] = prefix
-		if slices.IndexFunc(prefix.Tags, func(t *objects.Tag) bool { return t.Slug == nbi.SsotTag.Slug }) >= 0 {
-			nbi.OrphanManager[service.PrefixesAPIPath][prefix.ID] = true
-		}
-	}
+	return nbi.prefixes.Init()
+}
 
-	nbi.Logger.Debug("Successfully collected prefixes from Netbox: ", nbi.PrefixesIndexByPrefix)
-	return nil
+// Collects all wireless lans from Netbox API and stores them in the
+// NetBoxInventory.WirelessLANsIndexBySSID.
+func (nbi *NetboxInventory) InitWirelessLANs() error {
+	return nbi.wirelessLANs.Init()
 }
@@ -33,6 +33,12 @@ type NetboxInventory struct {
 	ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID map[string]map[int]map[int]map[int]*objects.ContactAssignment
 	// SitesIndexByName is a map of all sites in the Netbox's inventory, indexed by their name
 	SitesIndexByName map[string]*objects.Site
+	// RegionsIndexByParentIDAndName is a map of all regions in the Netbox's inventory, indexed by
+	// their parent's id (0 for top level regions) and their name.
+	RegionsIndexByParentIDAndName map[int]map[string]*objects.Region
+	// LocationsIndexBySiteIDAndName is a map of all locations in the Netbox's inventory, indexed by their
+	// site id and their name.
+	LocationsIndexBySiteIDAndName map[int]map[string]*objects.Location
 	// ManufacturersIndexByName is a map of all manufacturers in the Netbox's inventory, indexed by their name
 	ManufacturersIndexByName map[string]*objects.Manufacturer
 	// PlatformsIndexByName is a map of all platforms in the Netbox's inventory, indexed by their name
@@ -44,8 +50,20 @@ type NetboxInventory struct {
 	// DevicesIndexByNameAndSiteID is a map of all devices in the Netbox's inventory, indexed by their name, and
 	// site ID (This is because, netbox constraints: https://github.com/netbox-community/netbox/blob/3d941411d438f77b66d2036edf690c14b459af58/netbox/dcim/models/devices.py#L775)
 	DevicesIndexByNameAndSiteID map[string]map[int]*objects.Device
-	// PrefixesIndexByPrefix is a map of all prefixes in the Netbox's inventory, indexed by their prefix
-	PrefixesIndexByPrefix map[string]*objects.Prefix
+	// DevicesIndexBySourceAndSourceID is a map of all devices in the Netbox's inventory, that have
+	// source and source_id custom fields set, indexed by their source name and their source id
+	DevicesIndexBySourceAndSourceID map[string]map[string]*objects.Device
+	// DevicesIndexBySerialNumber is a map of all devices in the Netbox's inventory, indexed by their
+	// lowercase serial number. Serial numbers shared by multiple devices are indexed with nil device
+	DevicesIndexBySerialNumber map[string]*objects.Device
+	// DevicesIndexByAssetTag is a map of all devices in the Netbox's inventory, indexed by their
+	// asset tag. Asset tags shared by multiple devices are indexed with nil device
+	DevicesIndexByAssetTag map[string]*objects.Device
+	// VRFsIndexByName is a map of all VRFs in the Netbox's inventory, indexed by their name
+	VRFsIndexByName map[string]*objects.VRF
+	// PrefixesIndexByVRFIDAndPrefix is a map of all prefixes in the Netbox's inventory, indexed by their
+	// VRF id (0 for the global table) and their prefix
+	PrefixesIndexByVRFIDAndPrefix map[int]map[string]*objects.Prefix
 	// VlanGroupsIndexByName is a map of all VlanGroups in the Netbox's inventory, indexed by their name
 	VlanGroupsIndexByName map[string]*objects.VlanGroup
 	// VlansIndexByVlanGroupIDAndVID is a map of all vlans in the Netbox's inventory, indexed by their VlanGroup and vid.
@@ -63,12 +81,35 @@ type NetboxInventory struct {
 	// InterfacesIndexByDeviceAnName is a map of all interfaces in the inventory, indexed by their's
 	// device id and their name.
 	InterfacesIndexByDeviceIDAndName map[int]map[string]*objects.Interface
-	// VirtualMachinedIndexByName is a map of all virtual machines in the inventory, indexed by their name
-	VMsIndexByName map[string]*objects.VM
+	// InterfacesIndexBySourceAndSourceID is a map of all interfaces in the inventory, that have
+	// source and source_id custom fields set, indexed by their source name and their source id
+	InterfacesIndexBySourceAndSourceID map[string]map[string]*objects.Interface
+	// PowerPortsIndexByDeviceIDAndName is a map of all power ports in the inventory, indexed by their's
+	// device id and their name.
+	PowerPortsIndexByDeviceIDAndName map[int]map[string]*objects.PowerPort
+	// VirtualChassisIndexByName is a map of all virtual chassis in the inventory, indexed by their name
+	VirtualChassisIndexByName map[string]*objects.VirtualChassis
+	// InventoryItemsIndexByDeviceIDAndName is a map of all inventory items in the inventory, indexed by their's
+	// device id and their name.
+	InventoryItemsIndexByDeviceIDAndName map[int]map[string]*objects.InventoryItem
+	// CablesIndexByInterfaceID is a map of all cables in the inventory, indexed by ids
+	// of interfaces on both of their ends.
+	CablesIndexByInterfaceID map[int]*objects.Cable
+	// VMsIndexByClusterIDAndName is a map of all virtual machines in the inventory, indexed by their
+	// cluster id (0 for vms without cluster) and their name, which is unique in netbox
+	VMsIndexByClusterIDAndName map[int]map[string]*objects.VM
+	// VMsIndexBySourceAndSourceID is a map of all virtual machines in the inventory, that have
+	// source and source_id custom fields set, indexed by their source name and their source id
+	VMsIndexBySourceAndSourceID map[string]map[string]*objects.VM
 	// VirtualMachineInterfacesIndexByVMAndName is a map of all virtual machine interfaces in the inventory, indexed by their's virtual machine id and their name
 	VMInterfacesIndexByVMIdAndName map[int]map[string]*objects.VMInterface
-	// IPAdressesIndexByAddress is a map of all IP addresses in the inventory, indexed by their address
-	IPAdressesIndexByAddress map[string]*objects.IPAddress
+	// VirtualDisksIndexByVMIDAndName is a map of all virtual disks in the inventory, indexed by their's virtual machine id and their name
+	VirtualDisksIndexByVMIDAndName map[int]map[string]*objects.VirtualDisk
+	// WirelessLANsIndexBySSID is a map of all wireless lans in the inventory, indexed by their ssid
+	WirelessLANsIndexBySSID map[string]*objects.WirelessLAN
+	// IPAddressesIndexByVRFIDAndAddress is a map of all IP addresses in the inventory, indexed by their
+	// VRF id (0 for the global table) and their address
+	IPAddressesIndexByVRFIDAndAddress map[int]map[string]*objects.IPAddress
 
 	// Orphan manager is a map of objectAPIPath to a set of managed ids for that object type.
 	//
@@ -96,6 +137,11 @@ type NetboxInventory struct {
 
 	// Tag used by netbox-ssot to mark devices that are managed by it
 	SsotTag *objects.Tag
+
+	// stores of all objects managed by the inventory
+	stores
+	// snapshot of the previous run, used only during Init
+	snapshot *Snapshot
 }
 
 // Func string representation.
@@ -113,25 +159,36 @@ func NewNetboxInventory(logger *logger.Logger, nbConfig *parser.NetboxConfig) *N
 	}
 	// Starts with 0 for easier integration with for loops
 	orphanObjectPriority := map[int]string{
-		0:  service.VlanGroupsAPIPath,
-		1:  service.PrefixesAPIPath,
-		2:  service.VlansAPIPath,
-		3:  service.IPAddressesAPIPath,
-		4:  service.InterfacesAPIPath,
-		5:  service.VMInterfacesAPIPath,
-		6:  service.VirtualMachinesAPIPath,
-		7:  service.DevicesAPIPath,
-		8:  service.PlatformsAPIPath,
-		9:  service.DeviceTypesAPIPath,
-		10: service.ManufacturersAPIPath,
-		11: service.DeviceRolesAPIPath,
-		12: service.ClustersAPIPath,
-		13: service.ClusterTypesAPIPath,
-		14: service.ClusterGroupsAPIPath,
-		15: service.ContactsAPIPath,
-		16: service.ContactAssignmentsAPIPath,
+		0:  service.CablesAPIPath,
+		1:  service.WirelessLANsAPIPath,
+		2:  service.VlanGroupsAPIPath,
+		3:  service.PrefixesAPIPath,
+		4:  service.VlansAPIPath,
+		5:  service.IPAddressesAPIPath,
+		6:  service.VRFsAPIPath,
+		7:  service.InventoryItemsAPIPath,
+		8:  service.InterfacesAPIPath,
+		9:  service.PowerPortsAPIPath,
+		10: service.VMInterfacesAPIPath,
+		11: service.VirtualDisksAPIPath,
+		12: service.VirtualMachinesAPIPath,
+		13: service.VirtualChassisAPIPath,
+		14: service.DevicesAPIPath,
+		15: service.PlatformsAPIPath,
+		16: service.DeviceTypesAPIPath,
+		17: service.ManufacturersAPIPath,
+		18: service.DeviceRolesAPIPath,
+		19: service.ClustersAPIPath,
+		20: service.ClusterTypesAPIPath,
+		21: service.ClusterGroupsAPIPath,
+		22: service.LocationsAPIPath,
+		23: service.SitesAPIPath,
+		24: service.RegionsAPIPath,
+		25: service.ContactsAPIPath,
+		26: service.ContactAssignmentsAPIPath,
 	}
 	nbi := &NetboxInventory{Logger: logger, NetboxConfig: nbConfig, SourcePriority: sourcePriority, OrphanManager: make(map[string]map[int]bool), OrphanObjectPriority: orphanObjectPriority}
+	nbi.declareStores()
 	return nbi
 }
 
@@ -141,6 +198,13 @@ func (nbi *NetboxInventory) Init() error {
 
 	nbi.Logger.Debug("Initializing Netbox API with baseURL: ", baseURL)
 	nbi.NetboxAPI = service.NewNetBoxAPI(nbi.Logger, baseURL, nbi.NetboxConfig.APIToken, nbi.NetboxConfig.ValidateCert, nbi.NetboxConfig.Timeout)
+	nbi.NetboxAPI.PageSize = nbi.NetboxConfig.PageSize
+	nbi.NetboxAPI.DisableFieldSelection = nbi.NetboxConfig.DisableFieldSelection
+
+	initStartTime := time.Now()
+	if nbi.NetboxConfig.Snapshot {
+		nbi.snapshot = nbi.loadSnapshot()
+	}
 
 	// Order matters. TODO: use parallelization in the future, on the init functions that can be parallelized
 	initFunctions := []func() error{
@@ -153,16 +217,24 @@ func (nbi *NetboxInventory) Init() error {
 		nbi.InitContacts,
 		nbi.InitContactAssignments,
 		nbi.InitTenants,
+		nbi.InitRegions,
 		nbi.InitSites,
+		nbi.InitLocations,
 		nbi.InitManufacturers,
 		nbi.InitPlatforms,
+		nbi.InitVirtualChassis,
 		nbi.InitDevices,
+		nbi.InitInventoryItems,
 		nbi.InitInterfaces,
+		nbi.InitPowerPorts,
+		nbi.InitCables,
+		nbi.InitVRFs,
 		nbi.InitIPAddresses,
 		nbi.InitVlanGroups,
 		nbi.InitDefaultVlanGroup,
 		nbi.InitPrefixes,
 		nbi.InitVlans,
+		nbi.InitWirelessLANs,
 		nbi.InitDeviceRoles,
 		nbi.InitServerDeviceRole,
 		nbi.InitDeviceTypes,
@@ -171,6 +243,7 @@ func (nbi *NetboxInventory) Init() error {
 		nbi.InitClusters,
 		nbi.InitVMs,
 		nbi.InitVMInterfaces,
+		nbi.InitVirtualDisks,
 	}
 	for _, initFunc := range initFunctions {
 		startTime := time.Now()
@@ -181,5 +254,12 @@ func (nbi *NetboxInventory) Init() error {
 		nbi.Logger.Infof("Successfully initialized %s in %f seconds", utils.ExtractFunctionName(initFunc), duration.Seconds())
 	}
 
+	if nbi.NetboxConfig.Snapshot {
+		nbi.snapshot = nil
+		if err := nbi.saveSnapshot(initStartTime); err != nil {
+			nbi.Logger.Warningf("failed saving inventory snapshot: %s", err)
+		}
+	}
+
 	return nil
 }
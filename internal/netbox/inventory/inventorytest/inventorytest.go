@@ -0,0 +1,235 @@
+// Package inventorytest provides an in-memory fake of Netbox API, which is used
+// to test syncing of sources against the netbox inventory.
+package inventorytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+)
+
+// objectTypes maps api paths to types of objects, that are served by the fake Netbox.
+var objectTypes = map[string]reflect.Type{
+	service.ContactGroupsAPIPath:      reflect.TypeOf(objects.ContactGroup{}),
+	service.ContactRolesAPIPath:       reflect.TypeOf(objects.ContactRole{}),
+	service.ContactsAPIPath:           reflect.TypeOf(objects.Contact{}),
+	service.TenantsAPIPath:            reflect.TypeOf(objects.Tenant{}),
+	service.ContactAssignmentsAPIPath: reflect.TypeOf(objects.ContactAssignment{}),
+	service.PrefixesAPIPath:           reflect.TypeOf(objects.Prefix{}),
+	service.VlanGroupsAPIPath:         reflect.TypeOf(objects.VlanGroup{}),
+	service.VlansAPIPath:              reflect.TypeOf(objects.Vlan{}),
+	service.IPAddressesAPIPath:        reflect.TypeOf(objects.IPAddress{}),
+	service.VRFsAPIPath:               reflect.TypeOf(objects.VRF{}),
+	service.ClusterTypesAPIPath:       reflect.TypeOf(objects.ClusterType{}),
+	service.ClusterGroupsAPIPath:      reflect.TypeOf(objects.ClusterGroup{}),
+	service.ClustersAPIPath:           reflect.TypeOf(objects.Cluster{}),
+	service.VirtualMachinesAPIPath:    reflect.TypeOf(objects.VM{}),
+	service.VMInterfacesAPIPath:       reflect.TypeOf(objects.VMInterface{}),
+	service.VirtualDisksAPIPath:       reflect.TypeOf(objects.VirtualDisk{}),
+	service.DevicesAPIPath:            reflect.TypeOf(objects.Device{}),
+	service.DeviceRolesAPIPath:        reflect.TypeOf(objects.DeviceRole{}),
+	service.DeviceTypesAPIPath:        reflect.TypeOf(objects.DeviceType{}),
+	service.InterfacesAPIPath:         reflect.TypeOf(objects.Interface{}),
+	service.PowerPortsAPIPath:         reflect.TypeOf(objects.PowerPort{}),
+	service.SitesAPIPath:              reflect.TypeOf(objects.Site{}),
+	service.RegionsAPIPath:            reflect.TypeOf(objects.Region{}),
+	service.LocationsAPIPath:          reflect.TypeOf(objects.Location{}),
+	service.ManufacturersAPIPath:      reflect.TypeOf(objects.Manufacturer{}),
+	service.PlatformsAPIPath:          reflect.TypeOf(objects.Platform{}),
+	service.CablesAPIPath:             reflect.TypeOf(objects.Cable{}),
+	service.VirtualChassisAPIPath:     reflect.TypeOf(objects.VirtualChassis{}),
+	service.InventoryItemsAPIPath:     reflect.TypeOf(objects.InventoryItem{}),
+	service.WirelessLANsAPIPath:       reflect.TypeOf(objects.WirelessLAN{}),
+	service.CustomFieldsAPIPath:       reflect.TypeOf(objects.CustomField{}),
+	service.TagsAPIPath:               reflect.TypeOf(objects.Tag{}),
+}
+
+// Request is a request, that modified objects of the fake Netbox.
+type Request struct {
+	Method string
+	// Path of the object (e.g. /api/dcim/devices/1/)
+	Path string
+	// Body of the request
+	Body map[string]interface{}
+}
+
+// FakeNetbox is an in-memory Netbox API. It stores created and patched objects, and
+// returns them in the same format as Netbox does (nested objects contain only ids).
+// Filters of list requests are ignored, all objects of the api path are returned.
+type FakeNetbox struct {
+	mu      sync.Mutex
+	nextID  int
+	objects map[string]map[int]map[string]interface{} // api path -> id -> object
+	// Requests are all POST, PATCH and DELETE requests received by the fake Netbox
+	Requests []Request
+}
+
+// NewInventory returns an inventory, whose objects are stored in a new fake Netbox.
+// Inventory is not initialized, so objects, that should already exist in Netbox,
+// can be added with inventory's Add functions.
+func NewInventory(t *testing.T) (*inventory.NetboxInventory, *FakeNetbox) {
+	t.Helper()
+	fake := &FakeNetbox{objects: make(map[string]map[int]map[string]interface{})}
+	server := httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(server.Close)
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi := inventory.NewNetboxInventory(testLogger, &parser.NetboxConfig{})
+	nbi.NetboxAPI = service.NewNetBoxAPI(testLogger, server.URL, "token", false, 5)
+	nbi.SsotTag, err = nbi.AddTag(&objects.Tag{Name: "netbox-ssot", Slug: "netbox-ssot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.Requests = nil
+	return nbi, fake
+}
+
+// Patches returns bodies of all PATCH requests of the object with the given api path and id.
+func (f *FakeNetbox) Patches(apiPath string, id int) []map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var patches []map[string]interface{}
+	for _, request := range f.Requests {
+		if request.Method == http.MethodPatch && request.Path == apiPath+strconv.Itoa(id)+"/" {
+			patches = append(patches, request.Body)
+		}
+	}
+	return patches
+}
+
+func (f *FakeNetbox) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	apiPath, id := splitPath(r.URL.Path)
+	objectType, ok := objectTypes[apiPath]
+	if !ok {
+		http.Error(w, "unknown api path", http.StatusNotFound)
+		return
+	}
+	var body map[string]interface{}
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.Requests = append(f.Requests, Request{Method: r.Method, Path: r.URL.Path, Body: body})
+	}
+	switch {
+	case r.Method == http.MethodGet && id == 0:
+		ids := make([]int, 0, len(f.objects[apiPath]))
+		for id := range f.objects[apiPath] {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		results := make([]map[string]interface{}, 0, len(ids))
+		for _, id := range ids {
+			results = append(results, f.objects[apiPath][id])
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"count": len(results), "next": nil, "previous": nil, "results": results})
+	case r.Method == http.MethodPost && id == 0:
+		f.nextID++
+		obj := map[string]interface{}{"id": f.nextID}
+		merge(obj, body, objectType)
+		if f.objects[apiPath] == nil {
+			f.objects[apiPath] = make(map[int]map[string]interface{})
+		}
+		f.objects[apiPath][f.nextID] = obj
+		writeJSON(w, http.StatusCreated, obj)
+	case r.Method == http.MethodPatch && f.objects[apiPath][id] != nil:
+		merge(f.objects[apiPath][id], body, objectType)
+		writeJSON(w, http.StatusOK, f.objects[apiPath][id])
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+// splitPath splits path of the request to api path and id of the object (0 for lists).
+func splitPath(path string) (string, int) {
+	trimmed := strings.TrimSuffix(path, "/")
+	lastSlash := strings.LastIndex(trimmed, "/")
+	id, err := strconv.Atoi(trimmed[lastSlash+1:])
+	if err != nil {
+		return path, 0
+	}
+	return trimmed[:lastSlash+1], id
+}
+
+// merge merges fields of the request body into obj. Nested objects are sent as ids
+// and choices as their values, so they are converted to objects, like Netbox returns them.
+// Custom fields are merged with the existing ones, like Netbox does.
+func merge(obj map[string]interface{}, body map[string]interface{}, objectType reflect.Type) {
+	fields := make(map[string]reflect.Type)
+	collectFields(objectType, fields)
+	for key, value := range body {
+		customFields, ok := value.(map[string]interface{})
+		if existingCustomFields, isMap := obj[key].(map[string]interface{}); ok && isMap && key == "custom_fields" {
+			for name, customField := range customFields {
+				existingCustomFields[name] = customField
+			}
+			continue
+		}
+		obj[key] = nestedValue(value, fields[key])
+	}
+}
+
+// collectFields collects types of all json fields of t (including fields of embedded structs).
+func collectFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct:
+			collectFields(field.Type, fields)
+		case name != "" && name != "-":
+			fields[name] = field.Type
+		}
+	}
+}
+
+// nestedValue converts value of the request body to the value returned by Netbox.
+func nestedValue(value interface{}, fieldType reflect.Type) interface{} {
+	if fieldType == nil {
+		return value
+	}
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	switch fieldType.Kind() {
+	case reflect.Struct:
+		switch v := value.(type) {
+		case float64:
+			return map[string]interface{}{"id": v}
+		case string:
+			return map[string]interface{}{"value": v}
+		}
+	case reflect.Slice:
+		if values, ok := value.([]interface{}); ok {
+			nested := make([]interface{}, 0, len(values))
+			for _, v := range values {
+				nested = append(nested, nestedValue(v, fieldType.Elem()))
+			}
+			return nested
+		}
+	}
+	return value
+}
+
+func writeJSON(w http.ResponseWriter, status int, content interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(content)
+}
@@ -0,0 +1,157 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+const (
+	// snapshotVersion is increased whenever format of the snapshot changes,
+	// so snapshots written by older versions are discarded.
+	snapshotVersion = 1
+	// snapshotClockSkew is subtracted from the snapshot timestamp, when fetching changed
+	// objects, so clock skew between netbox-ssot and Netbox doesn't cause missed changes.
+	snapshotClockSkew = 5 * time.Minute
+)
+
+// Snapshot of the inventory, that is persisted between runs. On startup only objects
+// changed since Timestamp are fetched from Netbox and merged with the snapshot.
+type Snapshot struct {
+	// Version of the snapshot format
+	Version int `json:"version"`
+	// NetboxURL is the base url of the Netbox, from which objects were collected
+	NetboxURL string `json:"netbox_url"`
+	// Timestamp is the time when collection of the objects started
+	Timestamp time.Time `json:"timestamp"`
+	// Objects of each store, indexed by their api path
+	Objects map[string]json.RawMessage `json:"objects"`
+
+	// deleted are ids of objects deleted since Timestamp, indexed by their api path
+	deleted map[string]map[int]bool
+}
+
+// snapshotStore is a store, whose objects can be persisted in the snapshot.
+type snapshotStore interface {
+	saveTo(snapshot *Snapshot) error
+}
+
+// snapshotPath returns path of the file, where snapshot of the inventory is persisted.
+func (nbi *NetboxInventory) snapshotPath() string {
+	return filepath.Join(nbi.NetboxConfig.SnapshotDir, fmt.Sprintf("netbox-%s.json", utils.Slugify(nbi.NetboxConfig.Hostname)))
+}
+
+// loadSnapshot loads snapshot of the previous run and collects objects deleted since then
+// from Netbox's changelog. If there is no valid snapshot, nil is returned, and all
+// objects are collected from Netbox.
+func (nbi *NetboxInventory) loadSnapshot() *Snapshot {
+	content, err := os.ReadFile(nbi.snapshotPath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			nbi.Logger.Warningf("failed reading inventory snapshot: %s", err)
+		}
+		return nil
+	}
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(content, snapshot); err != nil {
+		nbi.Logger.Warningf("failed parsing inventory snapshot: %s", err)
+		return nil
+	}
+	maxAge := time.Duration(nbi.NetboxConfig.SnapshotMaxAge) * time.Hour
+	switch {
+	case snapshot.Version != snapshotVersion:
+		nbi.Logger.Infof("Inventory snapshot has version %d instead of %d. Collecting all objects...", snapshot.Version, snapshotVersion)
+		return nil
+	case snapshot.NetboxURL != nbi.NetboxAPI.BaseURL:
+		nbi.Logger.Infof("Inventory snapshot is from %s instead of %s. Collecting all objects...", snapshot.NetboxURL, nbi.NetboxAPI.BaseURL)
+		return nil
+	case time.Since(snapshot.Timestamp) > maxAge:
+		nbi.Logger.Infof("Inventory snapshot from %s is older than %s. Collecting all objects...", snapshot.Timestamp, maxAge)
+		return nil
+	}
+	snapshot.deleted, err = nbi.deletedSince(snapshot.since())
+	if err != nil {
+		nbi.Logger.Warningf("failed collecting deleted objects from changelog: %s. Collecting all objects...", err)
+		return nil
+	}
+	nbi.Logger.Infof("Loaded inventory snapshot from %s", snapshot.Timestamp)
+	return snapshot
+}
+
+// deletedSince returns ids of objects deleted since the given time, indexed by their api path.
+// Changelog was moved from extras to core in Netbox 4.1, so the legacy api path is used as
+// a fallback for older versions of Netbox.
+func (nbi *NetboxInventory) deletedSince(since string) (map[string]map[int]bool, error) {
+	params := fmt.Sprintf("&action=%s&time_after=%s", objects.ObjectChangeActionDelete.Value, since)
+	changes, err := service.GetAll[objects.ObjectChange](nbi.NetboxAPI, params)
+	if err != nil {
+		var legacyErr error
+		changes, legacyErr = service.GetAllFromPath[objects.ObjectChange](nbi.NetboxAPI, service.LegacyObjectChangesAPIPath, params)
+		if legacyErr != nil {
+			return nil, err
+		}
+		nbi.Logger.Debugf("Collected object changes from %s", service.LegacyObjectChangesAPIPath)
+	}
+	apiPaths := make(map[string]string, len(service.ContentTypes))
+	for apiPath, contentType := range service.ContentTypes {
+		apiPaths[contentType] = apiPath
+	}
+	deleted := make(map[string]map[int]bool)
+	for _, change := range changes {
+		apiPath, ok := apiPaths[change.ChangedObjectType]
+		if !ok {
+			continue
+		}
+		if deleted[apiPath] == nil {
+			deleted[apiPath] = make(map[int]bool)
+		}
+		deleted[apiPath][change.ChangedObjectID] = true
+	}
+	return deleted, nil
+}
+
+// saveSnapshot persists objects of all stores, so the next run can continue from them.
+func (nbi *NetboxInventory) saveSnapshot(timestamp time.Time) error {
+	snapshot := &Snapshot{
+		Version:   snapshotVersion,
+		NetboxURL: nbi.NetboxAPI.BaseURL,
+		Timestamp: timestamp,
+		Objects:   make(map[string]json.RawMessage),
+	}
+	for _, store := range nbi.snapshotStores() {
+		if err := store.saveTo(snapshot); err != nil {
+			return err
+		}
+	}
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal inventory snapshot: %s", err)
+	}
+	if err := os.MkdirAll(nbi.NetboxConfig.SnapshotDir, 0700); err != nil {
+		return fmt.Errorf("snapshot dir: %s", err)
+	}
+	// Snapshot is written to a temporary file first, so an interrupted
+	// write never leaves a corrupted snapshot behind
+	tmpPath := nbi.snapshotPath() + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0600); err != nil {
+		return fmt.Errorf("write inventory snapshot: %s", err)
+	}
+	if err := os.Rename(tmpPath, nbi.snapshotPath()); err != nil {
+		return fmt.Errorf("write inventory snapshot: %s", err)
+	}
+	nbi.Logger.Debugf("Saved inventory snapshot to %s", nbi.snapshotPath())
+	return nil
+}
+
+// since returns the time, from which changed objects are fetched, in format
+// accepted by Netbox's filters.
+func (s *Snapshot) since() string {
+	return s.Timestamp.Add(-snapshotClockSkew).UTC().Format(time.RFC3339)
+}
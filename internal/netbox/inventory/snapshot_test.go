@@ -0,0 +1,114 @@
+package inventory
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+)
+
+func TestSnapshot(t *testing.T) {
+	// Netbox, where since the snapshot site 2 was renamed, site 3 was deleted and site 4 was created
+	config := &parser.NetboxConfig{Snapshot: true, SnapshotDir: t.TempDir(), SnapshotMaxAge: 1}
+	nbi := newTestInventory(t, config, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == service.ObjectChangesAPIPath:
+			writeResults(w, []objects.ObjectChange{
+				{ID: 1, Action: &objects.ObjectChangeActionDelete, ChangedObjectType: "dcim.site", ChangedObjectID: 3},
+			})
+		case r.URL.Path == service.SitesAPIPath && r.URL.Query().Get("last_updated__gte") != "":
+			writeResults(w, []objects.Site{
+				{NetboxObject: objects.NetboxObject{ID: 2}, Name: "site2-renamed"},
+				{NetboxObject: objects.NetboxObject{ID: 4}, Name: "site4"},
+			})
+		case r.URL.Path == service.SitesAPIPath:
+			t.Errorf("all sites were collected, instead of only changed ones")
+			writeResults(w, []objects.Site{})
+		default:
+			writeResults(w, []interface{}{})
+		}
+	})
+
+	for id, name := range map[int]string{1: "site1", 2: "site2", 3: "site3"} {
+		nbi.sites.put(&objects.Site{NetboxObject: objects.NetboxObject{ID: id}, Name: name})
+	}
+	if err := nbi.saveSnapshot(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	nbi.snapshot = nbi.loadSnapshot()
+	if nbi.snapshot == nil {
+		t.Fatal("loadSnapshot() returned nil")
+	}
+	if err := nbi.InitSites(); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, site := range nbi.sites.All() {
+		names = append(names, site.Name)
+	}
+	if expected := []string{"site1", "site2-renamed", "site4"}; len(names) != len(expected) || names[0] != expected[0] || names[1] != expected[1] || names[2] != expected[2] {
+		t.Errorf("sites = %v, want %v", names, expected)
+	}
+	if _, ok := nbi.SitesIndexByName["site2"]; ok {
+		t.Errorf("renamed site is still indexed by its old name")
+	}
+
+	// Stale snapshots are discarded
+	nbi.NetboxConfig.SnapshotMaxAge = 0
+	if nbi.loadSnapshot() != nil {
+		t.Errorf("loadSnapshot() should discard stale snapshot")
+	}
+}
+
+func TestScopedSnapshot(t *testing.T) {
+	// Device 1 has left the scope since the snapshot, so it is no longer returned by scoped queries
+	config := &parser.NetboxConfig{Snapshot: true, SnapshotDir: t.TempDir(), SnapshotMaxAge: 1, ScopedInit: true, ScopeSites: []string{"site1"}}
+	nbi := newTestInventory(t, config, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("last_updated__gte") != "" {
+			t.Errorf("scoped store collected changed objects %s", r.URL)
+		}
+		writeResults(w, []interface{}{})
+	})
+	nbi.devices.put(&objects.Device{NetboxObject: objects.NetboxObject{ID: 1}, Name: "device1", Site: &objects.Site{NetboxObject: objects.NetboxObject{ID: 1}}})
+	if err := nbi.saveSnapshot(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	nbi.snapshot = nbi.loadSnapshot()
+	if nbi.snapshot == nil {
+		t.Fatal("loadSnapshot() returned nil")
+	}
+	if _, ok := nbi.snapshot.Objects[service.DevicesAPIPath]; ok {
+		t.Errorf("objects of scoped store were saved to the snapshot")
+	}
+	if err := nbi.InitDevices(); err != nil {
+		t.Fatal(err)
+	}
+	if devices := nbi.devices.All(); len(devices) != 0 {
+		t.Errorf("devices = %v, want none", devices)
+	}
+}
+
+func TestDeletedSinceLegacyChangelog(t *testing.T) {
+	// Netbox older than 4.1, which serves changelog only under extras
+	nbi := newTestInventory(t, &parser.NetboxConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != service.LegacyObjectChangesAPIPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeResults(w, []objects.ObjectChange{
+			{ID: 1, Action: &objects.ObjectChangeActionDelete, ChangedObjectType: "dcim.site", ChangedObjectID: 3},
+		})
+	})
+	deleted, err := nbi.deletedSince(time.Now().Format(time.RFC3339))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deleted[service.SitesAPIPath][3] {
+		t.Errorf("deletedSince() = %v, want site 3", deleted)
+	}
+}
@@ -0,0 +1,454 @@
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// Filters of Netbox API, by which objects of scoped stores are scoped.
+const (
+	scopeSite   = "site"
+	scopeTenant = "tenant"
+)
+
+// netboxObject is a constraint satisfied by pointers to all netbox objects,
+// that embed objects.NetboxObject.
+type netboxObject[T any] interface {
+	*T
+	GetNetboxObject() *objects.NetboxObject
+}
+
+// Index is an index of objects of type T, where each object is indexed by
+// a key derived from the object itself.
+type Index[T any] interface {
+	// Get returns indexed object, that has the same key as obj.
+	Get(obj *T) (*T, bool)
+	// Put adds obj to the index.
+	Put(obj *T)
+	// Remove removes obj from the index, if it is indexed.
+	Remove(obj *T)
+	// Reset removes all objects from the index.
+	Reset()
+}
+
+// MapIndex indexes objects by a single key.
+type MapIndex[K comparable, T any] struct {
+	// Map points to the map of the index (usually an exported field of the inventory).
+	Map *map[K]*T
+	// Key returns key of the object. Objects for which ok is false are not indexed.
+	Key func(obj *T) (key K, ok bool)
+	// Match, if set, reports whether the indexed object can be matched to obj.
+	Match func(indexed, obj *T) bool
+	// Ambiguous keys, that are shared by multiple objects, are indexed with nil object
+	// and are never matched. Otherwise, the object indexed last is kept.
+	Ambiguous bool
+}
+
+func (idx *MapIndex[K, T]) Get(obj *T) (*T, bool) {
+	key, ok := idx.Key(obj)
+	if !ok {
+		return nil, false
+	}
+	indexed := (*idx.Map)[key]
+	if indexed == nil || (idx.Match != nil && !idx.Match(indexed, obj)) {
+		return nil, false
+	}
+	return indexed, true
+}
+
+func (idx *MapIndex[K, T]) Put(obj *T) {
+	key, ok := idx.Key(obj)
+	if !ok {
+		return
+	}
+	if *idx.Map == nil {
+		*idx.Map = make(map[K]*T)
+	}
+	if indexed, exists := (*idx.Map)[key]; exists && idx.Ambiguous && indexed != obj {
+		(*idx.Map)[key] = nil
+		return
+	}
+	(*idx.Map)[key] = obj
+}
+
+func (idx *MapIndex[K, T]) Remove(obj *T) {
+	if key, ok := idx.Key(obj); ok && (*idx.Map)[key] == obj {
+		delete(*idx.Map, key)
+	}
+}
+
+func (idx *MapIndex[K, T]) Reset() {
+	*idx.Map = make(map[K]*T)
+}
+
+// NestedMapIndex indexes objects by two keys (e.g. device id and name).
+type NestedMapIndex[K1, K2 comparable, T any] struct {
+	// Map points to the map of the index (usually an exported field of the inventory).
+	Map *map[K1]map[K2]*T
+	// Key returns keys of the object. Objects for which ok is false are not indexed.
+	Key func(obj *T) (key1 K1, key2 K2, ok bool)
+	// Match, if set, reports whether the indexed object can be matched to obj.
+	Match func(indexed, obj *T) bool
+}
+
+func (idx *NestedMapIndex[K1, K2, T]) Get(obj *T) (*T, bool) {
+	key1, key2, ok := idx.Key(obj)
+	if !ok {
+		return nil, false
+	}
+	indexed := (*idx.Map)[key1][key2]
+	if indexed == nil || (idx.Match != nil && !idx.Match(indexed, obj)) {
+		return nil, false
+	}
+	return indexed, true
+}
+
+func (idx *NestedMapIndex[K1, K2, T]) Put(obj *T) {
+	key1, key2, ok := idx.Key(obj)
+	if !ok {
+		return
+	}
+	if *idx.Map == nil {
+		*idx.Map = make(map[K1]map[K2]*T)
+	}
+	if (*idx.Map)[key1] == nil {
+		(*idx.Map)[key1] = make(map[K2]*T)
+	}
+	(*idx.Map)[key1][key2] = obj
+}
+
+func (idx *NestedMapIndex[K1, K2, T]) Remove(obj *T) {
+	if key1, key2, ok := idx.Key(obj); ok && (*idx.Map)[key1][key2] == obj {
+		delete((*idx.Map)[key1], key2)
+	}
+}
+
+func (idx *NestedMapIndex[K1, K2, T]) Reset() {
+	*idx.Map = make(map[K1]map[K2]*T)
+}
+
+// Store is a store of netbox objects of type T. It implements initialization, lookup,
+// diff, create, patch and orphan tracking of the objects once, so each object type
+// only needs a declaration of its store.
+type Store[T any, P netboxObject[T]] struct {
+	nbi *NetboxInventory
+	// Type of the objects, used for logging (e.g. "Device").
+	Type string
+	// APIPath of the objects, which is their key in the orphan manager.
+	APIPath string
+	// Name returns name of the object, used for logging.
+	Name func(obj *T) string
+	// Indexes of the objects. Existing objects are looked up in the indexes in order,
+	// so the first index has the highest priority.
+	Indexes []Index[T]
+	// Diff, if set, replaces the default diff of the new and the existing object.
+	Diff func(newObj, oldObj *T) (map[string]interface{}, error)
+	// NoOrphans disables orphan tracking of the objects.
+	NoOrphans bool
+	// Orphan, if set, reports whether the tagged obj is tracked by the orphan manager.
+	Orphan func(obj *T) bool
+	// Brief requests brief representation of the objects. It can be used only for objects,
+	// that are referenced by other objects, but are never added by the inventory.
+	Brief bool
+	// Lookup, if set, makes the store scoped in scoped init. It returns query params
+	// of targeted lookups (e.g. &name=...) of obj in Netbox API, which are used when
+	// obj is not found among the objects in scope.
+	Lookup func(obj *T) []string
+	// ScopeFilters (scopeSite, scopeTenant) supported by the objects' api path.
+	ScopeFilters []string
+	// objects are all objects in the store, indexed by their id.
+	objects map[int]*T
+}
+
+// Init collects all objects from Netbox API and stores them. Objects
+// tagged with the ssot tag are added to the orphan manager.
+func (s *Store[T, P]) Init() error {
+	nbObjects, err := s.fetch()
+	if err != nil {
+		return err
+	}
+	s.objects = make(map[int]*T, len(nbObjects))
+	for _, index := range s.Indexes {
+		index.Reset()
+	}
+	if !s.NoOrphans {
+		s.nbi.OrphanManager[s.APIPath] = make(map[int]bool)
+	}
+	for i := range nbObjects {
+		obj := &nbObjects[i]
+		s.put(obj)
+		if !s.NoOrphans && hasTag(P(obj).GetNetboxObject().Tags, s.nbi.SsotTag) && (s.Orphan == nil || s.Orphan(obj)) {
+			s.nbi.OrphanManager[s.APIPath][P(obj).GetNetboxObject().ID] = true
+		}
+	}
+	s.nbi.Logger.Debugf("Successfully collected %d objects of type %s from Netbox", len(s.objects), s.Type)
+	return nil
+}
+
+// fetch returns all objects from Netbox API (only objects in scope, if the store is scoped).
+// If the inventory has a snapshot of the objects, only objects changed since the snapshot
+// are fetched, and merged with the snapshot. Scoped stores never use the snapshot, because
+// objects, that have left the scope since the snapshot, are not returned by scoped queries.
+func (s *Store[T, P]) fetch() ([]T, error) {
+	snapshot := s.nbi.snapshot
+	if snapshot == nil || snapshot.Objects[s.APIPath] == nil || s.scoped() {
+		return s.fetchScoped("")
+	}
+	var snapshotObjects []T
+	if err := json.Unmarshal(snapshot.Objects[s.APIPath], &snapshotObjects); err != nil {
+		s.nbi.Logger.Warningf("failed parsing snapshot of %s objects: %s. Collecting all of them...", s.Type, err)
+		return s.fetchScoped("")
+	}
+	changedObjects, err := s.fetchScoped("&last_updated__gte=" + snapshot.since())
+	if err != nil {
+		return nil, err
+	}
+	nbObjects := make([]T, 0, len(snapshotObjects)+len(changedObjects))
+	changedIDs := make(map[int]bool, len(changedObjects))
+	for i := range changedObjects {
+		changedIDs[P(&changedObjects[i]).GetNetboxObject().ID] = true
+	}
+	for i := range snapshotObjects {
+		id := P(&snapshotObjects[i]).GetNetboxObject().ID
+		if !changedIDs[id] && !snapshot.deleted[s.APIPath][id] {
+			nbObjects = append(nbObjects, snapshotObjects[i])
+		}
+	}
+	nbObjects = append(nbObjects, changedObjects...)
+	s.nbi.Logger.Debugf("Collected %d changed objects of type %s from Netbox, since the snapshot", len(changedObjects), s.Type)
+	return nbObjects, nil
+}
+
+// fetchScoped returns objects from Netbox API filtered by extraParams. If the store is
+// scoped, only objects tagged with the ssot tag, or within the configured sites and tenants
+// are returned.
+func (s *Store[T, P]) fetchScoped(extraParams string) ([]T, error) {
+	if s.Brief {
+		extraParams += service.BriefParam
+	}
+	if !s.scoped() {
+		return service.GetAll[T](s.nbi.NetboxAPI, extraParams)
+	}
+	queries := []string{queryParam("tag", s.nbi.SsotTag.Slug)}
+	for _, filter := range s.ScopeFilters {
+		var values []string
+		switch filter {
+		case scopeSite:
+			values = s.nbi.NetboxConfig.ScopeSites
+		case scopeTenant:
+			values = s.nbi.NetboxConfig.ScopeTenants
+		}
+		if len(values) == 0 {
+			continue
+		}
+		query := ""
+		for _, value := range values {
+			query += queryParam(filter, value)
+		}
+		queries = append(queries, query)
+	}
+	return s.getAll(queries, extraParams)
+}
+
+// getAll returns objects from all queries to Netbox API, without duplicates.
+func (s *Store[T, P]) getAll(queries []string, extraParams string) ([]T, error) {
+	var nbObjects []T
+	ids := make(map[int]bool)
+	for _, query := range queries {
+		queryObjects, err := service.GetAll[T](s.nbi.NetboxAPI, query+extraParams)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range queryObjects {
+			if id := P(&obj).GetNetboxObject().ID; !ids[id] {
+				ids[id] = true
+				nbObjects = append(nbObjects, obj)
+			}
+		}
+	}
+	return nbObjects, nil
+}
+
+// scoped returns true if only objects in scope of the inventory are collected during init,
+// and other objects are looked up on demand.
+func (s *Store[T, P]) scoped() bool {
+	return s.nbi.NetboxConfig.ScopedInit && s.Lookup != nil
+}
+
+// lookup looks up obj in Netbox API and stores the found objects.
+func (s *Store[T, P]) lookup(obj *T) {
+	s.nbi.Logger.Debugf("%s %s is not in scope of the inventory. Looking it up in Netbox...", s.Type, s.Name(obj))
+	if err := s.collect(s.Lookup(obj)); err != nil {
+		s.nbi.Logger.Warningf("failed looking up %s %s: %s", s.Type, s.Name(obj), err)
+	}
+}
+
+// collect collects objects of all queries from Netbox API and stores them. It is used
+// by scoped stores, to collect objects outside of the scope.
+func (s *Store[T, P]) collect(queries []string) error {
+	nbObjects, err := s.getAll(queries, "")
+	if err != nil {
+		return err
+	}
+	for i := range nbObjects {
+		s.put(&nbObjects[i])
+	}
+	return nil
+}
+
+// saveTo saves all objects of the store to the snapshot. Objects of scoped stores
+// are not saved, since they are always collected from Netbox.
+func (s *Store[T, P]) saveTo(snapshot *Snapshot) error {
+	if s.scoped() {
+		return nil
+	}
+	content, err := json.Marshal(s.All())
+	if err != nil {
+		return fmt.Errorf("snapshot of %s objects: %s", s.Type, err)
+	}
+	snapshot.Objects[s.APIPath] = content
+	return nil
+}
+
+// Get returns the existing object, that matches obj in any of the indexes.
+// If the store is scoped, obj is looked up in Netbox API, when it is not found.
+func (s *Store[T, P]) Get(obj *T) (*T, bool) {
+	if existingObj, ok := s.get(obj); ok || !s.scoped() {
+		return existingObj, ok
+	}
+	s.lookup(obj)
+	return s.get(obj)
+}
+
+// get returns the existing object, that matches obj in any of the indexes.
+func (s *Store[T, P]) get(obj *T) (*T, bool) {
+	for _, index := range s.Indexes {
+		if existingObj, ok := index.Get(obj); ok {
+			return existingObj, true
+		}
+	}
+	return nil, false
+}
+
+// All returns all objects in the store ordered by their id.
+func (s *Store[T, P]) All() []*T {
+	all := make([]*T, 0, len(s.objects))
+	for _, obj := range s.objects {
+		all = append(all, obj)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return P(all[i]).GetNetboxObject().ID < P(all[j]).GetNetboxObject().ID
+	})
+	return all
+}
+
+// Add adds newObj to netbox. If the object already exists, it is patched if it is out of date,
+// and removed from the orphan manager, because it still exists in the sources. Otherwise it is created.
+func (s *Store[T, P]) Add(newObj *T) (*T, error) {
+	P(newObj).GetNetboxObject().Tags = withTag(P(newObj).GetNetboxObject().Tags, s.nbi.SsotTag)
+	oldObj, ok := s.Get(newObj)
+	if !ok {
+		s.nbi.Logger.Debugf("%s %s does not exist in Netbox. Creating it...", s.Type, s.Name(newObj))
+		createdObj, err := service.Create[T](s.nbi.NetboxAPI, newObj)
+		if err != nil {
+			return nil, err
+		}
+		s.put(createdObj)
+		return createdObj, nil
+	}
+	delete(s.nbi.OrphanManager[s.APIPath], P(oldObj).GetNetboxObject().ID)
+	var diffMap map[string]interface{}
+	var err error
+	if s.Diff != nil {
+		diffMap, err = s.Diff(newObj, oldObj)
+	} else {
+		diffMap, err = utils.JSONDiffMapExceptID(newObj, oldObj, false, s.nbi.SourcePriority)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(diffMap) == 0 {
+		s.nbi.Logger.Debugf("%s %s already exists in Netbox and is up to date...", s.Type, s.Name(newObj))
+		return oldObj, nil
+	}
+	if oldName, newName := s.Name(oldObj), s.Name(newObj); oldName != newName {
+		s.nbi.Logger.Infof("%s %s was changed to %s in the source. Patching it...", s.Type, oldName, newName)
+	} else {
+		s.nbi.Logger.Debugf("%s %s already exists in Netbox but is out of date. Patching it...", s.Type, newName)
+	}
+	return s.patch(oldObj, diffMap)
+}
+
+// patch patches oldObj with diffMap and replaces it with the patched object.
+func (s *Store[T, P]) patch(oldObj *T, diffMap map[string]interface{}) (*T, error) {
+	patchedObj, err := service.Patch[T](s.nbi.NetboxAPI, P(oldObj).GetNetboxObject().ID, diffMap)
+	if err != nil {
+		return nil, err
+	}
+	s.put(patchedObj)
+	return patchedObj, nil
+}
+
+// put adds obj to the store, replacing the object with the same id.
+func (s *Store[T, P]) put(obj *T) {
+	if s.objects == nil {
+		s.objects = make(map[int]*T)
+	}
+	id := P(obj).GetNetboxObject().ID
+	if oldObj, ok := s.objects[id]; ok {
+		for _, index := range s.Indexes {
+			index.Remove(oldObj)
+		}
+	}
+	s.objects[id] = obj
+	for _, index := range s.Indexes {
+		index.Put(obj)
+	}
+}
+
+// delete deletes obj from Netbox and removes it from the store.
+func (s *Store[T, P]) delete(obj *T) error {
+	id := P(obj).GetNetboxObject().ID
+	if err := s.nbi.NetboxAPI.BulkDeleteObjects(s.APIPath, map[int]bool{id: true}); err != nil {
+		return err
+	}
+	delete(s.objects, id)
+	delete(s.nbi.OrphanManager[s.APIPath], id)
+	for _, index := range s.Indexes {
+		index.Remove(obj)
+	}
+	return nil
+}
+
+// queryParam returns url encoded query param of Netbox API (e.g. &name=value).
+func queryParam(key string, value string) string {
+	return fmt.Sprintf("&%s=%s", key, url.QueryEscape(value))
+}
+
+// hasTag returns true if tags contain tag.
+func hasTag(tags []*objects.Tag, tag *objects.Tag) bool {
+	for _, t := range tags {
+		if t.Slug == tag.Slug {
+			return true
+		}
+	}
+	return false
+}
+
+// withTag returns tags with tag appended, unless it is already present. The result is
+// always a new slice, because tags are often shared between objects (e.g. source tags),
+// so appending to them could modify tags of other objects.
+func withTag(tags []*objects.Tag, tag *objects.Tag) []*objects.Tag {
+	newTags := make([]*objects.Tag, 0, len(tags)+1)
+	newTags = append(newTags, tags...)
+	if !hasTag(tags, tag) {
+		newTags = append(newTags, tag)
+	}
+	return newTags
+}
@@ -0,0 +1,178 @@
+package inventory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+)
+
+// newTestInventory returns an inventory, that uses Netbox API served by handler.
+func newTestInventory(t *testing.T, config *parser.NetboxConfig, handler http.HandlerFunc) *NetboxInventory {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi := NewNetboxInventory(testLogger, config)
+	nbi.NetboxAPI = service.NewNetBoxAPI(testLogger, server.URL, "token", false, 5)
+	nbi.SsotTag = &objects.Tag{ID: 1, Name: "netbox-ssot", Slug: "netbox-ssot"}
+	return nbi
+}
+
+// writeResults writes results as a single page response of Netbox API.
+func writeResults(w http.ResponseWriter, results interface{}) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"count": 0, "next": nil, "previous": nil, "results": results})
+}
+
+func TestMapIndexAmbiguous(t *testing.T) {
+	var m map[string]*objects.Device
+	idx := &MapIndex[string, objects.Device]{
+		Map:       &m,
+		Key:       func(device *objects.Device) (string, bool) { return device.SerialNumber, device.SerialNumber != "" },
+		Ambiguous: true,
+	}
+	device1 := &objects.Device{Name: "device1", SerialNumber: "123"}
+	device2 := &objects.Device{Name: "device2", SerialNumber: "123"}
+	device3 := &objects.Device{Name: "device3"}
+
+	idx.Put(device1)
+	if got, ok := idx.Get(&objects.Device{SerialNumber: "123"}); !ok || got != device1 {
+		t.Errorf("Get() = %v, %t, want device1", got, ok)
+	}
+	idx.Put(device1)
+	if _, ok := idx.Get(&objects.Device{SerialNumber: "123"}); !ok {
+		t.Errorf("Get() after putting the same device twice should match")
+	}
+	idx.Put(device2)
+	if got, ok := idx.Get(&objects.Device{SerialNumber: "123"}); ok {
+		t.Errorf("Get() = %v, ambiguous key should not match", got)
+	}
+	idx.Put(device3)
+	if _, ok := idx.Get(device3); ok {
+		t.Errorf("Get() should not match objects without key")
+	}
+}
+
+func TestNestedMapIndex(t *testing.T) {
+	var m map[int]map[string]*objects.Interface
+	idx := &NestedMapIndex[int, string, objects.Interface]{
+		Map: &m,
+		Key: func(intf *objects.Interface) (int, string, bool) { return intf.Device.ID, intf.Name, true },
+		Match: func(indexed, intf *objects.Interface) bool {
+			return indexed.Type == nil || intf.Type == nil || indexed.Type.Value == intf.Type.Value
+		},
+	}
+	device := &objects.Device{NetboxObject: objects.NetboxObject{ID: 1}}
+	intf := &objects.Interface{Name: "eth0", Device: device, Type: &objects.OtherInterfaceType}
+	idx.Put(intf)
+	if got, ok := idx.Get(&objects.Interface{Name: "eth0", Device: device}); !ok || got != intf {
+		t.Errorf("Get() = %v, %t, want eth0", got, ok)
+	}
+	if _, ok := idx.Get(&objects.Interface{Name: "eth0", Device: device, Type: &objects.VirtualInterfaceType}); ok {
+		t.Errorf("Get() should not match, when Match returns false")
+	}
+	idx.Remove(&objects.Interface{Name: "eth0", Device: device})
+	if _, ok := idx.Get(intf); !ok {
+		t.Errorf("Remove() should only remove the indexed object")
+	}
+	idx.Remove(intf)
+	if _, ok := idx.Get(intf); ok {
+		t.Errorf("Get() should not match removed object")
+	}
+}
+
+func TestWithTag(t *testing.T) {
+	ssotTag := &objects.Tag{Name: "netbox-ssot", Slug: "netbox-ssot"}
+	sourceTag := &objects.Tag{Name: "source", Slug: "source"}
+	sourceTags := make([]*objects.Tag, 1, 2)
+	sourceTags[0] = sourceTag
+
+	tags1 := withTag(sourceTags, ssotTag)
+	tags2 := withTag(sourceTags, &objects.Tag{Name: "other", Slug: "other"})
+	if len(tags1) != 2 || tags1[1] != ssotTag {
+		t.Errorf("withTag() = %v, want [source netbox-ssot]", tags1)
+	}
+	if len(tags2) != 2 || tags2[1].Slug != "other" {
+		t.Errorf("withTag() = %v, want [source other]", tags2)
+	}
+	if tags := withTag(tags1, ssotTag); len(tags) != 2 {
+		t.Errorf("withTag() = %v, tag should not be added twice", tags)
+	}
+}
+
+func TestScopedStore(t *testing.T) {
+	site := &objects.Site{NetboxObject: objects.NetboxObject{ID: 1}, Name: "site1", Slug: "site1"}
+	ssotTag := &objects.Tag{ID: 1, Name: "netbox-ssot", Slug: "netbox-ssot"}
+	device := func(id int, name string) objects.Device {
+		return objects.Device{NetboxObject: objects.NetboxObject{ID: id, Tags: []*objects.Tag{ssotTag}}, Name: name, Site: site}
+	}
+	var queries []url.Values
+	nbi := newTestInventory(t, &parser.NetboxConfig{ScopedInit: true, ScopeSites: []string{"site1"}}, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		queries = append(queries, query)
+		switch {
+		case r.URL.Path != service.DevicesAPIPath:
+			t.Errorf("unexpected request %s", r.URL)
+		case query.Get("tag") == "netbox-ssot":
+			writeResults(w, []objects.Device{device(1, "device1")})
+			return
+		case query.Get("site") == "site1":
+			writeResults(w, []objects.Device{device(1, "device1"), device(2, "device2")})
+			return
+		case query.Get("name") == "device 3" && query.Get("site_id") == "1":
+			writeResults(w, []objects.Device{device(3, "device 3")})
+			return
+		case query.Get("name") != "":
+		default:
+			t.Errorf("unscoped request %s", r.URL)
+		}
+		writeResults(w, []objects.Device{})
+	})
+	nbi.SsotTag = ssotTag
+	if err := nbi.InitDevices(); err != nil {
+		t.Fatal(err)
+	}
+	if len(nbi.devices.All()) != 2 {
+		t.Errorf("collected %d devices, want 2", len(nbi.devices.All()))
+	}
+
+	// Device outside of the scope is looked up on demand, instead of being created
+	newDevice := device(0, "device 3")
+	existingDevice, err := nbi.AddDevice(&newDevice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existingDevice.ID != 3 {
+		t.Errorf("AddDevice() returned device %d, want 3", existingDevice.ID)
+	}
+	if lastQuery := queries[len(queries)-1]; lastQuery.Get("name") != "device 3" || lastQuery.Get("site_id") != "1" {
+		t.Errorf("lookup query = %s", lastQuery.Encode())
+	}
+}
+
+func TestSiteOrphans(t *testing.T) {
+	for _, removeOrphanSites := range []bool{false, true} {
+		nbi := newTestInventory(t, &parser.NetboxConfig{RemoveOrphanSites: removeOrphanSites}, func(w http.ResponseWriter, _ *http.Request) {
+			writeResults(w, []objects.Site{
+				{NetboxObject: objects.NetboxObject{ID: 1, Tags: []*objects.Tag{{ID: 1, Name: "netbox-ssot", Slug: "netbox-ssot"}}}, Name: "site1"},
+				{NetboxObject: objects.NetboxObject{ID: 2}, Name: "site2"},
+			})
+		})
+		if err := nbi.InitSites(); err != nil {
+			t.Fatal(err)
+		}
+		// Tagged sites can be created by hand, so they are tracked only when enabled
+		if orphans := nbi.OrphanManager[service.SitesAPIPath]; orphans == nil || orphans[1] != removeOrphanSites || orphans[2] {
+			t.Errorf("orphaned sites with removeOrphanSites %t = %v", removeOrphanSites, orphans)
+		}
+	}
+}
@@ -0,0 +1,560 @@
+package inventory
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+)
+
+// stores of all netbox objects, that are managed by the inventory.
+// Indexes of the stores are exported fields of the inventory.
+type stores struct {
+	tenants            *Store[objects.Tenant, *objects.Tenant]
+	contactGroups      *Store[objects.ContactGroup, *objects.ContactGroup]
+	contactRoles       *Store[objects.ContactRole, *objects.ContactRole]
+	contacts           *Store[objects.Contact, *objects.Contact]
+	contactAssignments *Store[objects.ContactAssignment, *objects.ContactAssignment]
+	regions            *Store[objects.Region, *objects.Region]
+	sites              *Store[objects.Site, *objects.Site]
+	locations          *Store[objects.Location, *objects.Location]
+	manufacturers      *Store[objects.Manufacturer, *objects.Manufacturer]
+	platforms          *Store[objects.Platform, *objects.Platform]
+	deviceRoles        *Store[objects.DeviceRole, *objects.DeviceRole]
+	deviceTypes        *Store[objects.DeviceType, *objects.DeviceType]
+	devices            *Store[objects.Device, *objects.Device]
+	virtualChassis     *Store[objects.VirtualChassis, *objects.VirtualChassis]
+	inventoryItems     *Store[objects.InventoryItem, *objects.InventoryItem]
+	interfaces         *Store[objects.Interface, *objects.Interface]
+	powerPorts         *Store[objects.PowerPort, *objects.PowerPort]
+	cables             *Store[objects.Cable, *objects.Cable]
+	vrfs               *Store[objects.VRF, *objects.VRF]
+	ipAddresses        *Store[objects.IPAddress, *objects.IPAddress]
+	prefixes           *Store[objects.Prefix, *objects.Prefix]
+	vlanGroups         *Store[objects.VlanGroup, *objects.VlanGroup]
+	vlans              *Store[objects.Vlan, *objects.Vlan]
+	wirelessLANs       *Store[objects.WirelessLAN, *objects.WirelessLAN]
+	clusterGroups      *Store[objects.ClusterGroup, *objects.ClusterGroup]
+	clusterTypes       *Store[objects.ClusterType, *objects.ClusterType]
+	clusters           *Store[objects.Cluster, *objects.Cluster]
+	vms                *Store[objects.VM, *objects.VM]
+	vmInterfaces       *Store[objects.VMInterface, *objects.VMInterface]
+	virtualDisks       *Store[objects.VirtualDisk, *objects.VirtualDisk]
+}
+
+// snapshotStores returns all stores, whose objects are persisted in the snapshot.
+func (s *stores) snapshotStores() []snapshotStore {
+	return []snapshotStore{
+		s.tenants, s.contactGroups, s.contactRoles, s.contacts, s.contactAssignments,
+		s.regions, s.sites, s.locations, s.manufacturers, s.platforms,
+		s.deviceRoles, s.deviceTypes, s.devices, s.virtualChassis, s.inventoryItems,
+		s.interfaces, s.powerPorts, s.cables, s.vrfs, s.ipAddresses,
+		s.prefixes, s.vlanGroups, s.vlans, s.wirelessLANs, s.clusterGroups,
+		s.clusterTypes, s.clusters, s.vms, s.vmInterfaces, s.virtualDisks,
+	}
+}
+
+// byName returns an index of objects by their name.
+func byName[T any](m *map[string]*T, name func(obj *T) string) *MapIndex[string, T] {
+	return &MapIndex[string, T]{Map: m, Key: func(obj *T) (string, bool) { return name(obj), true }}
+}
+
+// bySourceID returns an index of objects by their source and source_id custom fields.
+// Objects without them are not indexed.
+func bySourceID[T any, P netboxObject[T]](m *map[string]map[string]*T, match func(indexed, obj *T) bool) *NestedMapIndex[string, string, T] {
+	return &NestedMapIndex[string, string, T]{
+		Map: m,
+		Key: func(obj *T) (string, string, bool) {
+			source, id := sourceID(*P(obj).GetNetboxObject())
+			return source, id, id != ""
+		},
+		Match: match,
+	}
+}
+
+// sourceIDQuery returns query params for a lookup of the object by its source
+// and source_id custom fields. If the object doesn't have them, ok is false.
+func sourceIDQuery(obj objects.NetboxObject) (query string, ok bool) {
+	source, id := sourceID(obj)
+	if id == "" {
+		return "", false
+	}
+	return queryParam("cf_"+constants.CustomFieldSourceName, source) + queryParam("cf_"+constants.CustomFieldSourceIDName, id), true
+}
+
+// notFromSameSource reports whether device can be matched to the indexed device by
+// serial number or asset tag. Devices with the same source, but different source_id are never matched.
+func notFromSameSource(indexed, device *objects.Device) bool {
+	indexedSource, indexedID := sourceID(indexed.NetboxObject)
+	source, id := sourceID(device.NetboxObject)
+	return indexedSource != source || indexedID == "" || indexedID == id
+}
+
+// declareStores declares stores of all objects managed by the inventory.
+func (nbi *NetboxInventory) declareStores() {
+	nbi.tenants = &Store[objects.Tenant, *objects.Tenant]{
+		nbi: nbi, Type: "Tenant", APIPath: service.TenantsAPIPath, NoOrphans: true, Brief: true,
+		Name: func(tenant *objects.Tenant) string { return tenant.Name },
+		Indexes: []Index[objects.Tenant]{
+			byName(&nbi.TenantsIndexByName, func(tenant *objects.Tenant) string { return tenant.Name }),
+		},
+	}
+	nbi.contactGroups = &Store[objects.ContactGroup, *objects.ContactGroup]{
+		nbi: nbi, Type: "Contact group", APIPath: service.ContactGroupsAPIPath, NoOrphans: true,
+		Name: func(contactGroup *objects.ContactGroup) string { return contactGroup.Name },
+		Indexes: []Index[objects.ContactGroup]{
+			byName(&nbi.ContactGroupsIndexByName, func(contactGroup *objects.ContactGroup) string { return contactGroup.Name }),
+		},
+	}
+	nbi.contactRoles = &Store[objects.ContactRole, *objects.ContactRole]{
+		nbi: nbi, Type: "Contact role", APIPath: service.ContactRolesAPIPath, NoOrphans: true,
+		Name: func(contactRole *objects.ContactRole) string { return contactRole.Name },
+		Indexes: []Index[objects.ContactRole]{
+			byName(&nbi.ContactRolesIndexByName, func(contactRole *objects.ContactRole) string { return contactRole.Name }),
+		},
+	}
+	nbi.contacts = &Store[objects.Contact, *objects.Contact]{
+		nbi: nbi, Type: "Contact", APIPath: service.ContactsAPIPath,
+		Name: func(contact *objects.Contact) string { return contact.Name },
+		Indexes: []Index[objects.Contact]{
+			byName(&nbi.ContactsIndexByName, func(contact *objects.Contact) string { return contact.Name }),
+		},
+	}
+	nbi.contactAssignments = &Store[objects.ContactAssignment, *objects.ContactAssignment]{
+		nbi: nbi, Type: "Contact assignment", APIPath: service.ContactAssignmentsAPIPath,
+		Name: func(ca *objects.ContactAssignment) string {
+			return fmt.Sprintf("%s %d (contact %d, role %d)", ca.ContentType, ca.ObjectID, ca.Contact.ID, ca.Role.ID)
+		},
+		Indexes: []Index[objects.ContactAssignment]{&contactAssignmentIndex{nbi: nbi}},
+	}
+	nbi.regions = &Store[objects.Region, *objects.Region]{
+		nbi: nbi, Type: "Region", APIPath: service.RegionsAPIPath,
+		Name: func(region *objects.Region) string { return region.Name },
+		Indexes: []Index[objects.Region]{
+			// Netbox allows regions with the same name under different parents
+			&NestedMapIndex[int, string, objects.Region]{
+				Map: &nbi.RegionsIndexByParentIDAndName,
+				Key: func(region *objects.Region) (int, string, bool) {
+					if region.Parent == nil {
+						return 0, region.Name, true
+					}
+					return region.Parent.ID, region.Name, true
+				},
+			},
+		},
+	}
+	nbi.sites = &Store[objects.Site, *objects.Site]{
+		nbi: nbi, Type: "Site", APIPath: service.SitesAPIPath,
+		Name: func(site *objects.Site) string { return site.Name },
+		Indexes: []Index[objects.Site]{
+			byName(&nbi.SitesIndexByName, func(site *objects.Site) string { return site.Name }),
+		},
+		// Sites matched by name were tagged also when they were created by hand,
+		// so they are removed only when explicitly enabled
+		Orphan: func(*objects.Site) bool { return nbi.NetboxConfig.RemoveOrphanSites },
+	}
+	nbi.locations = &Store[objects.Location, *objects.Location]{
+		nbi: nbi, Type: "Location", APIPath: service.LocationsAPIPath,
+		Name: func(location *objects.Location) string { return location.Name },
+		Indexes: []Index[objects.Location]{
+			&NestedMapIndex[int, string, objects.Location]{
+				Map: &nbi.LocationsIndexBySiteIDAndName,
+				Key: func(location *objects.Location) (int, string, bool) { return location.Site.ID, location.Name, true },
+			},
+		},
+	}
+	nbi.manufacturers = &Store[objects.Manufacturer, *objects.Manufacturer]{
+		nbi: nbi, Type: "Manufacturer", APIPath: service.ManufacturersAPIPath,
+		Name: func(manufacturer *objects.Manufacturer) string { return manufacturer.Name },
+		Indexes: []Index[objects.Manufacturer]{
+			byName(&nbi.ManufacturersIndexByName, func(manufacturer *objects.Manufacturer) string { return manufacturer.Name }),
+		},
+	}
+	nbi.platforms = &Store[objects.Platform, *objects.Platform]{
+		nbi: nbi, Type: "Platform", APIPath: service.PlatformsAPIPath,
+		Name: func(platform *objects.Platform) string { return platform.Name },
+		Indexes: []Index[objects.Platform]{
+			byName(&nbi.PlatformsIndexByName, func(platform *objects.Platform) string { return platform.Name }),
+		},
+	}
+	nbi.deviceRoles = &Store[objects.DeviceRole, *objects.DeviceRole]{
+		nbi: nbi, Type: "Device role", APIPath: service.DeviceRolesAPIPath,
+		Name: func(deviceRole *objects.DeviceRole) string { return deviceRole.Name },
+		Indexes: []Index[objects.DeviceRole]{
+			byName(&nbi.DeviceRolesIndexByName, func(deviceRole *objects.DeviceRole) string { return deviceRole.Name }),
+		},
+	}
+	nbi.deviceTypes = &Store[objects.DeviceType, *objects.DeviceType]{
+		nbi: nbi, Type: "Device type", APIPath: service.DeviceTypesAPIPath,
+		Name: func(deviceType *objects.DeviceType) string { return deviceType.Model },
+		Indexes: []Index[objects.DeviceType]{
+			byName(&nbi.DeviceTypesIndexByModel, func(deviceType *objects.DeviceType) string { return deviceType.Model }),
+		},
+	}
+	// Devices are matched by source id, name and site, serial number and asset tag,
+	// so renamed devices are patched instead of creating new ones.
+	nbi.devices = &Store[objects.Device, *objects.Device]{
+		nbi: nbi, Type: "Device", APIPath: service.DevicesAPIPath,
+		Name:         func(device *objects.Device) string { return device.Name },
+		ScopeFilters: []string{scopeSite, scopeTenant},
+		Lookup: func(device *objects.Device) []string {
+			queries := []string{}
+			if device.Site != nil {
+				queries = append(queries, queryParam("name", device.Name)+fmt.Sprintf("&site_id=%d", device.Site.ID))
+			}
+			if query, ok := sourceIDQuery(device.NetboxObject); ok {
+				queries = append(queries, query)
+			}
+			if device.SerialNumber != "" {
+				queries = append(queries, queryParam("serial", device.SerialNumber))
+			}
+			if device.AssetTag != "" {
+				queries = append(queries, queryParam("asset_tag", device.AssetTag))
+			}
+			return queries
+		},
+		Indexes: []Index[objects.Device]{
+			bySourceID[objects.Device](&nbi.DevicesIndexBySourceAndSourceID, nil),
+			&NestedMapIndex[string, int, objects.Device]{
+				Map: &nbi.DevicesIndexByNameAndSiteID,
+				Key: func(device *objects.Device) (string, int, bool) {
+					if device.Site == nil {
+						return "", 0, false
+					}
+					return device.Name, device.Site.ID, true
+				},
+			},
+			&MapIndex[string, objects.Device]{
+				Map: &nbi.DevicesIndexBySerialNumber,
+				Key: func(device *objects.Device) (string, bool) {
+					return strings.ToLower(device.SerialNumber), device.SerialNumber != ""
+				},
+				Match:     notFromSameSource,
+				Ambiguous: true,
+			},
+			&MapIndex[string, objects.Device]{
+				Map:       &nbi.DevicesIndexByAssetTag,
+				Key:       func(device *objects.Device) (string, bool) { return device.AssetTag, device.AssetTag != "" },
+				Match:     notFromSameSource,
+				Ambiguous: true,
+			},
+		},
+	}
+	nbi.virtualChassis = &Store[objects.VirtualChassis, *objects.VirtualChassis]{
+		nbi: nbi, Type: "Virtual chassis", APIPath: service.VirtualChassisAPIPath,
+		Name: func(virtualChassis *objects.VirtualChassis) string { return virtualChassis.Name },
+		Indexes: []Index[objects.VirtualChassis]{
+			byName(&nbi.VirtualChassisIndexByName, func(virtualChassis *objects.VirtualChassis) string { return virtualChassis.Name }),
+		},
+	}
+	nbi.inventoryItems = &Store[objects.InventoryItem, *objects.InventoryItem]{
+		nbi: nbi, Type: "Inventory item", APIPath: service.InventoryItemsAPIPath,
+		Name:         func(inventoryItem *objects.InventoryItem) string { return inventoryItem.Name },
+		ScopeFilters: []string{scopeSite},
+		Lookup: func(inventoryItem *objects.InventoryItem) []string {
+			return []string{fmt.Sprintf("&device_id=%d", inventoryItem.Device.ID) + queryParam("name", inventoryItem.Name)}
+		},
+		Indexes: []Index[objects.InventoryItem]{
+			&NestedMapIndex[int, string, objects.InventoryItem]{
+				Map: &nbi.InventoryItemsIndexByDeviceIDAndName,
+				Key: func(inventoryItem *objects.InventoryItem) (int, string, bool) {
+					return inventoryItem.Device.ID, inventoryItem.Name, true
+				},
+			},
+		},
+	}
+	// Interfaces are matched by source id (within the same device) and by name.
+	nbi.interfaces = &Store[objects.Interface, *objects.Interface]{
+		nbi: nbi, Type: "Interface", APIPath: service.InterfacesAPIPath,
+		Name:         func(intf *objects.Interface) string { return intf.Name },
+		ScopeFilters: []string{scopeSite},
+		Lookup: func(intf *objects.Interface) []string {
+			deviceQuery := fmt.Sprintf("&device_id=%d", intf.Device.ID)
+			queries := []string{deviceQuery + queryParam("name", intf.Name)}
+			if query, ok := sourceIDQuery(intf.NetboxObject); ok {
+				queries = append(queries, deviceQuery+query)
+			}
+			return queries
+		},
+		Indexes: []Index[objects.Interface]{
+			bySourceID[objects.Interface](&nbi.InterfacesIndexBySourceAndSourceID, func(indexed, intf *objects.Interface) bool {
+				return indexed.Device.ID == intf.Device.ID
+			}),
+			&NestedMapIndex[int, string, objects.Interface]{
+				Map: &nbi.InterfacesIndexByDeviceIDAndName,
+				Key: func(intf *objects.Interface) (int, string, bool) { return intf.Device.ID, intf.Name, true },
+			},
+		},
+	}
+	nbi.powerPorts = &Store[objects.PowerPort, *objects.PowerPort]{
+		nbi: nbi, Type: "Power port", APIPath: service.PowerPortsAPIPath,
+		Name:         func(powerPort *objects.PowerPort) string { return powerPort.Name },
+		ScopeFilters: []string{scopeSite},
+		Lookup: func(powerPort *objects.PowerPort) []string {
+			return []string{fmt.Sprintf("&device_id=%d", powerPort.Device.ID) + queryParam("name", powerPort.Name)}
+		},
+		Indexes: []Index[objects.PowerPort]{
+			&NestedMapIndex[int, string, objects.PowerPort]{
+				Map: &nbi.PowerPortsIndexByDeviceIDAndName,
+				Key: func(powerPort *objects.PowerPort) (int, string, bool) {
+					return powerPort.Device.ID, powerPort.Name, true
+				},
+			},
+		},
+	}
+	nbi.cables = &Store[objects.Cable, *objects.Cable]{
+		nbi: nbi, Type: "Cable", APIPath: service.CablesAPIPath,
+		Name:         cableName,
+		ScopeFilters: []string{scopeSite, scopeTenant},
+		Lookup: func(cable *objects.Cable) []string {
+			queries := []string{}
+			for _, id := range interfaceIDs(cable) {
+				queries = append(queries, fmt.Sprintf("&interface_id=%d", id))
+			}
+			return queries
+		},
+		Indexes: []Index[objects.Cable]{&cableIndex{nbi: nbi}},
+		Diff:    nbi.cableDiff,
+	}
+	nbi.vrfs = &Store[objects.VRF, *objects.VRF]{
+		nbi: nbi, Type: "VRF", APIPath: service.VRFsAPIPath,
+		Name: func(vrf *objects.VRF) string { return vrf.Name },
+		Indexes: []Index[objects.VRF]{
+			byName(&nbi.VRFsIndexByName, func(vrf *objects.VRF) string { return vrf.Name }),
+		},
+	}
+	nbi.ipAddresses = &Store[objects.IPAddress, *objects.IPAddress]{
+		nbi: nbi, Type: "IP address", APIPath: service.IPAddressesAPIPath,
+		Name:         func(ipAddress *objects.IPAddress) string { return ipAddress.Address },
+		ScopeFilters: []string{scopeTenant},
+		Lookup: func(ipAddress *objects.IPAddress) []string {
+			return []string{queryParam("address", ipAddress.Address)}
+		},
+		Indexes: []Index[objects.IPAddress]{
+			&NestedMapIndex[int, string, objects.IPAddress]{
+				Map: &nbi.IPAddressesIndexByVRFIDAndAddress,
+				Key: func(ipAddress *objects.IPAddress) (int, string, bool) {
+					return vrfID(ipAddress.VRF), ipAddress.Address, true
+				},
+			},
+		},
+	}
+	nbi.prefixes = &Store[objects.Prefix, *objects.Prefix]{
+		nbi: nbi, Type: "Prefix", APIPath: service.PrefixesAPIPath,
+		Name:         func(prefix *objects.Prefix) string { return prefix.Prefix },
+		ScopeFilters: []string{scopeSite, scopeTenant},
+		Lookup: func(prefix *objects.Prefix) []string {
+			return []string{queryParam("prefix", prefix.Prefix)}
+		},
+		Indexes: []Index[objects.Prefix]{
+			&NestedMapIndex[int, string, objects.Prefix]{
+				Map: &nbi.PrefixesIndexByVRFIDAndPrefix,
+				Key: func(prefix *objects.Prefix) (int, string, bool) { return vrfID(prefix.VRF), prefix.Prefix, true },
+			},
+		},
+	}
+	nbi.vlanGroups = &Store[objects.VlanGroup, *objects.VlanGroup]{
+		nbi: nbi, Type: "Vlan group", APIPath: service.VlanGroupsAPIPath,
+		Name: func(vlanGroup *objects.VlanGroup) string { return vlanGroup.Name },
+		Indexes: []Index[objects.VlanGroup]{
+			byName(&nbi.VlanGroupsIndexByName, func(vlanGroup *objects.VlanGroup) string { return vlanGroup.Name }),
+		},
+	}
+	// Vlans without vlan group are not indexed, until they are moved to the default vlan group.
+	nbi.vlans = &Store[objects.Vlan, *objects.Vlan]{
+		nbi: nbi, Type: "Vlan", APIPath: service.VlansAPIPath,
+		Name: func(vlan *objects.Vlan) string { return vlan.Name },
+		Indexes: []Index[objects.Vlan]{
+			&NestedMapIndex[int, int, objects.Vlan]{
+				Map: &nbi.VlansIndexByVlanGroupIDAndVID,
+				Key: func(vlan *objects.Vlan) (int, int, bool) {
+					if vlan.Group == nil {
+						return 0, 0, false
+					}
+					return vlan.Group.ID, vlan.Vid, true
+				},
+			},
+		},
+	}
+	nbi.wirelessLANs = &Store[objects.WirelessLAN, *objects.WirelessLAN]{
+		nbi: nbi, Type: "Wireless LAN", APIPath: service.WirelessLANsAPIPath,
+		Name: func(wirelessLAN *objects.WirelessLAN) string { return wirelessLAN.SSID },
+		Indexes: []Index[objects.WirelessLAN]{
+			byName(&nbi.WirelessLANsIndexBySSID, func(wirelessLAN *objects.WirelessLAN) string { return wirelessLAN.SSID }),
+		},
+	}
+	nbi.clusterGroups = &Store[objects.ClusterGroup, *objects.ClusterGroup]{
+		nbi: nbi, Type: "Cluster group", APIPath: service.ClusterGroupsAPIPath,
+		Name: func(clusterGroup *objects.ClusterGroup) string { return clusterGroup.Name },
+		Indexes: []Index[objects.ClusterGroup]{
+			byName(&nbi.ClusterGroupsIndexByName, func(clusterGroup *objects.ClusterGroup) string { return clusterGroup.Name }),
+		},
+	}
+	nbi.clusterTypes = &Store[objects.ClusterType, *objects.ClusterType]{
+		nbi: nbi, Type: "Cluster type", APIPath: service.ClusterTypesAPIPath,
+		Name: func(clusterType *objects.ClusterType) string { return clusterType.Name },
+		Indexes: []Index[objects.ClusterType]{
+			byName(&nbi.ClusterTypesIndexByName, func(clusterType *objects.ClusterType) string { return clusterType.Name }),
+		},
+	}
+	nbi.clusters = &Store[objects.Cluster, *objects.Cluster]{
+		nbi: nbi, Type: "Cluster", APIPath: service.ClustersAPIPath,
+		Name: func(cluster *objects.Cluster) string { return cluster.Name },
+		Indexes: []Index[objects.Cluster]{
+			byName(&nbi.ClustersIndexByName, func(cluster *objects.Cluster) string { return cluster.Name }),
+		},
+	}
+	// Vms are matched by source id, and by cluster and name.
+	nbi.vms = &Store[objects.VM, *objects.VM]{
+		nbi: nbi, Type: "VM", APIPath: service.VirtualMachinesAPIPath,
+		Diff:         nbi.vmDiff,
+		Name:         func(vm *objects.VM) string { return vm.Name },
+		ScopeFilters: []string{scopeSite, scopeTenant},
+		Lookup: func(vm *objects.VM) []string {
+			queries := []string{}
+			if vm.Name != "" {
+				queries = append(queries, queryParam("name", vm.Name))
+			}
+			if query, ok := sourceIDQuery(vm.NetboxObject); ok {
+				queries = append(queries, query)
+			}
+			return queries
+		},
+		Indexes: []Index[objects.VM]{
+			bySourceID[objects.VM](&nbi.VMsIndexBySourceAndSourceID, nil),
+			&NestedMapIndex[int, string, objects.VM]{
+				Map: &nbi.VMsIndexByClusterIDAndName,
+				Key: func(vm *objects.VM) (int, string, bool) { return clusterID(vm.Cluster), vm.Name, vm.Name != "" },
+			},
+		},
+	}
+	nbi.vmInterfaces = &Store[objects.VMInterface, *objects.VMInterface]{
+		nbi: nbi, Type: "VM interface", APIPath: service.VMInterfacesAPIPath,
+		Name: func(vmInterface *objects.VMInterface) string { return vmInterface.Name },
+		Lookup: func(vmInterface *objects.VMInterface) []string {
+			return []string{fmt.Sprintf("&virtual_machine_id=%d", vmInterface.VM.ID) + queryParam("name", vmInterface.Name)}
+		},
+		Indexes: []Index[objects.VMInterface]{
+			&NestedMapIndex[int, string, objects.VMInterface]{
+				Map: &nbi.VMInterfacesIndexByVMIdAndName,
+				Key: func(vmInterface *objects.VMInterface) (int, string, bool) {
+					return vmInterface.VM.ID, vmInterface.Name, true
+				},
+			},
+		},
+	}
+	nbi.virtualDisks = &Store[objects.VirtualDisk, *objects.VirtualDisk]{
+		nbi: nbi, Type: "Virtual disk", APIPath: service.VirtualDisksAPIPath,
+		Name: func(virtualDisk *objects.VirtualDisk) string { return virtualDisk.Name },
+		Lookup: func(virtualDisk *objects.VirtualDisk) []string {
+			return []string{fmt.Sprintf("&virtual_machine_id=%d", virtualDisk.VM.ID) + queryParam("name", virtualDisk.Name)}
+		},
+		Indexes: []Index[objects.VirtualDisk]{
+			&NestedMapIndex[int, string, objects.VirtualDisk]{
+				Map: &nbi.VirtualDisksIndexByVMIDAndName,
+				Key: func(virtualDisk *objects.VirtualDisk) (int, string, bool) {
+					return virtualDisk.VM.ID, virtualDisk.Name, true
+				},
+			},
+		},
+	}
+}
+
+// contactAssignmentIndex indexes contact assignments by their content type,
+// object id, contact id and role id.
+type contactAssignmentIndex struct {
+	nbi *NetboxInventory
+}
+
+func (idx *contactAssignmentIndex) Get(ca *objects.ContactAssignment) (*objects.ContactAssignment, bool) {
+	indexed, ok := idx.nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[ca.ContentType][ca.ObjectID][ca.Contact.ID][ca.Role.ID]
+	return indexed, ok
+}
+
+func (idx *contactAssignmentIndex) Put(ca *objects.ContactAssignment) {
+	index := idx.nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID
+	if index == nil {
+		index = make(map[string]map[int]map[int]map[int]*objects.ContactAssignment)
+		idx.nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID = index
+	}
+	if index[ca.ContentType] == nil {
+		index[ca.ContentType] = make(map[int]map[int]map[int]*objects.ContactAssignment)
+	}
+	if index[ca.ContentType][ca.ObjectID] == nil {
+		index[ca.ContentType][ca.ObjectID] = make(map[int]map[int]*objects.ContactAssignment)
+	}
+	if index[ca.ContentType][ca.ObjectID][ca.Contact.ID] == nil {
+		index[ca.ContentType][ca.ObjectID][ca.Contact.ID] = make(map[int]*objects.ContactAssignment)
+	}
+	index[ca.ContentType][ca.ObjectID][ca.Contact.ID][ca.Role.ID] = ca
+}
+
+func (idx *contactAssignmentIndex) Remove(ca *objects.ContactAssignment) {
+	roles := idx.nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID[ca.ContentType][ca.ObjectID][ca.Contact.ID]
+	if roles[ca.Role.ID] == ca {
+		delete(roles, ca.Role.ID)
+	}
+}
+
+func (idx *contactAssignmentIndex) Reset() {
+	idx.nbi.ContactAssignmentsIndexByContentTypeAndObjectIDAndContactIDAndRoleID = make(map[string]map[int]map[int]map[int]*objects.ContactAssignment)
+}
+
+// cableIndex indexes cables by ids of interfaces on both of their ends.
+// A cable matches an indexed cable, if any of its interfaces is already connected.
+type cableIndex struct {
+	nbi *NetboxInventory
+}
+
+// interfaceIDs returns ids of all interfaces, that are terminated by the cable.
+func interfaceIDs(cable *objects.Cable) []int {
+	ids := []int{}
+	for _, termination := range append(append([]*objects.CableTermination{}, cable.ATerminations...), cable.BTerminations...) {
+		if termination.ObjectType == objects.CableTerminationTypeInterface {
+			ids = append(ids, termination.ObjectID)
+		}
+	}
+	return ids
+}
+
+func (idx *cableIndex) Get(cable *objects.Cable) (*objects.Cable, bool) {
+	for _, id := range interfaceIDs(cable) {
+		if indexed, ok := idx.nbi.CablesIndexByInterfaceID[id]; ok {
+			return indexed, true
+		}
+	}
+	return nil, false
+}
+
+func (idx *cableIndex) Put(cable *objects.Cable) {
+	if idx.nbi.CablesIndexByInterfaceID == nil {
+		idx.nbi.CablesIndexByInterfaceID = make(map[int]*objects.Cable)
+	}
+	for _, id := range interfaceIDs(cable) {
+		idx.nbi.CablesIndexByInterfaceID[id] = cable
+	}
+}
+
+func (idx *cableIndex) Remove(cable *objects.Cable) {
+	for _, id := range interfaceIDs(cable) {
+		if idx.nbi.CablesIndexByInterfaceID[id] == cable {
+			delete(idx.nbi.CablesIndexByInterfaceID, id)
+		}
+	}
+}
+
+func (idx *cableIndex) Reset() {
+	idx.nbi.CablesIndexByInterfaceID = make(map[int]*objects.Cable)
+}
+
+// cableName returns ids of the interfaces, that are connected by the cable.
+func cableName(cable *objects.Cable) string {
+	ids := interfaceIDs(cable)
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = fmt.Sprintf("interface %d", id)
+	}
+	return strings.Join(names, " <-> ")
+}
@@ -29,13 +29,19 @@ type NetboxObject struct {
 	// Description represents custom description of the object.
 	Description string `json:"description,omitempty"`
 	// Array of custom fields, in format customFieldLabel: customFieldValue
-	CustomFields map[string]string `json:"custom_fields,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
 func (n NetboxObject) String() string {
 	return fmt.Sprintf("Id: %d, Tags: %s, Description: %s", n.ID, n.Tags, n.Description)
 }
 
+// GetNetboxObject returns the NetboxObject itself. Because it is promoted to all structs
+// that embed NetboxObject, their common attributes can be accessed in generic code.
+func (n *NetboxObject) GetNetboxObject() *NetboxObject {
+	return n
+}
+
 type Color string
 
 const (
@@ -30,6 +30,8 @@ type Site struct {
 	Status *SiteStatus `json:"status,omitempty"`
 	// Tenant of the site
 	Tenant *Tenant `json:"tenant,omitempty"`
+	// Region of the site
+	Region *Region `json:"region,omitempty"`
 
 	// Physical location of the building
 	PhysicalAddress string `json:"physical_address,omitempty"`
@@ -59,25 +61,36 @@ func (p Platform) String() string {
 	return fmt.Sprintf("Platform{Name: %s, Manufacturer: %s}", p.Name, p.Manufacturer)
 }
 
+// Region represents a geographic area (e.g. country, city...), regions can be nested.
 type Region struct {
 	NetboxObject
 	// Name is the name of the region. This field is required.
 	Name string `json:"name,omitempty"`
 	// Slug is a URL-friendly unique shorthand. This field is required.
 	Slug string `json:"slug,omitempty"`
+	// Parent is the parent region.
+	Parent *Region `json:"parent,omitempty"`
+}
+
+func (r Region) String() string {
+	return fmt.Sprintf("Region{Name: %s}", r.Name)
 }
 
 // Location represents a physical location, such as a floor or room in a building.
 type Location struct {
 	NetboxObject
 	// Site is the site to which the location belongs. This field is required.
-	Site *Site
+	Site *Site `json:"site,omitempty"`
 	// Name is the name of the location. This field is required.
-	Name string
+	Name string `json:"name,omitempty"`
 	// URL-friendly unique shorthand. This field is required.
-	Slug string
+	Slug string `json:"slug,omitempty"`
 	// Status is the status of the location. This field is required.
-	Status *SiteStatus
+	Status *SiteStatus `json:"status,omitempty"`
+}
+
+func (l Location) String() string {
+	return fmt.Sprintf("Location{Name: %s}", l.Name)
 }
 
 // Manufacturer represents a hardware manufacturer (e.g. Cisco, HP, ...).
@@ -228,12 +241,13 @@ type Device struct {
 	Tenant *Tenant `json:"tenant,omitempty"`
 
 	// Virtual Chassis
-
+	// VirtualChassis is the virtual chassis (e.g. switch stack) of which the device is a member.
+	VirtualChassis *VirtualChassis `json:"virtual_chassis,omitempty"`
 	// The position in the virtual chassis this device is identified by
-	// Position
-
+	VCPosition int `json:"vc_position,omitempty"`
 	// The priority of the device in the virtual chassis
-	// Priority
+	VCPriority int `json:"vc_priority,omitempty"`
+
 	// Additional comments.
 	Comments string `json:"comments,omitempty"`
 }
@@ -430,6 +444,8 @@ type Interface struct {
 	MTU int `json:"mtu,omitempty"`
 	// MAC is the mac address of the interface
 	MAC string `json:"mac_address,omitempty"`
+	// MgmtOnly is true if the interface is used only for out-of-band management (e.g. BMC).
+	MgmtOnly bool `json:"mgmt_only,omitempty"`
 
 	// Duplex is the duplex mode of the interface
 	Duplex *InterfaceDuplex `json:"duplex,omitempty"`
@@ -440,8 +456,123 @@ type Interface struct {
 	TaggedVlans []*Vlan `json:"tagged_vlans,omitempty"`
 	// UntaggedVlan
 	UntaggedVlan *Vlan `json:"untagged_vlan,omitempty"`
+	// WirelessLANs are wireless networks, that are served by the wireless interface.
+	WirelessLANs []*WirelessLAN `json:"wireless_lans,omitempty"`
 }
 
 func (i Interface) String() string {
 	return fmt.Sprintf("Interface{Name: %s, Device: %s, Type: %s}", i.Name, i.Device.Name, i.Type.Label)
 }
+
+// PowerPort represents a power input of a device (e.g. power supply unit).
+type PowerPort struct {
+	NetboxObject
+	// Device is the device to which the power port belongs. This field is required.
+	Device *Device `json:"device,omitempty"`
+	// Name is the name of the power port. This field is required.
+	Name string `json:"name,omitempty"`
+	// Label is physical label of the power port.
+	Label string `json:"label,omitempty"`
+	// MaximumDraw is maximum power draw in watts.
+	MaximumDraw int `json:"maximum_draw,omitempty"`
+	// AllocatedDraw is allocated power draw in watts.
+	AllocatedDraw int `json:"allocated_draw,omitempty"`
+}
+
+func (pp PowerPort) String() string {
+	return fmt.Sprintf("PowerPort{Name: %s, Device: %s}", pp.Name, pp.Device.Name)
+}
+
+type CableStatus struct {
+	Choice
+}
+
+var (
+	CableStatusConnected       = CableStatus{Choice{Value: "connected", Label: "Connected"}}
+	CableStatusPlanned         = CableStatus{Choice{Value: "planned", Label: "Planned"}}
+	CableStatusDecommissioning = CableStatus{Choice{Value: "decommissioning", Label: "Decommissioning"}}
+)
+
+type CableTerminationType string
+
+const (
+	CableTerminationTypeInterface CableTerminationType = "dcim.interface"
+	CableTerminationTypePowerPort CableTerminationType = "dcim.powerport"
+)
+
+// CableTermination represents one end of the cable.
+type CableTermination struct {
+	// ObjectType is the type of the terminated object (e.g. dcim.interface).
+	ObjectType CableTerminationType `json:"object_type,omitempty"`
+	// ObjectID is the id of the terminated object.
+	ObjectID int `json:"object_id,omitempty"`
+}
+
+// Cable represents a physical connection between two sets of terminations.
+type Cable struct {
+	NetboxObject
+	// ATerminations are objects on the A side of the cable. This field is required.
+	ATerminations []*CableTermination `json:"a_terminations,omitempty"`
+	// BTerminations are objects on the B side of the cable. This field is required.
+	BTerminations []*CableTermination `json:"b_terminations,omitempty"`
+	// Status of the cable.
+	Status *CableStatus `json:"status,omitempty"`
+	// Type of the cable (e.g. cat6, smf, mmf-om4...).
+	Type string `json:"type,omitempty"`
+	// Tenant that the cable belongs to.
+	Tenant *Tenant `json:"tenant,omitempty"`
+	// Label is physical label of the cable.
+	Label string `json:"label,omitempty"`
+	// Color of the cable.
+	Color Color `json:"color,omitempty"`
+	// Comments about the cable.
+	Comments string `json:"comments,omitempty"`
+}
+
+func (c Cable) String() string {
+	return fmt.Sprintf("Cable{ATerminations: %d, BTerminations: %d}", len(c.ATerminations), len(c.BTerminations))
+}
+
+// VirtualChassis represents a set of devices which share a common control plane (e.g. switch stack).
+type VirtualChassis struct {
+	NetboxObject
+	// Name of the virtual chassis. This field is required.
+	Name string `json:"name,omitempty"`
+	// Domain of the virtual chassis.
+	Domain string `json:"domain,omitempty"`
+	// Master is the member device, which controls the virtual chassis.
+	Master *Device `json:"master,omitempty"`
+	// Comments about the virtual chassis.
+	Comments string `json:"comments,omitempty"`
+}
+
+func (vc VirtualChassis) String() string {
+	return fmt.Sprintf("VirtualChassis{Name: %s}", vc.Name)
+}
+
+// InventoryItem represents a hardware component installed within a device (e.g. line card, transceiver...).
+type InventoryItem struct {
+	NetboxObject
+	// Device is the device to which the inventory item belongs. This field is required.
+	Device *Device `json:"device,omitempty"`
+	// Parent is the parent inventory item.
+	Parent *InventoryItem `json:"parent,omitempty"`
+	// Name of the inventory item. This field is required.
+	Name string `json:"name,omitempty"`
+	// Label is physical label of the inventory item.
+	Label string `json:"label,omitempty"`
+	// Manufacturer of the inventory item.
+	Manufacturer *Manufacturer `json:"manufacturer,omitempty"`
+	// PartID is manufacturer-assigned part identifier.
+	PartID string `json:"part_id,omitempty"`
+	// SerialNumber of the inventory item.
+	SerialNumber string `json:"serial,omitempty"`
+	// AssetTag is an unique tag for identifying the inventory item.
+	AssetTag string `json:"asset_tag,omitempty"`
+	// Discovered is true, if the item was automatically discovered.
+	Discovered bool `json:"discovered,omitempty"`
+}
+
+func (ii InventoryItem) String() string {
+	return fmt.Sprintf("InventoryItem{Name: %s, Device: %s}", ii.Name, ii.Device.Name)
+}
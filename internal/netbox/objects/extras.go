@@ -2,6 +2,7 @@ package objects
 
 import (
 	"fmt"
+	"time"
 )
 
 type Tag struct {
@@ -93,3 +94,31 @@ type CustomField struct {
 func (cf CustomField) String() string {
 	return fmt.Sprintf("CustomField{Id: %d, Name: %s, Label: %s, ...}", cf.ID, cf.Name, cf.Label)
 }
+
+// ObjectChangeAction is the action of an ObjectChange.
+type ObjectChangeAction struct {
+	Choice
+}
+
+var (
+	ObjectChangeActionCreate = ObjectChangeAction{Choice{Value: "create", Label: "Created"}}
+	ObjectChangeActionUpdate = ObjectChangeAction{Choice{Value: "update", Label: "Updated"}}
+	ObjectChangeActionDelete = ObjectChangeAction{Choice{Value: "delete", Label: "Deleted"}}
+)
+
+// ObjectChange is an entry of Netbox's changelog.
+type ObjectChange struct {
+	ID int `json:"id,omitempty"`
+	// Time of the change.
+	Time time.Time `json:"time,omitempty"`
+	// Action performed on the object (create, update or delete).
+	Action *ObjectChangeAction `json:"action,omitempty"`
+	// ChangedObjectType is the content type of the changed object (e.g. dcim.device).
+	ChangedObjectType string `json:"changed_object_type,omitempty"`
+	// ChangedObjectID is the id of the changed object.
+	ChangedObjectID int `json:"changed_object_id,omitempty"`
+}
+
+func (oc ObjectChange) String() string {
+	return fmt.Sprintf("ObjectChange{Action: %s, ChangedObjectType: %s, ChangedObjectID: %d}", oc.Action, oc.ChangedObjectType, oc.ChangedObjectID)
+}
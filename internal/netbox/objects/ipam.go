@@ -37,10 +37,29 @@ const (
 	AssignedObjectTypeDeviceInterface = "dcim.interface"
 )
 
+// VRF represents a netbox's virtual routing and forwarding table, which is a separate ip space.
+type VRF struct {
+	NetboxObject
+	// Name of the VRF. This field is required.
+	Name string `json:"name,omitempty"`
+	// Route distinguisher as defined in RFC 4364.
+	RD string `json:"rd,omitempty"`
+	// Tenant that this VRF belongs to.
+	Tenant *Tenant `json:"tenant,omitempty"`
+	// EnforceUnique prevents duplicate prefixes and ip addresses within this VRF.
+	EnforceUnique bool `json:"enforce_unique,omitempty"`
+}
+
+func (v VRF) String() string {
+	return fmt.Sprintf("VRF{Id: %d, Name: %s}", v.ID, v.Name)
+}
+
 type IPAddress struct {
 	NetboxObject
 	// IPv4 or IPv6 address (with mask). This field is required.
 	Address string `json:"address,omitempty"`
+	// VRF of the IP address. Nil means the global table.
+	VRF *VRF `json:"vrf,omitempty"`
 	// The status of this IP address.
 	Status *IPAddressStatus `json:"status,omitempty"`
 	// Role of the IP address.
@@ -140,6 +159,8 @@ type Prefix struct {
 	NetboxObject
 	// Prefix is a IPv4 or IPv6 network address (with mask). This field is required.
 	Prefix string `json:"prefix,omitempty"`
+	// VRF of the prefix. Nil means the global table.
+	VRF *VRF `json:"vrf,omitempty"`
 	// Status of the prefix (default "active").
 	Status *PrefixStatus `json:"status,omitempty"`
 
@@ -143,3 +143,18 @@ type VMInterface struct {
 func (vmi VMInterface) String() string {
 	return fmt.Sprintf("VMInterface{Name: %s, VM: %s}", vmi.Name, vmi.VM.Name)
 }
+
+// VirtualDisk represents a netbox's virtual disk of the virtual machine.
+type VirtualDisk struct {
+	NetboxObject
+	// VM that this virtual disk belongs to. This field is required.
+	VM *VM `json:"virtual_machine,omitempty"`
+	// Name is the name of the virtual disk. This field is required.
+	Name string `json:"name,omitempty"`
+	// Size is the size of the virtual disk in GB. This field is required.
+	Size int `json:"size"`
+}
+
+func (vd VirtualDisk) String() string {
+	return fmt.Sprintf("VirtualDisk{Name: %s, VM: %s}", vd.Name, vd.VM.Name)
+}
@@ -0,0 +1,46 @@
+package objects
+
+import "fmt"
+
+type WirelessLANStatus struct {
+	Choice
+}
+
+var (
+	WirelessLANStatusActive     = WirelessLANStatus{Choice{Value: "active", Label: "Active"}}
+	WirelessLANStatusReserved   = WirelessLANStatus{Choice{Value: "reserved", Label: "Reserved"}}
+	WirelessLANStatusDisabled   = WirelessLANStatus{Choice{Value: "disabled", Label: "Disabled"}}
+	WirelessLANStatusDeprecated = WirelessLANStatus{Choice{Value: "deprecated", Label: "Deprecated"}}
+)
+
+type WirelessLANAuthType struct {
+	Choice
+}
+
+var (
+	WirelessLANAuthTypeOpen          = WirelessLANAuthType{Choice{Value: "open", Label: "Open"}}
+	WirelessLANAuthTypeWEP           = WirelessLANAuthType{Choice{Value: "wep", Label: "WEP"}}
+	WirelessLANAuthTypeWPAPersonal   = WirelessLANAuthType{Choice{Value: "wpa-personal", Label: "WPA Personal (PSK)"}}
+	WirelessLANAuthTypeWPAEnterprise = WirelessLANAuthType{Choice{Value: "wpa-enterprise", Label: "WPA Enterprise"}}
+)
+
+// WirelessLAN represents a wireless network, identified by its SSID.
+type WirelessLAN struct {
+	NetboxObject
+	// SSID is the service set identifier of the wireless network. This field is required.
+	SSID string `json:"ssid,omitempty"`
+	// Status of the wireless network.
+	Status *WirelessLANStatus `json:"status,omitempty"`
+	// Vlan to which the wireless network is bridged.
+	Vlan *Vlan `json:"vlan,omitempty"`
+	// Tenant of the wireless network.
+	Tenant *Tenant `json:"tenant,omitempty"`
+	// AuthType is the authentication type of the wireless network.
+	AuthType *WirelessLANAuthType `json:"auth_type,omitempty"`
+	// Comments about the wireless network.
+	Comments string `json:"comments,omitempty"`
+}
+
+func (wl WirelessLAN) String() string {
+	return fmt.Sprintf("WirelessLAN{SSID: %s}", wl.SSID)
+}
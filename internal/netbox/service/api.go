@@ -19,6 +19,10 @@ type NetboxAPI struct {
 	APIKey     string
 	Timeout    int // in seconds
 	MaxRetires int
+	// PageSize is the number of objects requested per page (Netbox caps it at its MAX_PAGE_SIZE)
+	PageSize int
+	// DisableFieldSelection requests all fields of objects, instead of only fields of their structs
+	DisableFieldSelection bool
 }
 
 const (
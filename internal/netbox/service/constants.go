@@ -13,21 +13,74 @@ const (
 	VlanGroupsAPIPath  = "/api/ipam/vlan-groups/"
 	VlansAPIPath       = "/api/ipam/vlans/"
 	IPAddressesAPIPath = "/api/ipam/ip-addresses/"
+	VRFsAPIPath        = "/api/ipam/vrfs/"
 
 	ClusterTypesAPIPath    = "/api/virtualization/cluster-types/"
 	ClusterGroupsAPIPath   = "/api/virtualization/cluster-groups/"
 	ClustersAPIPath        = "/api/virtualization/clusters/"
 	VirtualMachinesAPIPath = "/api/virtualization/virtual-machines/"
 	VMInterfacesAPIPath    = "/api/virtualization/interfaces/"
+	VirtualDisksAPIPath    = "/api/virtualization/virtual-disks/"
 
-	DevicesAPIPath       = "/api/dcim/devices/"
-	DeviceRolesAPIPath   = "/api/dcim/device-roles/"
-	DeviceTypesAPIPath   = "/api/dcim/device-types/"
-	InterfacesAPIPath    = "/api/dcim/interfaces/"
-	SitesAPIPath         = "/api/dcim/sites/"
-	ManufacturersAPIPath = "/api/dcim/manufacturers/"
-	PlatformsAPIPath     = "/api/dcim/platforms/"
+	DevicesAPIPath        = "/api/dcim/devices/"
+	DeviceRolesAPIPath    = "/api/dcim/device-roles/"
+	DeviceTypesAPIPath    = "/api/dcim/device-types/"
+	InterfacesAPIPath     = "/api/dcim/interfaces/"
+	PowerPortsAPIPath     = "/api/dcim/power-ports/"
+	SitesAPIPath          = "/api/dcim/sites/"
+	RegionsAPIPath        = "/api/dcim/regions/"
+	LocationsAPIPath      = "/api/dcim/locations/"
+	ManufacturersAPIPath  = "/api/dcim/manufacturers/"
+	PlatformsAPIPath      = "/api/dcim/platforms/"
+	CablesAPIPath         = "/api/dcim/cables/"
+	VirtualChassisAPIPath = "/api/dcim/virtual-chassis/"
+	InventoryItemsAPIPath = "/api/dcim/inventory-items/"
+
+	WirelessLANsAPIPath = "/api/wireless/wireless-lans/"
 
 	CustomFieldsAPIPath = "/api/extras/custom-fields/"
 	TagsAPIPath         = "/api/extras/tags/"
+
+	ObjectChangesAPIPath = "/api/core/object-changes/"
+	// LegacyObjectChangesAPIPath is the api path of object changes before Netbox 4.1
+	LegacyObjectChangesAPIPath = "/api/extras/object-changes/"
 )
+
+// ContentTypes maps api paths of objects to their content types,
+// which are used to reference objects in Netbox's changelog.
+var ContentTypes = map[string]string{
+	ContactGroupsAPIPath:      "tenancy.contactgroup",
+	ContactRolesAPIPath:       "tenancy.contactrole",
+	ContactsAPIPath:           "tenancy.contact",
+	TenantsAPIPath:            "tenancy.tenant",
+	ContactAssignmentsAPIPath: "tenancy.contactassignment",
+
+	PrefixesAPIPath:    "ipam.prefix",
+	VlanGroupsAPIPath:  "ipam.vlangroup",
+	VlansAPIPath:       "ipam.vlan",
+	IPAddressesAPIPath: "ipam.ipaddress",
+	VRFsAPIPath:        "ipam.vrf",
+
+	ClusterTypesAPIPath:    "virtualization.clustertype",
+	ClusterGroupsAPIPath:   "virtualization.clustergroup",
+	ClustersAPIPath:        "virtualization.cluster",
+	VirtualMachinesAPIPath: "virtualization.virtualmachine",
+	VMInterfacesAPIPath:    "virtualization.vminterface",
+	VirtualDisksAPIPath:    "virtualization.virtualdisk",
+
+	DevicesAPIPath:        "dcim.device",
+	DeviceRolesAPIPath:    "dcim.devicerole",
+	DeviceTypesAPIPath:    "dcim.devicetype",
+	InterfacesAPIPath:     "dcim.interface",
+	PowerPortsAPIPath:     "dcim.powerport",
+	SitesAPIPath:          "dcim.site",
+	RegionsAPIPath:        "dcim.region",
+	LocationsAPIPath:      "dcim.location",
+	ManufacturersAPIPath:  "dcim.manufacturer",
+	PlatformsAPIPath:      "dcim.platform",
+	CablesAPIPath:         "dcim.cable",
+	VirtualChassisAPIPath: "dcim.virtualchassis",
+	InventoryItemsAPIPath: "dcim.inventoryitem",
+
+	WirelessLANsAPIPath: "wireless.wirelesslan",
+}
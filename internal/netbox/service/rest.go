@@ -5,12 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
 
+	"github.com/bl4ko/netbox-ssot/internal/constants"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
 	"github.com/bl4ko/netbox-ssot/internal/utils"
 )
 
+// BriefParam requests brief representation of objects (e.g. only id, name and slug),
+// which can be used, when objects are only referenced by other objects.
+const BriefParam = "&brief=true"
+
 // Standard response format from Netbox's API.
 type Response[T any] struct {
 	Count    int     `json:"count"`
@@ -23,16 +30,24 @@ var type2path = map[reflect.Type]string{
 	reflect.TypeOf((*objects.VlanGroup)(nil)).Elem():         VlanGroupsAPIPath,
 	reflect.TypeOf((*objects.Vlan)(nil)).Elem():              VlansAPIPath,
 	reflect.TypeOf((*objects.IPAddress)(nil)).Elem():         IPAddressesAPIPath,
+	reflect.TypeOf((*objects.VRF)(nil)).Elem():               VRFsAPIPath,
 	reflect.TypeOf((*objects.ClusterType)(nil)).Elem():       ClusterTypesAPIPath,
 	reflect.TypeOf((*objects.ClusterGroup)(nil)).Elem():      ClusterGroupsAPIPath,
 	reflect.TypeOf((*objects.Cluster)(nil)).Elem():           ClustersAPIPath,
 	reflect.TypeOf((*objects.VM)(nil)).Elem():                VirtualMachinesAPIPath,
 	reflect.TypeOf((*objects.VMInterface)(nil)).Elem():       VMInterfacesAPIPath,
+	reflect.TypeOf((*objects.VirtualDisk)(nil)).Elem():       VirtualDisksAPIPath,
 	reflect.TypeOf((*objects.Device)(nil)).Elem():            DevicesAPIPath,
 	reflect.TypeOf((*objects.DeviceRole)(nil)).Elem():        DeviceRolesAPIPath,
 	reflect.TypeOf((*objects.DeviceType)(nil)).Elem():        DeviceTypesAPIPath,
 	reflect.TypeOf((*objects.Interface)(nil)).Elem():         InterfacesAPIPath,
+	reflect.TypeOf((*objects.PowerPort)(nil)).Elem():         PowerPortsAPIPath,
+	reflect.TypeOf((*objects.Cable)(nil)).Elem():             CablesAPIPath,
+	reflect.TypeOf((*objects.VirtualChassis)(nil)).Elem():    VirtualChassisAPIPath,
+	reflect.TypeOf((*objects.InventoryItem)(nil)).Elem():     InventoryItemsAPIPath,
 	reflect.TypeOf((*objects.Site)(nil)).Elem():              SitesAPIPath,
+	reflect.TypeOf((*objects.Region)(nil)).Elem():            RegionsAPIPath,
+	reflect.TypeOf((*objects.Location)(nil)).Elem():          LocationsAPIPath,
 	reflect.TypeOf((*objects.Manufacturer)(nil)).Elem():      ManufacturersAPIPath,
 	reflect.TypeOf((*objects.Platform)(nil)).Elem():          PlatformsAPIPath,
 	reflect.TypeOf((*objects.Tenant)(nil)).Elem():            TenantsAPIPath,
@@ -43,24 +58,40 @@ var type2path = map[reflect.Type]string{
 	reflect.TypeOf((*objects.Tag)(nil)).Elem():               TagsAPIPath,
 	reflect.TypeOf((*objects.ContactAssignment)(nil)).Elem(): ContactAssignmentsAPIPath,
 	reflect.TypeOf((*objects.Prefix)(nil)).Elem():            PrefixesAPIPath,
+	reflect.TypeOf((*objects.WirelessLAN)(nil)).Elem():       WirelessLANsAPIPath,
+	reflect.TypeOf((*objects.ObjectChange)(nil)).Elem():      ObjectChangesAPIPath,
 }
 
 // GetAll queries all objects of type T from Netbox's API.
-// It is querying objects via pagination of limit=100.
+// It is querying objects via pagination of limit=api.PageSize, following
+// the next url of each page. Unless field selection is disabled, only fields of T
+// are requested.
 //
 // extraParams in a string format of: &extraParam1=...&extraParam2=...
 func GetAll[T any](api *NetboxAPI, extraParams string) ([]T, error) {
-	var allResults []T
 	var dummy T // Dummy variable for extracting type of generic
-	path := type2path[reflect.TypeOf(dummy)]
-	limit := 100
-	offset := 0
+	return GetAllFromPath[T](api, type2path[reflect.TypeOf(dummy)], extraParams)
+}
+
+// GetAllFromPath queries all objects of type T from the given api path, for objects,
+// whose api path depends on the version of Netbox.
+func GetAllFromPath[T any](api *NetboxAPI, path string, extraParams string) ([]T, error) {
+	var allResults []T
+	var dummy T // Dummy variable for printf
+	limit := api.PageSize
+	if limit <= 0 {
+		limit = constants.DefaultPageSize
+	}
+	queryPath := fmt.Sprintf("%s?limit=%d%s", path, limit, extraParams)
+	// Brief representation of objects can't be combined with field selection
+	if !api.DisableFieldSelection && !strings.Contains(extraParams, BriefParam) {
+		queryPath += "&fields=" + strings.Join(Fields[T](), ",")
+	}
 
 	api.Logger.Debugf("Getting all %T from Netbox", dummy)
 
 	for {
-		api.Logger.Debugf("Getting %T with limit=%d and offset=%d", dummy, limit, offset)
-		queryPath := fmt.Sprintf("%s?limit=%d&offset=%d%s", path, limit, offset, extraParams)
+		api.Logger.Debugf("Getting %T with %s", dummy, queryPath)
 		response, err := api.doRequest(MethodGet, queryPath, nil)
 		if err != nil {
 			return nil, err
@@ -81,7 +112,13 @@ func GetAll[T any](api *NetboxAPI, extraParams string) ([]T, error) {
 		if responseObj.Next == nil {
 			break
 		}
-		offset += limit
+		// Only path and query of the next url are used, because netbox behind
+		// a reverse proxy can return next url with its internal hostname
+		nextURL, err := url.Parse(*responseObj.Next)
+		if err != nil {
+			return nil, fmt.Errorf("next url: %s", err)
+		}
+		queryPath = nextURL.RequestURI()
 	}
 
 	api.Logger.Debugf("Successfully received all %T: %v", dummy, allResults)
@@ -89,6 +126,28 @@ func GetAll[T any](api *NetboxAPI, extraParams string) ([]T, error) {
 	return allResults, nil
 }
 
+// Fields returns names of all json fields of T (including fields of embedded structs),
+// which are requested from Netbox's API, when field selection is enabled.
+func Fields[T any]() []string {
+	var dummy T
+	return jsonFields(reflect.TypeOf(dummy))
+}
+
+func jsonFields(t reflect.Type) []string {
+	fields := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct:
+			fields = append(fields, jsonFields(field.Type)...)
+		case name != "" && name != "-":
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
 // Patch func patches the object of type T, with the given api path and body.
 // Path of the object (must contain the id), for example /api/dcim/devices/1/.
 func Patch[T any](api *NetboxAPI, objectID int, body map[string]interface{}) (*T, error) {
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+)
+
+func TestGetAll(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RequestURI())
+		response := Response[objects.Site]{Results: []objects.Site{{NetboxObject: objects.NetboxObject{ID: 1}, Name: "site1"}}}
+		if r.URL.Query().Get("cursor") == "" {
+			// Next url of netbox behind a reverse proxy points to its internal hostname
+			next := "http://netbox.internal:8080/api/dcim/sites/?cursor=2&limit=2"
+			response.Next = &next
+		} else {
+			response.Results[0] = objects.Site{NetboxObject: objects.NetboxObject{ID: 2}, Name: "site2"}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := NewNetBoxAPI(testLogger, server.URL, "token", false, 5)
+	api.PageSize = 2
+
+	sites, err := GetAll[objects.Site](api, "&tag=netbox-ssot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sites) != 2 || sites[0].Name != "site1" || sites[1].Name != "site2" {
+		t.Errorf("GetAll() = %v, want [site1 site2]", sites)
+	}
+	expectedQueries := []string{
+		"/api/dcim/sites/?limit=2&tag=netbox-ssot&fields=" + strings.Join(Fields[objects.Site](), ","),
+		"/api/dcim/sites/?cursor=2&limit=2",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Errorf("queries = %v, want %v", queries, expectedQueries)
+	}
+
+	queries = nil
+	if _, err := GetAll[objects.Site](api, BriefParam); err != nil {
+		t.Fatal(err)
+	}
+	if queries[0] != "/api/dcim/sites/?limit=2&brief=true" {
+		t.Errorf("brief query = %s, want it without field selection", queries[0])
+	}
+}
+
+func TestFields(t *testing.T) {
+	expected := []string{"id", "tags", "description", "custom_fields", "name", "slug", "parent"}
+	if got := Fields[objects.Region](); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Fields() = %v, want %v", got, expected)
+	}
+}
@@ -3,9 +3,12 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"strings"
 
 	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
 	"github.com/bl4ko/netbox-ssot/internal/utils"
 	"gopkg.in/yaml.v3"
 )
@@ -13,6 +16,7 @@ import (
 type Config struct {
 	Logger  *LoggerConfig  `yaml:"logger"`
 	Netbox  *NetboxConfig  `yaml:"netbox"`
+	DNS     *DNSConfig     `yaml:"dns"`
 	Sources []SourceConfig `yaml:"source"`
 }
 
@@ -28,6 +32,20 @@ func (l LoggerConfig) String() string {
 	return fmt.Sprintf("LoggerConfig{Level: %d, Dest: %s}", l.Level, l.Dest)
 }
 
+// DNSConfig configures resolver, that is used for dns names of ip addresses.
+type DNSConfig struct {
+	// Servers are dns servers in format host[:port]. If empty, system resolver is used
+	Servers []string `yaml:"servers"`
+	// Timeout of a single dns query in seconds
+	Timeout int `yaml:"timeout"`
+	// Concurrency is the maximum number of concurrent dns queries
+	Concurrency int `yaml:"concurrency"`
+}
+
+func (d DNSConfig) String() string {
+	return fmt.Sprintf("DNSConfig{Servers: %v, Timeout: %d, Concurrency: %d}", d.Servers, d.Timeout, d.Concurrency)
+}
+
 type HTTPScheme string
 
 const (
@@ -35,22 +53,58 @@ const (
 	HTTPS HTTPScheme = "https"
 )
 
+// Options for mapping vsphere tag categories in tagCategoryMappings.
+const (
+	TagCategoryTenant      = "tenant"
+	TagCategoryCustomField = "customField"
+)
+
+// Policies for choosing dns name of ip addresses in dnsNamePolicy.
+const (
+	// DNSNamePolicyPTR uses PTR record of the ip address.
+	DNSNamePolicyPTR = "ptr"
+	// DNSNamePolicyGuest uses hostname reported by the guest, falling back to PTR record.
+	DNSNamePolicyGuest = "guest"
+	// DNSNamePolicyVerified uses hostname reported by the guest only if it resolves
+	// to the ip address, falling back to PTR record.
+	DNSNamePolicyVerified = "verified"
+)
+
+// Default directory, where state of incremental sync is persisted between runs.
+const DefaultSyncStateDir = ".netbox-ssot"
+
 type NetboxConfig struct {
 	APIToken string `yaml:"apiToken"`
 	Hostname string `yaml:"hostname"`
 	Port     int    `yaml:"port"`
 	// Can be http or https (default)
-	HTTPScheme     HTTPScheme `yaml:"httpScheme"`
-	ValidateCert   bool       `yaml:"validateCert"`
-	Timeout        int        `yaml:"timeout"`
-	Tag            string     `yaml:"tag"`
-	TagColor       string     `yaml:"tagColor"`
-	RemoveOrphans  bool       `yaml:"removeOrphans"`
-	SourcePriority []string   `yaml:"sourcePriority"`
+	HTTPScheme    HTTPScheme `yaml:"httpScheme"`
+	ValidateCert  bool       `yaml:"validateCert"`
+	Timeout       int        `yaml:"timeout"`
+	Tag           string     `yaml:"tag"`
+	TagColor      string     `yaml:"tagColor"`
+	RemoveOrphans bool       `yaml:"removeOrphans"`
+	// Sites are removed only when explicitly enabled, because they are matched by name
+	RemoveOrphanSites bool     `yaml:"removeOrphanSites"`
+	SourcePriority    []string `yaml:"sourcePriority"`
+	// Number of objects per page of Netbox API responses
+	PageSize int `yaml:"pageSize"`
+	// Request all fields of objects, instead of only fields used by netbox-ssot
+	DisableFieldSelection bool `yaml:"disableFieldSelection"`
+
+	// Snapshot of the inventory, that is used to fetch only objects changed since the previous run
+	Snapshot       bool   `yaml:"snapshot"`
+	SnapshotDir    string `yaml:"snapshotDir"`
+	SnapshotMaxAge int    `yaml:"snapshotMaxAge"` // in hours
+
+	// Scoped init collects only objects tagged with the ssot tag, or within scope sites and tenants
+	ScopedInit   bool     `yaml:"scopedInit"`
+	ScopeSites   []string `yaml:"scopeSites"`
+	ScopeTenants []string `yaml:"scopeTenants"`
 }
 
 func (n NetboxConfig) String() string {
-	return fmt.Sprintf("NetboxConfig{ApiToken: %s, Hostname: %s, Port: %d, HTTPScheme: %s, ValidateCert: %t, Timeout: %d, Tag: %s, TagColor: %s, RemoveOrphans: %t}", n.APIToken, n.Hostname, n.Port, n.HTTPScheme, n.ValidateCert, n.Timeout, n.Tag, n.TagColor, n.RemoveOrphans)
+	return fmt.Sprintf("NetboxConfig{ApiToken: %s, Hostname: %s, Port: %d, HTTPScheme: %s, ValidateCert: %t, Timeout: %d, Tag: %s, TagColor: %s, RemoveOrphans: %t, Snapshot: %t, ScopedInit: %t}", n.APIToken, n.Hostname, n.Port, n.HTTPScheme, n.ValidateCert, n.Timeout, n.Tag, n.TagColor, n.RemoveOrphans, n.Snapshot, n.ScopedInit)
 }
 
 type SourceConfig struct {
@@ -62,10 +116,15 @@ type SourceConfig struct {
 	Username         string               `yaml:"username"`
 	Password         string               `yaml:"password"`
 	PermittedSubnets []string             `yaml:"permittedSubnets"`
+	IgnoredSubnets   []string             `yaml:"ignoredSubnets"`
 	ValidateCert     bool                 `yaml:"validateCert"`
 	Tag              string               `yaml:"tag"`
 	TagColor         string               `yaml:"tagColor"`
 
+	// Dns names of ip addresses
+	DisableReverseLookup bool   `yaml:"disableReverseLookup"`
+	DNSNamePolicy        string `yaml:"dnsNamePolicy"`
+
 	// Relations
 	HostSiteRelations      []string `yaml:"hostSiteRelations"`
 	ClusterSiteRelations   []string `yaml:"clusterSiteRelations"`
@@ -75,8 +134,40 @@ type SourceConfig struct {
 	VlanGroupRelations     []string `yaml:"vlanGroupRelations"`
 	VlanTenantRelations    []string `yaml:"vlanTenantRelations"`
 
+	// VRF relations. VRF is the default vrf of all ip addresses and prefixes of the source,
+	// and is overridden by clusterVrfRelations and subnetVrfRelations
+	VRF                 string   `yaml:"vrf"`
+	ClusterVRFRelations []string `yaml:"clusterVrfRelations"`
+	SubnetVRFRelations  []string `yaml:"subnetVrfRelations"`
+
+	// Prefixes derived from ip addresses of interfaces
+	AutoPrefixes              bool `yaml:"autoPrefixes"`
+	AutoPrefixesPermittedOnly bool `yaml:"autoPrefixesPermittedOnly"`
+
 	// Vmware specific relations
 	CustomFieldMappings []string `yaml:"customFieldMappings"`
+	SyncTags            bool     `yaml:"syncTags"`
+	TagCategoryPrefix   bool     `yaml:"tagCategoryPrefix"`
+	VsphereTagColor     string   `yaml:"vsphereTagColor"`
+	TagCategoryMappings []string `yaml:"tagCategoryMappings"`
+	FolderCustomFields  bool     `yaml:"folderCustomFields"`
+	IncrementalSync     bool     `yaml:"incrementalSync"`
+	SyncStateDir        string   `yaml:"syncStateDir"`
+
+	// Vmware specific relations, matched against vm's folder path and resource pool
+	FolderSiteRelations         []string `yaml:"folderSiteRelations"`
+	FolderTenantRelations       []string `yaml:"folderTenantRelations"`
+	ResourcePoolSiteRelations   []string `yaml:"resourcePoolSiteRelations"`
+	ResourcePoolTenantRelations []string `yaml:"resourcePoolTenantRelations"`
+
+	// Redfish specific
+	BMCHostnames        []string `yaml:"bmcHostnames"`
+	BMCDiscoverySubnets []string `yaml:"bmcDiscoverySubnets"`
+
+	// Netbox specific
+	FilterTags    []string `yaml:"filterTags"`
+	FilterSites   []string `yaml:"filterSites"`
+	FilterTenants []string `yaml:"filterTenants"`
 }
 
 func (s SourceConfig) String() string {
@@ -95,6 +186,11 @@ func validateConfig(config *Config) error {
 		return err
 	}
 
+	err = validateDNSConfig(config)
+	if err != nil {
+		return err
+	}
+
 	err = validateSourceConfig(config)
 	if err != nil {
 		return err
@@ -110,6 +206,27 @@ func validateLoggerConfig(config *Config) error {
 	return nil
 }
 
+// Function that validates DNSConfig. Servers without port get the default dns port.
+func validateDNSConfig(config *Config) error {
+	if config.DNS.Timeout <= 0 {
+		return errors.New("dns.timeout: must be positive")
+	}
+	if config.DNS.Concurrency <= 0 {
+		return errors.New("dns.concurrency: must be positive")
+	}
+	for i, server := range config.DNS.Servers {
+		host, port, err := net.SplitHostPort(server)
+		if err != nil {
+			host, port = server, constants.DNSDefaultPort
+		}
+		if net.ParseIP(host) == nil {
+			return fmt.Errorf("dns.servers: %s is not a valid ip address", server)
+		}
+		config.DNS.Servers[i] = net.JoinHostPort(host, port)
+	}
+	return nil
+}
+
 // Function that validates NetboxConfig.
 func validateNetboxConfig(config *Config) error {
 	// Validate Netbox config
@@ -128,6 +245,32 @@ func validateNetboxConfig(config *Config) error {
 	if config.Netbox.Timeout < 0 {
 		return errors.New("netbox.timeout: cannot be negative")
 	}
+	if config.Netbox.PageSize < 0 {
+		return errors.New("netbox.pageSize: cannot be negative")
+	}
+	if config.Netbox.PageSize > constants.MaxPageSize {
+		return fmt.Errorf("netbox.pageSize: must be at most %d. Is %d", constants.MaxPageSize, config.Netbox.PageSize)
+	}
+	if config.Netbox.PageSize == 0 {
+		config.Netbox.PageSize = constants.DefaultPageSize
+	}
+	if config.Netbox.SnapshotMaxAge < 0 {
+		return errors.New("netbox.snapshotMaxAge: cannot be negative")
+	}
+	if config.Netbox.Snapshot {
+		if config.Netbox.SnapshotDir == "" {
+			config.Netbox.SnapshotDir = DefaultSyncStateDir
+		}
+		if config.Netbox.SnapshotMaxAge == 0 {
+			config.Netbox.SnapshotMaxAge = constants.DefaultSnapshotMaxAge
+		}
+	}
+	if !config.Netbox.ScopedInit && len(config.Netbox.ScopeSites) > 0 {
+		return errors.New("netbox.scopeSites: requires netbox.scopedInit")
+	}
+	if !config.Netbox.ScopedInit && len(config.Netbox.ScopeTenants) > 0 {
+		return errors.New("netbox.scopeTenants: requires netbox.scopedInit")
+	}
 	if config.Netbox.Tag == "" {
 		config.Netbox.Tag = "netbox-ssot"
 	}
@@ -181,11 +324,15 @@ func validateSourceConfig(config *Config) error {
 			return fmt.Errorf("%s: hostname cannot be empty", externalSourceStr)
 		}
 		if externalSource.Port == 0 {
-			externalSource.Port = 443
+			externalSource.Port = constants.HTTPSDefaultPort
+			if externalSource.Type == constants.SNMP {
+				externalSource.Port = constants.SNMPDefaultPort
+			}
 		} else if externalSource.Port < 0 || externalSource.Port > 65535 {
 			return fmt.Errorf("%s: port must be between 0 and 65535. Is %d", externalSourceStr, externalSource.Port)
 		}
-		if externalSource.Username == "" {
+		// Snmp and netbox sources use only community string or api token, which is stored in password
+		if externalSource.Username == "" && externalSource.Type != constants.SNMP && externalSource.Type != constants.Netbox {
 			return fmt.Errorf("%s: username cannot be empty", externalSourceStr)
 		}
 		if externalSource.Password == "" {
@@ -197,10 +344,65 @@ func validateSourceConfig(config *Config) error {
 		if externalSource.TagColor == "" {
 			externalSource.TagColor = constants.DefaultSourceToTagColorMap[externalSource.Type]
 		}
+		// Snmp permittedSubnets can also contain single ip addresses, so they are validated separately
+		if externalSource.Type != constants.SNMP {
+			for _, subnet := range externalSource.PermittedSubnets {
+				if _, _, err := net.ParseCIDR(subnet); err != nil {
+					return fmt.Errorf("%s.permittedSubnets: %s", externalSourceStr, err)
+				}
+			}
+		}
+		for _, subnet := range externalSource.IgnoredSubnets {
+			if _, _, err := net.ParseCIDR(subnet); err != nil {
+				return fmt.Errorf("%s.ignoredSubnets: %s", externalSourceStr, err)
+			}
+		}
+		switch externalSource.DNSNamePolicy {
+		case "":
+			externalSource.DNSNamePolicy = DNSNamePolicyPTR
+		case DNSNamePolicyPTR, DNSNamePolicyGuest, DNSNamePolicyVerified:
+		default:
+			return fmt.Errorf("%s.dnsNamePolicy: must be one of [%s, %s, %s]", externalSourceStr, DNSNamePolicyPTR, DNSNamePolicyGuest, DNSNamePolicyVerified)
+		}
 		switch externalSource.Type {
 		case constants.Ovirt:
 		case constants.Vmware:
+			if externalSource.IncrementalSync && externalSource.SyncStateDir == "" {
+				externalSource.SyncStateDir = DefaultSyncStateDir
+			}
+			if externalSource.VsphereTagColor == "" {
+				externalSource.VsphereTagColor = objects.ColorGrey
+			} else if !isHexColor(externalSource.VsphereTagColor) {
+				return fmt.Errorf("%s.vsphereTagColor: must be a string of 6 lowercase hexadecimal characters", externalSourceStr)
+			}
+			for _, mapping := range externalSource.TagCategoryMappings {
+				pair := strings.Split(mapping, "=")
+				if len(pair) != 2 {
+					return fmt.Errorf("%s.tagCategoryMappings: %s is not in format categoryName = option", externalSourceStr, mapping)
+				}
+				if option := strings.TrimSpace(pair[1]); option != TagCategoryTenant && option != TagCategoryCustomField {
+					return fmt.Errorf("%s.tagCategoryMappings: option %s is not one of [%s, %s]", externalSourceStr, option, TagCategoryTenant, TagCategoryCustomField)
+				}
+			}
 		case constants.Dnac:
+		case constants.Nutanix:
+		case constants.Xen:
+		case constants.Redfish:
+			for _, subnet := range externalSource.BMCDiscoverySubnets {
+				if _, _, err := net.ParseCIDR(subnet); err != nil {
+					return fmt.Errorf("%s.bmcDiscoverySubnets: %s", externalSourceStr, err)
+				}
+			}
+		case constants.SNMP:
+			for _, target := range externalSource.PermittedSubnets {
+				if _, _, err := net.ParseCIDR(target); err != nil && net.ParseIP(target) == nil {
+					return fmt.Errorf("%s.permittedSubnets: %s is neither ip address nor subnet", externalSourceStr, target)
+				}
+			}
+		case constants.Netbox:
+			if externalSource.Hostname == config.Netbox.Hostname && externalSource.Port == config.Netbox.Port {
+				return fmt.Errorf("%s: hostname and port must differ from the target netbox", externalSourceStr)
+			}
 		default:
 			return fmt.Errorf("%s.type is not valid", externalSourceStr)
 		}
@@ -212,6 +414,19 @@ func validateSourceConfig(config *Config) error {
 	return nil
 }
 
+// isHexColor returns true if color is a string of 6 lowercase hexadecimal characters.
+func isHexColor(color string) bool {
+	if len(color) != len("ffffff") {
+		return false
+	}
+	for _, c := range color {
+		if c < '0' || c > '9' && c < 'a' || c > 'f' {
+			return false
+		}
+	}
+	return true
+}
+
 func validateSourceConfigRelations(externalSource *SourceConfig, externalSourceStr string) error {
 	if len(externalSource.HostSiteRelations) > 0 {
 		err := utils.ValidateRegexRelations(externalSource.HostSiteRelations)
@@ -243,6 +458,45 @@ func validateSourceConfigRelations(externalSource *SourceConfig, externalSourceS
 			return fmt.Errorf("%s.vmTenantRelations: %s", externalSourceStr, err)
 		}
 	}
+	if len(externalSource.FolderSiteRelations) > 0 {
+		err := utils.ValidateRegexRelations(externalSource.FolderSiteRelations)
+		if err != nil {
+			return fmt.Errorf("%s.folderSiteRelations: %s", externalSourceStr, err)
+		}
+	}
+	if len(externalSource.FolderTenantRelations) > 0 {
+		err := utils.ValidateRegexRelations(externalSource.FolderTenantRelations)
+		if err != nil {
+			return fmt.Errorf("%s.folderTenantRelations: %s", externalSourceStr, err)
+		}
+	}
+	if len(externalSource.ResourcePoolSiteRelations) > 0 {
+		err := utils.ValidateRegexRelations(externalSource.ResourcePoolSiteRelations)
+		if err != nil {
+			return fmt.Errorf("%s.resourcePoolSiteRelations: %s", externalSourceStr, err)
+		}
+	}
+	if len(externalSource.ResourcePoolTenantRelations) > 0 {
+		err := utils.ValidateRegexRelations(externalSource.ResourcePoolTenantRelations)
+		if err != nil {
+			return fmt.Errorf("%s.resourcePoolTenantRelations: %s", externalSourceStr, err)
+		}
+	}
+	if len(externalSource.ClusterVRFRelations) > 0 {
+		err := utils.ValidateRegexRelations(externalSource.ClusterVRFRelations)
+		if err != nil {
+			return fmt.Errorf("%s.clusterVrfRelations: %s", externalSourceStr, err)
+		}
+	}
+	for _, relation := range externalSource.SubnetVRFRelations {
+		pair := strings.Split(relation, "=")
+		if len(pair) != 2 {
+			return fmt.Errorf("%s.subnetVrfRelations: %s is not in format subnet = vrf", externalSourceStr, relation)
+		}
+		if _, _, err := net.ParseCIDR(strings.TrimSpace(pair[0])); err != nil {
+			return fmt.Errorf("%s.subnetVrfRelations: %s", externalSourceStr, err)
+		}
+	}
 	if len(externalSource.VlanGroupRelations) > 0 {
 		err := utils.ValidateRegexRelations(externalSource.VlanGroupRelations)
 		if err != nil {
@@ -278,6 +532,10 @@ func ParseConfig(filename string) (*Config, error) {
 			Timeout:       constants.DefaultTimeout,
 			RemoveOrphans: true,
 		},
+		DNS: &DNSConfig{
+			Timeout:     constants.DefaultDNSTimeout,
+			Concurrency: constants.DefaultDNSConcurrency,
+		},
 		Sources: []SourceConfig{},
 	}
 
@@ -90,3 +90,120 @@ func TestInvalidConfig7(t *testing.T) {
 		return
 	}
 }
+
+func TestInvalidConfig8(t *testing.T) {
+	filename := filepath.Join("testdata", "invalid_config8.yaml")
+	expectedErr := "source[prodbmc].bmcDiscoverySubnets: invalid CIDR address: 10.0.0.0/33"
+	_, err := ParseConfig(filename)
+	if err == nil {
+		t.Errorf("%s", err)
+		return
+	} else if err.Error() != expectedErr {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		return
+	}
+}
+
+func TestInvalidConfig9(t *testing.T) {
+	filename := filepath.Join("testdata", "invalid_config9.yaml")
+	expectedErr := "source[switches].permittedSubnets: 10.0.1.300 is neither ip address nor subnet"
+	_, err := ParseConfig(filename)
+	if err == nil {
+		t.Errorf("%s", err)
+		return
+	} else if err.Error() != expectedErr {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		return
+	}
+}
+
+func TestInvalidConfig10(t *testing.T) {
+	filename := filepath.Join("testdata", "invalid_config10.yaml")
+	expectedErr := "source[regional]: hostname and port must differ from the target netbox"
+	_, err := ParseConfig(filename)
+	if err == nil {
+		t.Errorf("%s", err)
+		return
+	} else if err.Error() != expectedErr {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		return
+	}
+}
+
+func TestInvalidConfig11(t *testing.T) {
+	filename := filepath.Join("testdata", "invalid_config11.yaml")
+	expectedErr := "source[prodvmware].tagCategoryMappings: option site is not one of [tenant, customField]"
+	_, err := ParseConfig(filename)
+	if err == nil {
+		t.Errorf("%s", err)
+		return
+	} else if err.Error() != expectedErr {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		return
+	}
+}
+
+func TestInvalidConfig12(t *testing.T) {
+	filename := filepath.Join("testdata", "invalid_config12.yaml")
+	expectedErr := "source[prodovirt].ignoredSubnets: invalid CIDR address: 172.17.0.0"
+	_, err := ParseConfig(filename)
+	if err == nil {
+		t.Errorf("%s", err)
+		return
+	} else if err.Error() != expectedErr {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		return
+	}
+}
+
+func TestInvalidConfig13(t *testing.T) {
+	filename := filepath.Join("testdata", "invalid_config13.yaml")
+	expectedErr := "source[prodovirt].subnetVrfRelations: 10.2.0.0/16 is not in format subnet = vrf"
+	_, err := ParseConfig(filename)
+	if err == nil {
+		t.Errorf("%s", err)
+		return
+	} else if err.Error() != expectedErr {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		return
+	}
+}
+
+func TestInvalidConfig14(t *testing.T) {
+	filename := filepath.Join("testdata", "invalid_config14.yaml")
+	expectedErr := "netbox.snapshotMaxAge: cannot be negative"
+	_, err := ParseConfig(filename)
+	if err == nil {
+		t.Errorf("%s", err)
+		return
+	} else if err.Error() != expectedErr {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		return
+	}
+}
+
+func TestInvalidConfig15(t *testing.T) {
+	filename := filepath.Join("testdata", "invalid_config15.yaml")
+	expectedErr := "netbox.scopeSites: requires netbox.scopedInit"
+	_, err := ParseConfig(filename)
+	if err == nil {
+		t.Errorf("%s", err)
+		return
+	} else if err.Error() != expectedErr {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		return
+	}
+}
+
+func TestInvalidConfig16(t *testing.T) {
+	filename := filepath.Join("testdata", "invalid_config16.yaml")
+	expectedErr := "netbox.pageSize: must be at most 1000. Is 5000"
+	_, err := ParseConfig(filename)
+	if err == nil {
+		t.Errorf("%s", err)
+		return
+	} else if err.Error() != expectedErr {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		return
+	}
+}
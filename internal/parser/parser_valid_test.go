@@ -22,9 +22,15 @@ func TestValidConfig(t *testing.T) {
 			Port:          666,
 			ValidateCert:  false, // Default
 			Timeout:       constants.DefaultTimeout,
-			Tag:           "netbox-ssot", // Default
-			TagColor:      "00add8",      // Default
-			RemoveOrphans: true,          // Default
+			Tag:           "netbox-ssot",             // Default
+			TagColor:      "00add8",                  // Default
+			RemoveOrphans: true,                      // Default
+			PageSize:      constants.DefaultPageSize, // Default
+		},
+		DNS: &DNSConfig{
+			Servers:     []string{"10.0.0.53:53", "[fd00::53]:5353"},
+			Timeout:     5,
+			Concurrency: constants.DefaultDNSConcurrency, // Default
 		},
 		Sources: []SourceConfig{
 			{
@@ -40,9 +46,10 @@ func TestValidConfig(t *testing.T) {
 					"192.168.0.0/16",
 					"fd00::/8",
 				},
-				ValidateCert: true,
-				Tag:          "testing",
-				TagColor:     "ff0000",
+				ValidateCert:  true,
+				Tag:           "testing",
+				TagColor:      "ff0000",
+				DNSNamePolicy: DNSNamePolicyPTR, // Default
 			},
 			{
 				Name:       "prodolvm",
@@ -55,9 +62,10 @@ func TestValidConfig(t *testing.T) {
 				PermittedSubnets: []string{
 					"172.16.0.0/12",
 				},
-				ValidateCert: false,
-				Tag:          "Source: prodolvm", // Default
-				TagColor:     "aa1409",           // Default
+				ValidateCert:  false,
+				Tag:           "Source: prodolvm", // Default
+				TagColor:      "aa1409",           // Default
+				DNSNamePolicy: DNSNamePolicyPTR,   // Default
 				ClusterSiteRelations: []string{
 					"Cluster_NYC = New York",
 					"Cluster_FFM.* = Frankfurt",
@@ -0,0 +1,132 @@
+// Package resolver resolves dns names of ip addresses and ip addresses of hostnames.
+package resolver
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Resolver performs dns queries with timeouts and limited concurrency.
+// Results (including failed queries) are cached for the lifetime of the resolver,
+// which is a single run, so the same ip address always gets the same dns name.
+//
+// Resolver is safe for concurrent use.
+type Resolver struct {
+	resolver *net.Resolver
+	timeout  time.Duration
+	// semaphore limits number of concurrent dns queries
+	semaphore chan struct{}
+
+	mu        sync.Mutex
+	ptrCache  map[string]*cacheEntry
+	hostCache map[string]*cacheEntry
+}
+
+// cacheEntry is a result of a dns query. Done is closed, when the query is finished,
+// so concurrent callers for the same key wait for a single query.
+type cacheEntry struct {
+	done   chan struct{}
+	values []string
+}
+
+// New returns a new resolver, that queries given dns servers (host:port) in round robin
+// fashion. If servers are empty, system resolver configuration is used.
+func New(servers []string, timeout time.Duration, concurrency int) *Resolver {
+	netResolver := &net.Resolver{PreferGo: true}
+	if len(servers) > 0 {
+		var next uint32
+		netResolver.Dial = func(ctx context.Context, network, _ string) (net.Conn, error) {
+			server := servers[(atomic.AddUint32(&next, 1)-1)%uint32(len(servers))]
+			dialer := net.Dialer{}
+			return dialer.DialContext(ctx, network, server)
+		}
+	}
+	return &Resolver{
+		resolver:  netResolver,
+		timeout:   timeout,
+		semaphore: make(chan struct{}, concurrency),
+		ptrCache:  make(map[string]*cacheEntry),
+		hostCache: make(map[string]*cacheEntry),
+	}
+}
+
+// ReverseLookup returns dns name from the PTR record of the ip address (with or without mask).
+// If there are multiple PTR records, the first one in alphabetical order is returned.
+// If the lookup fails, it returns an empty string.
+func (r *Resolver) ReverseLookup(ipAddress string) string {
+	ipAddress = strings.Split(ipAddress, "/")[0]
+	names := r.query(r.ptrCache, ipAddress, func(ctx context.Context) ([]string, error) {
+		names, err := r.resolver.LookupAddr(ctx, ipAddress)
+		for i := range names {
+			names[i] = strings.TrimSuffix(names[i], ".")
+		}
+		return names, err
+	})
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// LookupAll returns all ip addresses of the hostname in alphabetical order.
+// If the lookup fails, it returns nil.
+func (r *Resolver) LookupAll(hostname string) []string {
+	return r.query(r.hostCache, hostname, func(ctx context.Context) ([]string, error) {
+		return r.resolver.LookupHost(ctx, hostname)
+	})
+}
+
+// Lookup returns the first ip address of the hostname. If the lookup fails,
+// it returns an empty string.
+func (r *Resolver) Lookup(hostname string) string {
+	addresses := r.LookupAll(hostname)
+	if len(addresses) == 0 {
+		return ""
+	}
+	return addresses[0]
+}
+
+// Prefetch concurrently performs reverse lookups of all ip addresses, so following
+// calls of ReverseLookup are served from the cache.
+func (r *Resolver) Prefetch(ipAddresses []string) {
+	var wg sync.WaitGroup
+	for _, ipAddress := range ipAddresses {
+		wg.Add(1)
+		go func(ipAddress string) {
+			defer wg.Done()
+			r.ReverseLookup(ipAddress)
+		}(ipAddress)
+	}
+	wg.Wait()
+}
+
+// query returns cached result for the key, or performs lookup and caches its sorted result.
+func (r *Resolver) query(cache map[string]*cacheEntry, key string, lookup func(context.Context) ([]string, error)) []string {
+	r.mu.Lock()
+	if entry, ok := cache[key]; ok {
+		r.mu.Unlock()
+		<-entry.done
+		return entry.values
+	}
+	entry := &cacheEntry{done: make(chan struct{})}
+	cache[key] = entry
+	r.mu.Unlock()
+
+	r.semaphore <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	values, err := lookup(ctx)
+	cancel()
+	<-r.semaphore
+
+	if err == nil {
+		sort.Strings(values)
+		entry.values = values
+	}
+	close(entry.done)
+	return entry.values
+}
@@ -0,0 +1,157 @@
+package resolver
+
+import (
+	"net"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// testServer is an in-process dns server, that answers PTR and A queries
+// from the records. Queries for names in silent are never answered.
+type testServer struct {
+	conn    net.PacketConn
+	ptr     map[string]string
+	a       map[string][]string
+	silent  map[string]bool
+	queries atomic.Int32
+}
+
+func newTestServer(t *testing.T) *testServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &testServer{
+		conn: conn,
+		ptr: map[string]string{
+			"10.2.0.192.in-addr.arpa.": "host1.example.com.",
+		},
+		a: map[string][]string{
+			"host1.example.com.": {"192.0.2.10"},
+			"multi.example.com.": {"192.0.2.21", "192.0.2.20"},
+		},
+		silent: map[string]bool{
+			"11.2.0.192.in-addr.arpa.": true,
+		},
+	}
+	t.Cleanup(func() { conn.Close() })
+	go server.serve()
+	return server
+}
+
+func (s *testServer) serve() {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		var parser dnsmessage.Parser
+		header, err := parser.Start(buf[:n])
+		if err != nil {
+			continue
+		}
+		question, err := parser.Question()
+		if err != nil {
+			continue
+		}
+		name := question.Name.String()
+		if s.silent[name] {
+			continue
+		}
+		s.queries.Add(1)
+		builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true})
+		builder.EnableCompression()
+		_ = builder.StartQuestions()
+		_ = builder.Question(question)
+		_ = builder.StartAnswers()
+		resourceHeader := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60}
+		switch question.Type {
+		case dnsmessage.TypePTR:
+			if ptr, ok := s.ptr[name]; ok {
+				_ = builder.PTRResource(resourceHeader, dnsmessage.PTRResource{PTR: dnsmessage.MustNewName(ptr)})
+			}
+		case dnsmessage.TypeA:
+			for _, address := range s.a[name] {
+				var a dnsmessage.AResource
+				copy(a.A[:], net.ParseIP(address).To4())
+				_ = builder.AResource(resourceHeader, a)
+			}
+		}
+		response, err := builder.Finish()
+		if err != nil {
+			continue
+		}
+		_, _ = s.conn.WriteTo(response, addr)
+	}
+}
+
+func TestReverseLookup(t *testing.T) {
+	server := newTestServer(t)
+	r := New([]string{server.conn.LocalAddr().String()}, time.Second, 2)
+	tests := []struct {
+		name      string
+		ipAddress string
+		expected  string
+	}{
+		{name: "PTR record", ipAddress: "192.0.2.10", expected: "host1.example.com"},
+		{name: "Ip address with mask", ipAddress: "192.0.2.10/24", expected: "host1.example.com"},
+		{name: "No PTR record", ipAddress: "192.0.2.12", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.ReverseLookup(tt.ipAddress); got != tt.expected {
+				t.Errorf("ReverseLookup() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	server := newTestServer(t)
+	r := New([]string{server.conn.LocalAddr().String()}, time.Second, 2)
+	if got := r.Lookup("host1.example.com"); got != "192.0.2.10" {
+		t.Errorf("Lookup() = %q, want %q", got, "192.0.2.10")
+	}
+	if got, want := r.LookupAll("multi.example.com"), []string{"192.0.2.20", "192.0.2.21"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LookupAll() = %v, want %v", got, want)
+	}
+	if got := r.Lookup("missing.example.com"); got != "" {
+		t.Errorf("Lookup() = %q, want empty string", got)
+	}
+}
+
+func TestCache(t *testing.T) {
+	server := newTestServer(t)
+	r := New([]string{server.conn.LocalAddr().String()}, time.Second, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.ReverseLookup("192.0.2.10")
+		}()
+	}
+	wg.Wait()
+	r.Prefetch([]string{"192.0.2.10", "192.0.2.10/24"})
+	if queries := server.queries.Load(); queries != 1 {
+		t.Errorf("server received %d queries, want 1", queries)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	server := newTestServer(t)
+	r := New([]string{server.conn.LocalAddr().String()}, 200*time.Millisecond, 2)
+	start := time.Now()
+	if got := r.ReverseLookup("192.0.2.11"); got != "" {
+		t.Errorf("ReverseLookup() = %q, want empty string", got)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ReverseLookup() took %s, timeout was not respected", elapsed)
+	}
+}
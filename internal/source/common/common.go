@@ -1,10 +1,19 @@
 package common
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
 	"github.com/bl4ko/netbox-ssot/internal/logger"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
 	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/resolver"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
 )
 
 // Source is an interface for all sources (e.g. oVirt, VMware, etc.).
@@ -20,4 +29,124 @@ type Config struct {
 	Logger       *logger.Logger
 	SourceConfig *parser.SourceConfig
 	SourceTags   []*objects.Tag
+	// Resolver is shared between all sources of the run, so dns queries are cached across sources
+	Resolver *resolver.Resolver
+
+	// User defined vrf relations, that are used by MatchIPToVRF. Initialized in source's Init.
+	ClusterVRFRelations map[string]string
+	SubnetVRFRelations  map[string]string
+
+	// autoPrefixes are prefixes collected with CollectPrefix (vrf id-prefix -> prefix)
+	autoPrefixes map[string]*autoPrefix
+}
+
+// resolver returns resolver of the source. If source was created without it,
+// resolver with default settings is created.
+func (c *Config) resolver() *resolver.Resolver {
+	if c.Resolver == nil {
+		c.Resolver = resolver.New(nil, constants.DefaultDNSTimeout*time.Second, constants.DefaultDNSConcurrency)
+	}
+	return c.Resolver
+}
+
+// DNSName returns dns name of the ip address (with or without mask), based on source's dnsNamePolicy.
+// GuestHostname is hostname reported by the guest (e.g. vm's guest tools), and can be empty.
+//
+// PTR record of the ip address is used, unless policy is guest (guest hostname is preferred) or
+// verified (guest hostname is preferred only if it resolves to the ip address).
+// If reverse lookups are disabled for the source, PTR record is never queried.
+func (c *Config) DNSName(ipAddress string, guestHostname string) string {
+	ipAddress = strings.Split(ipAddress, "/")[0]
+	if guestHostname != "" {
+		switch c.SourceConfig.DNSNamePolicy {
+		case parser.DNSNamePolicyGuest:
+			return guestHostname
+		case parser.DNSNamePolicyVerified:
+			if slices.Contains(c.resolver().LookupAll(guestHostname), ipAddress) {
+				return guestHostname
+			}
+		}
+	}
+	if c.SourceConfig.DisableReverseLookup {
+		return ""
+	}
+	return c.resolver().ReverseLookup(ipAddress)
+}
+
+// Lookup returns the first ip address of the hostname, or empty string if lookup fails.
+func (c *Config) Lookup(hostname string) string {
+	return c.resolver().Lookup(hostname)
+}
+
+// PrefetchDNSNames concurrently queries PTR records of all ip addresses, so following
+// calls of DNSName don't have to wait for them.
+func (c *Config) PrefetchDNSNames(ipAddresses []string) {
+	if c.SourceConfig.DisableReverseLookup {
+		return
+	}
+	c.resolver().Prefetch(ipAddresses)
+}
+
+// IsPermittedIPAddress returns true if ipAddress is part of source's permittedSubnets
+// and is not part of its ignoredSubnets. Ip addresses that are not permitted are not synced,
+// so the ones that already exist in netbox are left to the orphan manager.
+func (c *Config) IsPermittedIPAddress(ipAddress string) bool {
+	permittedSubnets := c.SourceConfig.PermittedSubnets
+	// For snmp permittedSubnets are targets that are polled, not a filter
+	if c.SourceConfig.Type == constants.SNMP {
+		permittedSubnets = nil
+	}
+	if utils.IsPermittedIPAddress(ipAddress, permittedSubnets, c.SourceConfig.IgnoredSubnets) {
+		return true
+	}
+	c.Logger.Debugf("ip address %s is not permitted by permittedSubnets and ignoredSubnets. Skipping...", ipAddress)
+	return false
+}
+
+// MatchIPToVRF returns VRF of the ip address (or prefix), that belongs to the given cluster (can be nil).
+// SubnetVrfRelations (the most specific subnet) have precedence over clusterVrfRelations,
+// which have precedence over source's vrf. VRFs that don't exist yet are created.
+//
+// In case there is no match, it will return nil, which means the global table.
+func (c *Config) MatchIPToVRF(nbi *inventory.NetboxInventory, ipAddress string, cluster *objects.Cluster) (*objects.VRF, error) {
+	vrfName := c.SourceConfig.VRF
+	if cluster != nil && len(c.ClusterVRFRelations) > 0 {
+		clusterVRFName, err := utils.MatchStringToValue(cluster.Name, c.ClusterVRFRelations)
+		if err != nil {
+			return nil, fmt.Errorf("matching cluster to vrf: %s", err)
+		}
+		if clusterVRFName != "" {
+			vrfName = clusterVRFName
+		}
+	}
+	if len(c.SubnetVRFRelations) > 0 {
+		subnets := make([]string, 0, len(c.SubnetVRFRelations))
+		for subnet := range c.SubnetVRFRelations {
+			subnets = append(subnets, subnet)
+		}
+		if subnet := utils.MatchIPAddressToSubnet(ipAddress, subnets); subnet != "" {
+			vrfName = c.SubnetVRFRelations[subnet]
+		}
+	}
+	if vrfName == "" {
+		return nil, nil
+	}
+	// Existing vrfs are reused as they are, unless they were created by netbox-ssot and
+	// are still in orphan manager, in which case they have to be added to be retained
+	if vrf, ok := nbi.VRFsIndexByName[vrfName]; ok && !nbi.OrphanManager[service.VRFsAPIPath][vrf.ID] {
+		return vrf, nil
+	}
+	vrf, err := nbi.AddVRF(&objects.VRF{
+		NetboxObject: objects.NetboxObject{
+			Tags: c.SourceTags,
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName: c.SourceConfig.Name,
+			},
+		},
+		Name: vrfName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("add vrf %s: %s", vrfName, err)
+	}
+	return vrf, nil
 }
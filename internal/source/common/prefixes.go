@@ -0,0 +1,132 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// autoPrefix is a prefix derived from ip addresses of interfaces. It stores
+// vlans, sites and tenants of all interfaces the prefix was derived from (id -> object,
+// id 0 is used for nil), so it can be decided whether they are unambiguous.
+type autoPrefix struct {
+	prefix  string
+	vrf     *objects.VRF
+	vlans   map[int]*objects.Vlan
+	sites   map[int]*objects.Site
+	tenants map[int]*objects.Tenant
+}
+
+// CollectPrefix derives prefix from the ip address (with mask) of an interface, and stores
+// it together with interface's vlan, site and tenant (which can be nil). Collected prefixes
+// are added to netbox with SyncAutoPrefixes.
+//
+// Netbox relates each ip address to the most specific prefix of the same vrf, so prefix
+// is derived in the vrf of the ip address. Ip addresses are never moved to vrfs of
+// other existing prefixes, so they can be related to them.
+//
+// Prefixes are collected only if autoPrefixes is enabled. Host prefixes (/32, /128)
+// are skipped, and if autoPrefixesPermittedOnly is enabled, so are prefixes that
+// are not fully contained in one of permittedSubnets.
+func (c *Config) CollectPrefix(ipAddress string, vrf *objects.VRF, vlan *objects.Vlan, site *objects.Site, tenant *objects.Tenant) {
+	if !c.SourceConfig.AutoPrefixes {
+		return
+	}
+	prefix, err := utils.GetPrefix(ipAddress)
+	if err != nil || utils.IsHostPrefix(prefix) {
+		return
+	}
+	if c.SourceConfig.AutoPrefixesPermittedOnly && len(c.SourceConfig.PermittedSubnets) > 0 {
+		permitted := false
+		for _, subnet := range c.SourceConfig.PermittedSubnets {
+			if utils.SubnetContainsSubnet(prefix, subnet) {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			c.Logger.Debugf("prefix %s is not within permittedSubnets. Skipping...", prefix)
+			return
+		}
+	}
+	var vrfID int
+	if vrf != nil {
+		vrfID = vrf.ID
+	}
+	key := fmt.Sprintf("%d-%s", vrfID, prefix)
+	if c.autoPrefixes == nil {
+		c.autoPrefixes = make(map[string]*autoPrefix)
+	}
+	if _, ok := c.autoPrefixes[key]; !ok {
+		c.autoPrefixes[key] = &autoPrefix{
+			prefix:  prefix,
+			vrf:     vrf,
+			vlans:   make(map[int]*objects.Vlan),
+			sites:   make(map[int]*objects.Site),
+			tenants: make(map[int]*objects.Tenant),
+		}
+	}
+	collected := c.autoPrefixes[key]
+	if vlan != nil {
+		collected.vlans[vlan.ID] = vlan
+	} else {
+		collected.vlans[0] = nil
+	}
+	if site != nil {
+		collected.sites[site.ID] = site
+	} else {
+		collected.sites[0] = nil
+	}
+	if tenant != nil {
+		collected.tenants[tenant.ID] = tenant
+	} else {
+		collected.tenants[0] = nil
+	}
+}
+
+// unambiguous returns the only value of the map, or nil if there are multiple values.
+func unambiguous[T any](values map[int]*T) *T {
+	if len(values) != 1 {
+		return nil
+	}
+	for _, value := range values {
+		return value
+	}
+	return nil
+}
+
+// SyncAutoPrefixes adds all prefixes collected with CollectPrefix to netbox.
+// Vlan, site and tenant are set only if all interfaces of the prefix share them.
+func (c *Config) SyncAutoPrefixes(nbi *inventory.NetboxInventory) error {
+	keys := make([]string, 0, len(c.autoPrefixes))
+	for key := range c.autoPrefixes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		collected := c.autoPrefixes[key]
+		_, err := nbi.AddPrefix(&objects.Prefix{
+			NetboxObject: objects.NetboxObject{
+				Tags: c.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: c.SourceConfig.Name,
+				},
+			},
+			Prefix: collected.prefix,
+			VRF:    collected.vrf,
+			Status: &objects.PrefixStatusActive,
+			Vlan:   unambiguous(collected.vlans),
+			Site:   unambiguous(collected.sites),
+			Tenant: unambiguous(collected.tenants),
+		})
+		if err != nil {
+			return fmt.Errorf("adding prefix %s: %s", collected.prefix, err)
+		}
+	}
+	c.autoPrefixes = nil
+	return nil
+}
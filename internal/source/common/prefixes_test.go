@@ -0,0 +1,139 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory/inventorytest"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+)
+
+// interfaceAddress is an ip address of an interface, from which prefix is derived.
+type interfaceAddress struct {
+	address string
+	vrf     *objects.VRF
+	vlan    *objects.Vlan
+	site    *objects.Site
+	tenant  *objects.Tenant
+}
+
+// expectedPrefix is a prefix, that should be synced, with ids of its vlan, site and tenant (0 for nil).
+type expectedPrefix struct {
+	vrfID    int
+	prefix   string
+	vlanID   int
+	siteID   int
+	tenantID int
+}
+
+func TestSyncAutoPrefixes(t *testing.T) {
+	vrf := &objects.VRF{NetboxObject: objects.NetboxObject{ID: 100}, Name: "prod"}
+	vlan10 := &objects.Vlan{NetboxObject: objects.NetboxObject{ID: 10}, Name: "vlan10", Vid: 10}
+	vlan20 := &objects.Vlan{NetboxObject: objects.NetboxObject{ID: 20}, Name: "vlan20", Vid: 20}
+	site := &objects.Site{NetboxObject: objects.NetboxObject{ID: 30}, Name: "site1"}
+	tenant := &objects.Tenant{NetboxObject: objects.NetboxObject{ID: 40}, Name: "tenant1"}
+	tests := []struct {
+		name             string
+		sourceConfig     parser.SourceConfig
+		addresses        []interfaceAddress
+		expectedPrefixes []expectedPrefix
+	}{
+		{
+			name:         "Disabled",
+			sourceConfig: parser.SourceConfig{},
+			addresses:    []interfaceAddress{{address: "10.0.0.1/24"}},
+		},
+		{
+			name:         "Shared vlan, site and tenant",
+			sourceConfig: parser.SourceConfig{AutoPrefixes: true},
+			addresses: []interfaceAddress{
+				{address: "10.0.0.1/24", vlan: vlan10, site: site, tenant: tenant},
+				{address: "10.0.0.2/24", vlan: vlan10, site: site, tenant: tenant},
+			},
+			expectedPrefixes: []expectedPrefix{{prefix: "10.0.0.0/24", vlanID: 10, siteID: 30, tenantID: 40}},
+		},
+		{
+			name:         "Ambiguous vlan and tenant",
+			sourceConfig: parser.SourceConfig{AutoPrefixes: true},
+			addresses: []interfaceAddress{
+				{address: "10.0.0.1/24", vlan: vlan10, site: site, tenant: tenant},
+				{address: "10.0.0.2/24", vlan: vlan20, site: site},
+			},
+			expectedPrefixes: []expectedPrefix{{prefix: "10.0.0.0/24", siteID: 30}},
+		},
+		{
+			name:         "Prefix in vrf of the ip address",
+			sourceConfig: parser.SourceConfig{AutoPrefixes: true},
+			addresses: []interfaceAddress{
+				{address: "10.0.0.1/24", vrf: vrf, site: site},
+				{address: "10.0.0.2/24", site: site},
+			},
+			expectedPrefixes: []expectedPrefix{{vrfID: 100, prefix: "10.0.0.0/24", siteID: 30}, {prefix: "10.0.0.0/24", siteID: 30}},
+		},
+		{
+			name:             "Host prefixes are skipped",
+			sourceConfig:     parser.SourceConfig{AutoPrefixes: true},
+			addresses:        []interfaceAddress{{address: "10.0.0.1/32"}, {address: "2001:db8::1/128"}, {address: "2001:db8::1/64"}},
+			expectedPrefixes: []expectedPrefix{{prefix: "2001:db8::/64"}},
+		},
+		{
+			name:             "Permitted subnets without autoPrefixesPermittedOnly",
+			sourceConfig:     parser.SourceConfig{AutoPrefixes: true, PermittedSubnets: []string{"10.0.0.0/16"}},
+			addresses:        []interfaceAddress{{address: "10.0.1.1/24"}, {address: "192.168.0.1/24"}},
+			expectedPrefixes: []expectedPrefix{{prefix: "10.0.1.0/24"}, {prefix: "192.168.0.0/24"}},
+		},
+		{
+			name:             "Only prefixes within permitted subnets",
+			sourceConfig:     parser.SourceConfig{AutoPrefixes: true, AutoPrefixesPermittedOnly: true, PermittedSubnets: []string{"10.0.0.0/16"}},
+			addresses:        []interfaceAddress{{address: "10.0.1.1/24"}, {address: "10.0.2.1/8"}, {address: "192.168.0.1/24"}},
+			expectedPrefixes: []expectedPrefix{{prefix: "10.0.1.0/24"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testLogger, err := logger.New("", logger.ERROR, "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			sourceConfig := tt.sourceConfig
+			c := &Config{Logger: testLogger, SourceConfig: &sourceConfig}
+			for _, addr := range tt.addresses {
+				c.CollectPrefix(addr.address, addr.vrf, addr.vlan, addr.site, addr.tenant)
+			}
+			nbi, _ := inventorytest.NewInventory(t)
+			if err := c.SyncAutoPrefixes(nbi); err != nil {
+				t.Fatal(err)
+			}
+
+			syncedPrefixes := 0
+			for _, vrfPrefixes := range nbi.PrefixesIndexByVRFIDAndPrefix {
+				syncedPrefixes += len(vrfPrefixes)
+			}
+			if syncedPrefixes != len(tt.expectedPrefixes) {
+				t.Errorf("synced %d prefixes (%v), want %d", syncedPrefixes, nbi.PrefixesIndexByVRFIDAndPrefix, len(tt.expectedPrefixes))
+			}
+			for _, expected := range tt.expectedPrefixes {
+				prefix := nbi.PrefixesIndexByVRFIDAndPrefix[expected.vrfID][expected.prefix]
+				if prefix == nil {
+					t.Errorf("prefix %s in vrf %d was not synced", expected.prefix, expected.vrfID)
+					continue
+				}
+				if vlanID, siteID, tenantID := objectID(prefix.Vlan), objectID(prefix.Site), objectID(prefix.Tenant); vlanID != expected.vlanID || siteID != expected.siteID || tenantID != expected.tenantID {
+					t.Errorf("prefix %s has vlan %d, site %d and tenant %d, want %d, %d and %d", expected.prefix, vlanID, siteID, tenantID, expected.vlanID, expected.siteID, expected.tenantID)
+				}
+			}
+		})
+	}
+}
+
+// objectID returns id of the netbox object, or 0 if it is nil.
+func objectID[T any, P interface {
+	*T
+	GetNetboxObject() *objects.NetboxObject
+}](obj P) int {
+	if obj == nil {
+		return 0
+	}
+	return obj.GetNetboxObject().ID
+}
@@ -114,3 +114,66 @@ func MatchVMToTenant(nbi *inventory.NetboxInventory, vmName string, vmTenantRela
 	}
 	return nil, nil
 }
+
+// Function that matches Vm from vmAttribute (e.g. vm's folder path) to Site using vmSiteRelations.
+//
+// In case that there is not match or vmSiteRelations is nil, it will return nil.
+func MatchVMToSite(nbi *inventory.NetboxInventory, vmAttribute string, vmSiteRelations map[string]string) (*objects.Site, error) {
+	if vmSiteRelations == nil {
+		return nil, nil
+	}
+	siteName, err := utils.MatchStringToValue(vmAttribute, vmSiteRelations)
+	if err != nil {
+		return nil, fmt.Errorf("matching vm to site: %s", err)
+	}
+	if siteName != "" {
+		site, ok := nbi.SitesIndexByName[siteName]
+		if !ok {
+			return nil, fmt.Errorf("site with name %s doesn't exist", siteName)
+		}
+		return site, nil
+	}
+	return nil, nil
+}
+
+// Function that matches Cluster from clusterName to Site using clusterSiteRelations.
+//
+// In case that there is not match or clusterSiteRelations is nil, it will return nil.
+func MatchClusterToSite(nbi *inventory.NetboxInventory, clusterName string, clusterSiteRelations map[string]string) (*objects.Site, error) {
+	if clusterSiteRelations == nil {
+		return nil, nil
+	}
+	siteName, err := utils.MatchStringToValue(clusterName, clusterSiteRelations)
+	if err != nil {
+		return nil, fmt.Errorf("matching cluster to site: %s", err)
+	}
+	if siteName != "" {
+		site, ok := nbi.SitesIndexByName[siteName]
+		if !ok {
+			return nil, fmt.Errorf("site with name %s doesn't exist", siteName)
+		}
+		return site, nil
+	}
+	return nil, nil
+}
+
+// Function that matches Cluster from clusterName to Tenant using clusterTenantRelations.
+//
+// In case that there is not match or clusterTenantRelations is nil, it will return nil.
+func MatchClusterToTenant(nbi *inventory.NetboxInventory, clusterName string, clusterTenantRelations map[string]string) (*objects.Tenant, error) {
+	if clusterTenantRelations == nil {
+		return nil, nil
+	}
+	tenantName, err := utils.MatchStringToValue(clusterName, clusterTenantRelations)
+	if err != nil {
+		return nil, fmt.Errorf("matching cluster to tenant: %s", err)
+	}
+	if tenantName != "" {
+		tenant, ok := nbi.TenantsIndexByName[tenantName]
+		if !ok {
+			return nil, fmt.Errorf("tenant with name %s doesn't exist", tenantName)
+		}
+		return tenant, nil
+	}
+	return nil, nil
+}
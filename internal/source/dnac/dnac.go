@@ -16,20 +16,31 @@ type Source struct {
 	common.Config
 
 	// Dnac fetched data. Initialized in init functions.
-	Sites      map[string]dnac.ResponseSitesGetSiteResponse                // SiteId -> Site
-	Devices    map[string]dnac.ResponseDevicesGetDeviceListResponse        // DeviceId -> Device
-	Interfaces map[string]dnac.ResponseDevicesGetAllInterfacesResponse     // InterfaceId -> Interface
-	Vlans      map[int]dnac.ResponseDevicesGetDeviceInterfaceVLANsResponse // VlanId -> Vlan
+	Sites        map[string]dnac.ResponseSitesGetSiteResponse                     // SiteId -> Site
+	Devices      map[string]dnac.ResponseDevicesGetDeviceListResponse             // DeviceId -> Device
+	Interfaces   map[string]dnac.ResponseDevicesGetAllInterfacesResponse          // InterfaceId -> Interface
+	Vlans        map[int]dnac.ResponseDevicesGetDeviceInterfaceVLANsResponse      // VlanId -> Vlan
+	SSIDs        map[string]dnac.ResponseItemWirelessGetEnterpriseSSIDSSIDDetails // SSID name -> SSID
+	AccessPoints map[string]dnac.ResponseWirelessGetAccessPointConfiguration      // DeviceId -> AccessPoint configuration
+	Stacks       map[string]dnac.ResponseDevicesGetStackDetailsForDeviceResponse  // DeviceId -> Stack details
+	Modules      map[string][]dnac.ResponseDevicesGetModulesResponse              // DeviceId -> Modules installed in the device
 	// Relations between dnac data. Initialized in init functions.
-	Site2Devices          map[string]map[string]bool // Site Id - > set of device Ids
-	Device2Site           map[string]string          // Device Id -> Site Id
-	DeviceID2InterfaceIDs map[string][]string        // DeviceId -> []InterfaceID
+	Site2Devices          map[string]map[string]bool                                       // Site Id - > set of device Ids
+	Device2Site           map[string]string                                                // Device Id -> Site Id
+	DeviceID2InterfaceIDs map[string][]string                                              // DeviceId -> []InterfaceID
+	InterfaceID2Vids      map[string][]int                                                 // InterfaceId -> vids carried by the interface in l2 topology
+	PhysicalLinks         map[string]dnac.ResponseTopologyGetPhysicalTopologyResponseLinks // LinkId -> Link
+	SiteID2SSIDs          map[string][]string                                              // SiteId -> names of SSIDs configured on the site
 
 	// Netbox related data for easier access. Initialized in sync functions.
-	VID2nbVlan              map[int]*objects.Vlan         // VlanId -> nbVlan
-	SiteID2nbSite           map[string]*objects.Site      // SiteId -> nbSite
-	DeviceID2nbDevice       map[string]*objects.Device    // DeviceId -> nbDevice
-	InterfaceID2nbInterface map[string]*objects.Interface // InterfaceId -> nbInterface
+	VID2nbVlan              map[int]*objects.Vlan              // VlanId -> nbVlan
+	SiteID2nbSite           map[string]*objects.Site           // SiteId -> nbSite
+	SiteID2nbRegion         map[string]*objects.Region         // SiteId -> nbRegion
+	SiteID2nbLocation       map[string]*objects.Location       // SiteId -> nbLocation
+	DeviceID2nbDevice       map[string]*objects.Device         // DeviceId -> nbDevice
+	DeviceID2nbStackMembers map[string]map[int]*objects.Device // DeviceId -> stack member number -> nbDevice
+	InterfaceID2nbInterface map[string]*objects.Interface      // InterfaceId -> nbInterface
+	SSID2nbWirelessLAN      map[string]*objects.WirelessLAN    // SSID name -> nbWirelessLAN
 
 	// User defined relations
 	HostTenantRelations map[string]string
@@ -51,6 +62,8 @@ func (ds *Source) Init() error {
 	ds.Logger.Debugf("VlanTenantRelations: %s", ds.VlanTenantRelations)
 	ds.HostTenantRelations = utils.ConvertStringsToRegexPairs(ds.SourceConfig.HostTenantRelations)
 	ds.Logger.Debugf("HostTenantRelations: %s", ds.HostTenantRelations)
+	ds.SubnetVRFRelations = utils.ConvertStringsToPairs(ds.SourceConfig.SubnetVRFRelations)
+	ds.Logger.Debugf("SubnetVRFRelations: %s", ds.SubnetVRFRelations)
 
 	// Initialize items from vsphere API to local storage
 	initFunctions := []func(*dnac.Client) error{
@@ -58,6 +71,12 @@ func (ds *Source) Init() error {
 		ds.InitMemberships,
 		ds.InitDevices,
 		ds.InitInterfaces,
+		ds.InitInterfaceVlans,
+		ds.InitPhysicalLinks,
+		ds.InitSSIDs,
+		ds.InitAccessPoints,
+		ds.InitStacks,
+		ds.InitModules,
 	}
 
 	for _, initFunc := range initFunctions {
@@ -76,14 +95,25 @@ func (ds *Source) Sync(nbi *inventory.NetboxInventory) error {
 	// initialize variables, that are shared between sync functions
 	ds.VID2nbVlan = make(map[int]*objects.Vlan)
 	ds.SiteID2nbSite = make(map[string]*objects.Site)
+	ds.SiteID2nbRegion = make(map[string]*objects.Region)
+	ds.SiteID2nbLocation = make(map[string]*objects.Location)
 	ds.DeviceID2nbDevice = make(map[string]*objects.Device)
+	ds.DeviceID2nbStackMembers = make(map[string]map[int]*objects.Device)
 	ds.InterfaceID2nbInterface = make(map[string]*objects.Interface)
+	ds.SSID2nbWirelessLAN = make(map[string]*objects.WirelessLAN)
 
 	syncFunctions := []func(*inventory.NetboxInventory) error{
 		ds.SyncSites,
 		ds.SyncVlans,
+		ds.SyncWirelessLANs,
 		ds.SyncDevices,
 		ds.SyncDeviceInterfaces,
+		ds.SyncAccessPointInterfaces,
+		ds.SyncInventoryItems,
+		ds.SyncCables,
+	}
+	if ds.SourceConfig.AutoPrefixes {
+		syncFunctions = append(syncFunctions, ds.SyncAutoPrefixes)
 	}
 
 	for _, syncFunc := range syncFunctions {
@@ -3,6 +3,9 @@ package dnac
 import (
 	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
 
 	dnac "github.com/cisco-en-programmability/dnacenter-go-sdk/v5/sdk"
 )
@@ -141,3 +144,172 @@ func (ds *Source) InitMemberships(c *dnac.Client) error {
 	}
 	return nil
 }
+
+// vlanNameRegex matches vlan names returned by dnac topology api, e.g. Vlan10.
+var vlanNameRegex = regexp.MustCompile(`(?i)^vlan(\d+)$`)
+
+// vlanNameToVid returns vid from the vlan name used in dnac topology api.
+func vlanNameToVid(vlanName string) (int, error) {
+	match := vlanNameRegex.FindStringSubmatch(vlanName)
+	if match == nil {
+		return 0, fmt.Errorf("wrong vlan name format: %s", vlanName)
+	}
+	return strconv.Atoi(match[1])
+}
+
+// InitInterfaceVlans collects vids that are carried by each interface. Dnac doesn't
+// expose allowed vlans of trunk ports, so they are extracted from l2 topology of each vlan.
+//
+// This function has to run after InitDevices and InitInterfaces.
+func (ds *Source) InitInterfaceVlans(c *dnac.Client) error {
+	vlanNames, response, err := c.Topology.GetVLANDetails()
+	if err != nil {
+		return fmt.Errorf("init vlan names: %s", err)
+	}
+	if response.StatusCode() != http.StatusOK {
+		return fmt.Errorf("init vlan names response code: %s", response.String())
+	}
+	interfaceVids := make(map[string]map[int]bool)
+	for _, vlanName := range vlanNames.Response {
+		vid, err := vlanNameToVid(vlanName)
+		if err != nil {
+			ds.Logger.Warningf("skipping l2 topology: %s", err)
+			continue
+		}
+		topology, _, err := c.Topology.GetTopologyDetails(vlanName)
+		if err != nil || topology.Response == nil || topology.Response.Links == nil {
+			ds.Logger.Warningf("can't get l2 topology for %s: %v", vlanName, err)
+			continue
+		}
+		for _, link := range *topology.Response.Links {
+			for _, interfaceID := range []string{link.StartPortID, link.EndPortID} {
+				if _, ok := ds.Interfaces[interfaceID]; !ok {
+					continue
+				}
+				if interfaceVids[interfaceID] == nil {
+					interfaceVids[interfaceID] = make(map[int]bool)
+				}
+				interfaceVids[interfaceID][vid] = true
+			}
+		}
+		// Vlans that have no svi on any device are also synced
+		if _, ok := ds.Vlans[vid]; !ok {
+			vlanNumber := vid
+			ds.Vlans[vid] = dnac.ResponseDevicesGetDeviceInterfaceVLANsResponse{
+				InterfaceName: vlanName,
+				VLANNumber:    &vlanNumber,
+			}
+		}
+	}
+	ds.InterfaceID2Vids = make(map[string][]int, len(interfaceVids))
+	for interfaceID, vids := range interfaceVids {
+		for vid := range vids {
+			ds.InterfaceID2Vids[interfaceID] = append(ds.InterfaceID2Vids[interfaceID], vid)
+		}
+		sort.Ints(ds.InterfaceID2Vids[interfaceID])
+	}
+	return nil
+}
+
+// InitPhysicalLinks collects links between interfaces from physical topology.
+func (ds *Source) InitPhysicalLinks(c *dnac.Client) error {
+	topology, response, err := c.Topology.GetPhysicalTopology(nil)
+	if err != nil {
+		return fmt.Errorf("init physical topology: %s", err)
+	}
+	if response.StatusCode() != http.StatusOK {
+		return fmt.Errorf("init physical topology response code: %s", response.String())
+	}
+	ds.PhysicalLinks = make(map[string]dnac.ResponseTopologyGetPhysicalTopologyResponseLinks)
+	if topology.Response == nil || topology.Response.Links == nil {
+		return nil
+	}
+	for _, link := range *topology.Response.Links {
+		ds.PhysicalLinks[link.ID] = link
+	}
+	return nil
+}
+
+// InitSSIDs collects enterprise SSIDs and sites on which they are configured.
+func (ds *Source) InitSSIDs(c *dnac.Client) error {
+	ssids, response, err := c.Wireless.GetEnterpriseSSID(nil)
+	if err != nil {
+		return fmt.Errorf("init ssids: %s", err)
+	}
+	if response.StatusCode() != http.StatusOK {
+		return fmt.Errorf("init ssids response code: %s", response.String())
+	}
+	ds.SSIDs = make(map[string]dnac.ResponseItemWirelessGetEnterpriseSSIDSSIDDetails)
+	ds.SiteID2SSIDs = make(map[string][]string)
+	for _, item := range *ssids {
+		if item.SSIDDetails == nil {
+			continue
+		}
+		for _, ssid := range *item.SSIDDetails {
+			ds.SSIDs[ssid.Name] = ssid
+			ds.SiteID2SSIDs[item.GroupUUID] = append(ds.SiteID2SSIDs[item.GroupUUID], ssid.Name)
+		}
+	}
+	return nil
+}
+
+// InitAccessPoints collects configuration of each access point.
+//
+// This function has to run after InitDevices.
+func (ds *Source) InitAccessPoints(c *dnac.Client) error {
+	ds.AccessPoints = make(map[string]dnac.ResponseWirelessGetAccessPointConfiguration)
+	for deviceID, device := range ds.Devices {
+		if !isAccessPoint(device) {
+			continue
+		}
+		apConfig, _, err := c.Wireless.GetAccessPointConfiguration(&dnac.GetAccessPointConfigurationQueryParams{Key: accessPointMAC(device)})
+		if err != nil || apConfig == nil {
+			ds.Logger.Warningf("can't get configuration of access point %s: %v", device.Hostname, err)
+			continue
+		}
+		ds.AccessPoints[deviceID] = *apConfig
+	}
+	return nil
+}
+
+// InitStacks collects stack members of stacked switches. Dnac reports stack
+// as a single device, with comma separated platform ids of its members.
+//
+// This function has to run after InitDevices.
+func (ds *Source) InitStacks(c *dnac.Client) error {
+	ds.Stacks = make(map[string]dnac.ResponseDevicesGetStackDetailsForDeviceResponse)
+	for deviceID, device := range ds.Devices {
+		if !isStack(device) {
+			continue
+		}
+		stack, _, err := c.Devices.GetStackDetailsForDevice(deviceID)
+		if err != nil || stack == nil || stack.Response == nil {
+			ds.Logger.Warningf("can't get stack details of device %s: %v", device.Hostname, err)
+			continue
+		}
+		if stack.Response.StackSwitchInfo == nil || len(*stack.Response.StackSwitchInfo) < 2 {
+			continue
+		}
+		ds.Stacks[deviceID] = *stack.Response
+	}
+	return nil
+}
+
+// InitModules collects modules (line cards, transceivers, power supplies...) of each device.
+//
+// This function has to run after InitDevices.
+func (ds *Source) InitModules(c *dnac.Client) error {
+	ds.Modules = make(map[string][]dnac.ResponseDevicesGetModulesResponse)
+	for deviceID, device := range ds.Devices {
+		if isAccessPoint(device) {
+			continue
+		}
+		modules, _, err := c.Devices.GetModules(&dnac.GetModulesQueryParams{DeviceID: deviceID})
+		if err != nil || modules == nil || modules.Response == nil {
+			ds.Logger.Warningf("can't get modules of device %s: %v", device.Hostname, err)
+			continue
+		}
+		ds.Modules[deviceID] = *modules.Response
+	}
+	return nil
+}
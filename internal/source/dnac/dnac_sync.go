@@ -2,6 +2,9 @@ package dnac
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -10,43 +13,156 @@ import (
 	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
 	"github.com/bl4ko/netbox-ssot/internal/source/common"
 	"github.com/bl4ko/netbox-ssot/internal/utils"
+	dnac "github.com/cisco-en-programmability/dnacenter-go-sdk/v5/sdk"
 )
 
-// Syncs dnac sites to netbox inventory.
+// Regexes for extracting stack member number from names of interfaces (e.g. GigabitEthernet2/0/1)
+// and modules (e.g. Switch 2 - C9300-48P - Power Supply A) of stacked switches.
+var (
+	stackInterfaceRegex = regexp.MustCompile(`^[A-Za-z-]+(\d+)/\d+/\d+`)
+	stackModuleRegex    = regexp.MustCompile(`(?i)^switch\s*(\d+)`)
+)
+
+// Types of dnac sites in the site hierarchy.
+const (
+	siteTypeArea     = "area"
+	siteTypeBuilding = "building"
+	siteTypeFloor    = "floor"
+)
+
+// siteLocation returns location attributes of dnac site. Global site has no location attributes.
+func siteLocation(site dnac.ResponseSitesGetSiteResponse) *dnac.ResponseSitesGetSiteResponseAdditionalInfoAttributes {
+	for _, additionalInfo := range site.AdditionalInfo {
+		if additionalInfo.Namespace == "Location" {
+			return &additionalInfo.Attributes
+		}
+	}
+	return nil
+}
+
+// siteType returns type of dnac site (area, building or floor).
+func siteType(site dnac.ResponseSitesGetSiteResponse) string {
+	if location := siteLocation(site); location != nil {
+		return location.Type
+	}
+	return ""
+}
+
+// SyncSites syncs dnac site hierarchy. Areas are synced as nested regions,
+// buildings as sites and floors as locations within their buildings.
 func (ds *Source) SyncSites(nbi *inventory.NetboxInventory) error {
-	for _, site := range ds.Sites {
+	for siteID, site := range ds.Sites {
+		if siteType(site) == siteTypeArea {
+			if _, err := ds.syncRegion(nbi, siteID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for siteID, site := range ds.Sites {
+		if siteType(site) == siteTypeFloor {
+			continue
+		}
+		// Areas are synced as sites only when devices are directly assigned to them
+		if siteType(site) != siteTypeBuilding && len(ds.Site2Devices[siteID]) == 0 {
+			continue
+		}
 		dnacSite := &objects.Site{
 			NetboxObject: objects.NetboxObject{
 				Tags: ds.Config.SourceTags,
-				CustomFields: map[string]string{
+				CustomFields: map[string]interface{}{
 					constants.CustomFieldSourceName: ds.SourceConfig.Name,
 				},
 			},
-			Name: site.Name,
-			Slug: utils.Slugify(site.Name),
+			Name:   site.Name,
+			Slug:   utils.Slugify(site.Name),
+			Region: ds.SiteID2nbRegion[site.ParentID],
 		}
-		for _, additionalInfo := range site.AdditionalInfo {
-			if additionalInfo.Namespace == "Location" {
-				dnacSite.PhysicalAddress = additionalInfo.Attributes.Address
-				longitude, err := strconv.ParseFloat(additionalInfo.Attributes.Longitude, 64)
-				if err != nil {
-					dnacSite.Longitude = longitude
-				}
-				latitude, err := strconv.ParseFloat(additionalInfo.Attributes.Latitude, 64)
-				if err != nil {
-					dnacSite.Latitude = latitude
-				}
+		if siteType(site) == siteTypeArea {
+			dnacSite.Region = ds.SiteID2nbRegion[siteID]
+		}
+		if location := siteLocation(site); location != nil {
+			dnacSite.PhysicalAddress = location.Address
+			longitude, err := strconv.ParseFloat(location.Longitude, 64)
+			if err == nil {
+				dnacSite.Longitude = longitude
+			}
+			latitude, err := strconv.ParseFloat(location.Latitude, 64)
+			if err == nil {
+				dnacSite.Latitude = latitude
 			}
 		}
 		nbSite, err := nbi.AddSite(dnacSite)
 		if err != nil {
 			return fmt.Errorf("adding site: %s", err)
 		}
-		ds.SiteID2nbSite[site.ID] = nbSite
+		ds.SiteID2nbSite[siteID] = nbSite
+	}
+
+	for siteID, site := range ds.Sites {
+		if siteType(site) != siteTypeFloor {
+			continue
+		}
+		buildingSite, ok := ds.SiteID2nbSite[site.ParentID]
+		if !ok {
+			ds.Logger.Warningf("floor %s has no synced building, so it will be skipped", site.SiteNameHierarchy)
+			continue
+		}
+		nbLocation, err := nbi.AddLocation(&objects.Location{
+			NetboxObject: objects.NetboxObject{
+				Tags: ds.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: ds.SourceConfig.Name,
+				},
+			},
+			Site:   buildingSite,
+			Name:   site.Name,
+			Slug:   utils.Slugify(site.Name),
+			Status: &objects.SiteStatusActive,
+		})
+		if err != nil {
+			return fmt.Errorf("adding location: %s", err)
+		}
+		// Devices on the floor belong to the floor's building
+		ds.SiteID2nbSite[siteID] = buildingSite
+		ds.SiteID2nbLocation[siteID] = nbLocation
 	}
 	return nil
 }
 
+// syncRegion syncs dnac area and all its parent areas as nested regions. Areas are
+// matched to existing regions by their parent region and name, because dnac hierarchies
+// often reuse names of areas under different parents.
+func (ds *Source) syncRegion(nbi *inventory.NetboxInventory, siteID string) (*objects.Region, error) {
+	if nbRegion, ok := ds.SiteID2nbRegion[siteID]; ok {
+		return nbRegion, nil
+	}
+	site, ok := ds.Sites[siteID]
+	if !ok || siteType(site) != siteTypeArea {
+		return nil, nil
+	}
+	parentRegion, err := ds.syncRegion(nbi, site.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	nbRegion, err := nbi.AddRegion(&objects.Region{
+		NetboxObject: objects.NetboxObject{
+			Tags: ds.Config.SourceTags,
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName: ds.SourceConfig.Name,
+			},
+		},
+		Name:   site.Name,
+		Slug:   utils.Slugify(site.Name),
+		Parent: parentRegion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("adding region: %s", err)
+	}
+	ds.SiteID2nbRegion[siteID] = nbRegion
+	return nbRegion, nil
+}
+
 func (ds *Source) SyncVlans(nbi *inventory.NetboxInventory) error {
 	for vid, vlan := range ds.Vlans {
 		vlanGroup, err := common.MatchVlanToGroup(nbi, vlan.InterfaceName, ds.VlanGroupRelations)
@@ -61,7 +177,7 @@ func (ds *Source) SyncVlans(nbi *inventory.NetboxInventory) error {
 			NetboxObject: objects.NetboxObject{
 				Tags:        ds.Config.SourceTags,
 				Description: vlan.VLANType,
-				CustomFields: map[string]string{
+				CustomFields: map[string]interface{}{
 					constants.CustomFieldSourceName: ds.SourceConfig.Name,
 				},
 			},
@@ -77,14 +193,19 @@ func (ds *Source) SyncVlans(nbi *inventory.NetboxInventory) error {
 		if vlan.Prefix != "" && vlan.NetworkAddress != "" {
 			// Create prefix for this vlan
 			prefix := fmt.Sprintf("%s/%s", vlan.NetworkAddress, vlan.Prefix)
+			vrf, err := ds.MatchIPToVRF(nbi, prefix, nil)
+			if err != nil {
+				return fmt.Errorf("matching prefix to vrf: %s", err)
+			}
 			_, err = nbi.AddPrefix(&objects.Prefix{
 				NetboxObject: objects.NetboxObject{
 					Tags: ds.Config.SourceTags,
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldSourceName: ds.SourceConfig.Name,
 					},
 				},
 				Prefix: prefix,
+				VRF:    vrf,
 				Tenant: vlanTenant,
 				Vlan:   newVlan,
 			})
@@ -148,6 +269,8 @@ func (ds *Source) SyncDevices(nbi *inventory.NetboxInventory) error {
 			continue
 		}
 
+		deviceLocation := ds.SiteID2nbLocation[ds.Device2Site[device.ID]]
+
 		if device.Type == "" {
 			ds.Logger.Errorf("Device type for device %s is empty, this should not happen. This device will be skipped", device.ID)
 		}
@@ -166,13 +289,19 @@ func (ds *Source) SyncDevices(nbi *inventory.NetboxInventory) error {
 			return fmt.Errorf("hostTenant: %s", err)
 		}
 
-		nbDevice, err := nbi.AddDevice(&objects.Device{
+		deviceCustomFields := map[string]interface{}{
+			constants.CustomFieldSourceName:   ds.SourceConfig.Name,
+			constants.CustomFieldSourceIDName: device.ID,
+		}
+		if isAccessPoint(device) {
+			deviceCustomFields[constants.CustomFieldWLCName] = ds.accessPointWLC(device)
+		}
+
+		newDevice := &objects.Device{
 			NetboxObject: objects.NetboxObject{
-				Tags:        ds.Config.SourceTags,
-				Description: description,
-				CustomFields: map[string]string{
-					constants.CustomFieldSourceName: ds.SourceConfig.Name,
-				},
+				Tags:         ds.Config.SourceTags,
+				Description:  description,
+				CustomFields: deviceCustomFields,
 			},
 			Name:         device.Hostname,
 			Tenant:       deviceTenant,
@@ -181,9 +310,20 @@ func (ds *Source) SyncDevices(nbi *inventory.NetboxInventory) error {
 			Platform:     platform,
 			Comments:     comments,
 			Site:         deviceSite,
+			Location:     deviceLocation,
 			DeviceType:   deviceType,
-		})
+		}
+
+		if stack, ok := ds.Stacks[device.ID]; ok {
+			nbDevice, err := ds.syncStackMembers(nbi, device, stack, newDevice)
+			if err != nil {
+				return fmt.Errorf("adding dnac stack: %s", err)
+			}
+			ds.DeviceID2nbDevice[device.ID] = nbDevice
+			continue
+		}
 
+		nbDevice, err := nbi.AddDevice(newDevice)
 		if err != nil {
 			return fmt.Errorf("adding dnac device: %s", err)
 		}
@@ -197,7 +337,7 @@ func (ds *Source) SyncDevices(nbi *inventory.NetboxInventory) error {
 func (ds *Source) SyncDeviceInterfaces(nbi *inventory.NetboxInventory) error {
 	for ifaceID, iface := range ds.Interfaces {
 		ifaceDescription := iface.Description
-		ifaceDevice := ds.DeviceID2nbDevice[iface.DeviceID]
+		ifaceDevice := ds.memberDevice(iface.DeviceID, iface.PortName)
 		var ifaceDuplex *objects.InterfaceDuplex
 		switch iface.Duplex {
 		case "FullDuplex":
@@ -249,23 +389,40 @@ func (ds *Source) SyncDeviceInterfaces(nbi *inventory.NetboxInventory) error {
 		var ifaceMode *objects.InterfaceMode
 		var ifaceAccessVlan *objects.Vlan
 		var ifaceTrunkVlans []*objects.Vlan
-		vid, err := strconv.Atoi(iface.VLANID)
-		if err != nil {
-			ds.Logger.Errorf("Can't parse vid for iface %s", iface.VLANID)
-			continue
+		portMode := iface.PortMode
+		if strings.HasPrefix(portMode, "dynamic") {
+			// Dynamic ports negotiate their mode with dtp. If port
+			// carries multiple vlans in l2 topology, it operates as trunk.
+			if len(ds.InterfaceID2Vids[ifaceID]) > 1 {
+				portMode = "trunk"
+			} else {
+				portMode = "access"
+			}
 		}
-		switch iface.PortMode {
+		switch portMode {
 		case "access":
+			vid, err := strconv.Atoi(iface.VLANID)
+			if err != nil {
+				ds.Logger.Errorf("Can't parse vid for iface %s", iface.VLANID)
+				continue
+			}
 			ifaceMode = &objects.InterfaceModeAccess
 			ifaceAccessVlan = ds.VID2nbVlan[vid]
 		case "trunk":
 			ifaceMode = &objects.InterfaceModeTagged
-			// TODO: ifaceTrunkVlans = append(ifaceTrunkVlans, ds.Vid2nbVlan[vid])
-		case "dynamic_auto":
-			// TODO: how to handle this mode in netbox
-			ds.Logger.Warningf("vlan mode 'dynamic_auto' is not implemented yet")
+			// Native vlan is untagged, all other carried vlans are tagged
+			nativeVid, err := strconv.Atoi(iface.NativeVLANID)
+			if err != nil {
+				nativeVid = -1
+			}
+			ifaceAccessVlan = ds.VID2nbVlan[nativeVid]
+			for _, vid := range ds.InterfaceID2Vids[ifaceID] {
+				if vlan, ok := ds.VID2nbVlan[vid]; ok && vid != nativeVid {
+					ifaceTrunkVlans = append(ifaceTrunkVlans, vlan)
+				}
+			}
 		case "routed":
-			ds.Logger.Warningf("vlan mode 'routed' is not implemented yet")
+			// Routed ports have no 802.1Q mode, only their l3 address is synced
 		default:
 			ds.Logger.Errorf("Unknown interface mode: '%s'", iface.PortMode)
 		}
@@ -274,8 +431,9 @@ func (ds *Source) SyncDeviceInterfaces(nbi *inventory.NetboxInventory) error {
 			NetboxObject: objects.NetboxObject{
 				Description: ifaceDescription,
 				Tags:        ds.Config.SourceTags,
-				CustomFields: map[string]string{
-					constants.CustomFieldSourceName: ds.SourceConfig.Name,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   ds.SourceConfig.Name,
+					constants.CustomFieldSourceIDName: ifaceID,
 				},
 			},
 			Name:         iface.PortName,
@@ -294,7 +452,7 @@ func (ds *Source) SyncDeviceInterfaces(nbi *inventory.NetboxInventory) error {
 		}
 
 		// Add IP address to the interface
-		if iface.IPv4Address != "" {
+		if iface.IPv4Address != "" && ds.IsPermittedIPAddress(iface.IPv4Address) {
 			defaultMask := 32
 			if iface.IPv4Mask != "" {
 				maskBits, err := utils.MaskToBits(iface.IPv4Mask)
@@ -303,22 +461,28 @@ func (ds *Source) SyncDeviceInterfaces(nbi *inventory.NetboxInventory) error {
 				}
 				defaultMask = maskBits
 			}
+			vrf, err := ds.MatchIPToVRF(nbi, iface.IPv4Address, nil)
+			if err != nil {
+				return fmt.Errorf("matching ip address to vrf: %s", err)
+			}
 			nbIPAddress, err := nbi.AddIPAddress(&objects.IPAddress{
 				NetboxObject: objects.NetboxObject{
 					Tags: ds.Config.SourceTags,
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldSourceName: ds.SourceConfig.Name,
 					},
 				},
 				Address:            fmt.Sprintf("%s/%d", iface.IPv4Address, defaultMask),
+				VRF:                vrf,
 				Status:             &objects.IPAddressStatusActive,
-				DNSName:            utils.ReverseLookup(iface.IPv4Address),
+				DNSName:            ds.DNSName(iface.IPv4Address, ""),
 				AssignedObjectType: objects.AssignedObjectTypeDeviceInterface,
 				AssignedObjectID:   nbIface.ID,
 			})
 			if err != nil {
 				return fmt.Errorf("adding ip address: %s", err)
 			}
+			ds.CollectPrefix(nbIPAddress.Address, vrf, ifaceAccessVlan, ifaceDevice.Site, ifaceDevice.Tenant)
 
 			// To determine if this interface, has the same IP address as the device's management IP
 			// we need to check if management IP is in the same subnet as this interface
@@ -337,3 +501,358 @@ func (ds *Source) SyncDeviceInterfaces(nbi *inventory.NetboxInventory) error {
 	}
 	return nil
 }
+
+// SyncCables creates cables between synced interfaces, that are linked in physical topology.
+func (ds *Source) SyncCables(nbi *inventory.NetboxInventory) error {
+	for linkID, link := range ds.PhysicalLinks {
+		aInterface, aOk := ds.InterfaceID2nbInterface[link.StartPortID]
+		bInterface, bOk := ds.InterfaceID2nbInterface[link.EndPortID]
+		if !aOk || !bOk {
+			ds.Logger.Debugf("link %s (%s <-> %s) doesn't connect synced interfaces, skipping it", linkID, link.StartPortName, link.EndPortName)
+			continue
+		}
+		_, err := nbi.AddCable(aInterface, bInterface, &objects.Cable{
+			NetboxObject: objects.NetboxObject{
+				Tags: ds.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: ds.SourceConfig.Name,
+				},
+			},
+			Status: &objects.CableStatusConnected,
+		})
+		if err != nil {
+			return fmt.Errorf("adding cable %s <-> %s: %s", link.StartPortName, link.EndPortName, err)
+		}
+	}
+	return nil
+}
+
+// Device family of wireless access points in dnac.
+const accessPointFamily = "Unified AP"
+
+// Name of the ethernet interface of access points.
+const accessPointEthernetName = "GigabitEthernet0"
+
+func isAccessPoint(device dnac.ResponseDevicesGetDeviceListResponse) bool {
+	return device.Family == accessPointFamily
+}
+
+// accessPointMAC returns ethernet mac address of the access point, which is
+// used as a key for access point configuration api.
+func accessPointMAC(device dnac.ResponseDevicesGetDeviceListResponse) string {
+	if device.ApEthernetMacAddress != nil {
+		if mac, ok := (*device.ApEthernetMacAddress).(string); ok && mac != "" {
+			return mac
+		}
+	}
+	return device.MacAddress
+}
+
+// accessPointWLC returns hostname of the wireless lan controller, with which access point is associated.
+func (ds *Source) accessPointWLC(device dnac.ResponseDevicesGetDeviceListResponse) string {
+	if apConfig, ok := ds.AccessPoints[device.ID]; ok && apConfig.PrimaryControllerName != "" {
+		return apConfig.PrimaryControllerName
+	}
+	for _, wlc := range ds.Devices {
+		if device.AssociatedWlcIP != "" && wlc.ManagementIPAddress == device.AssociatedWlcIP {
+			return wlc.Hostname
+		}
+	}
+	return device.AssociatedWlcIP
+}
+
+// ssidAuthType returns wireless lan auth type from dnac ssid security level (e.g. wpa2_enterprise, open...).
+func ssidAuthType(securityLevel string) *objects.WirelessLANAuthType {
+	securityLevel = strings.ToLower(securityLevel)
+	switch {
+	case strings.Contains(securityLevel, "enterprise"):
+		return &objects.WirelessLANAuthTypeWPAEnterprise
+	case strings.Contains(securityLevel, "personal"), strings.Contains(securityLevel, "psk"):
+		return &objects.WirelessLANAuthTypeWPAPersonal
+	case strings.Contains(securityLevel, "open"):
+		return &objects.WirelessLANAuthTypeOpen
+	}
+	return nil
+}
+
+// radioInterfaceType returns wireless interface type from dnac radio type (e.g. "802.11ax - 5 GHz").
+func radioInterfaceType(radioType string) *objects.InterfaceType {
+	radioType = strings.ToLower(radioType)
+	switch {
+	case strings.Contains(radioType, "802.11ax"), strings.Contains(radioType, "6 ghz"), strings.Contains(radioType, "6ghz"):
+		return &objects.IEEE80211AXInterfaceType
+	case strings.Contains(radioType, "802.11ac"):
+		return &objects.IEEE80211ACInterfaceType
+	case strings.Contains(radioType, "802.11n"):
+		return &objects.IEEE80211NInterfaceType
+	case strings.Contains(radioType, "802.11a"), strings.Contains(radioType, "5 ghz"), strings.Contains(radioType, "5ghz"):
+		return &objects.IEEE80211AInterfaceType
+	case strings.Contains(radioType, "802.11b"), strings.Contains(radioType, "802.11g"), strings.Contains(radioType, "2.4"):
+		return &objects.IEEE80211GInterfaceType
+	}
+	return &objects.OtherInterfaceType
+}
+
+// SyncWirelessLANs syncs enterprise SSIDs as wireless lans.
+func (ds *Source) SyncWirelessLANs(nbi *inventory.NetboxInventory) error {
+	for ssidName, ssid := range ds.SSIDs {
+		status := &objects.WirelessLANStatusActive
+		if ssid.IsEnabled != nil && !*ssid.IsEnabled {
+			status = &objects.WirelessLANStatusDisabled
+		}
+		nbWirelessLAN, err := nbi.AddWirelessLAN(&objects.WirelessLAN{
+			NetboxObject: objects.NetboxObject{
+				Tags:        ds.Config.SourceTags,
+				Description: ssid.WLANType,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: ds.SourceConfig.Name,
+				},
+			},
+			SSID:     ssidName,
+			Status:   status,
+			AuthType: ssidAuthType(ssid.SecurityLevel),
+		})
+		if err != nil {
+			return fmt.Errorf("adding wireless lan: %s", err)
+		}
+		ds.SSID2nbWirelessLAN[ssidName] = nbWirelessLAN
+	}
+	return nil
+}
+
+// siteWirelessLANs returns wireless lans configured on the site or any of its parent sites.
+func (ds *Source) siteWirelessLANs(siteID string) []*objects.WirelessLAN {
+	var wirelessLANs []*objects.WirelessLAN
+	visited := make(map[string]bool)
+	for siteID != "" && !visited[siteID] {
+		visited[siteID] = true
+		for _, ssidName := range ds.SiteID2SSIDs[siteID] {
+			if nbWirelessLAN, ok := ds.SSID2nbWirelessLAN[ssidName]; ok {
+				wirelessLANs = append(wirelessLANs, nbWirelessLAN)
+			}
+		}
+		siteID = ds.Sites[siteID].ParentID
+	}
+	return wirelessLANs
+}
+
+// SyncAccessPointInterfaces syncs ethernet and radio interfaces of access points.
+// Radio interfaces are linked to wireless lans configured on the access point's site.
+func (ds *Source) SyncAccessPointInterfaces(nbi *inventory.NetboxInventory) error {
+	for deviceID, apConfig := range ds.AccessPoints {
+		nbDevice, ok := ds.DeviceID2nbDevice[deviceID]
+		if !ok {
+			continue
+		}
+		if apConfig.EthMac != "" {
+			_, err := nbi.AddInterface(&objects.Interface{
+				NetboxObject: objects.NetboxObject{
+					Tags: ds.Config.SourceTags,
+					CustomFields: map[string]interface{}{
+						constants.CustomFieldSourceName: ds.SourceConfig.Name,
+					},
+				},
+				Name:   accessPointEthernetName,
+				Device: nbDevice,
+				Type:   &objects.GE1FixedInterfaceType,
+				MAC:    strings.ToUpper(apConfig.EthMac),
+				Status: true,
+			})
+			if err != nil {
+				return fmt.Errorf("add access point ethernet interface: %s", err)
+			}
+		}
+		if apConfig.RadioDTOs == nil {
+			continue
+		}
+		wirelessLANs := ds.siteWirelessLANs(ds.Device2Site[deviceID])
+		for _, radio := range *apConfig.RadioDTOs {
+			if radio.SlotID == nil {
+				continue
+			}
+			_, err := nbi.AddInterface(&objects.Interface{
+				NetboxObject: objects.NetboxObject{
+					Tags:        ds.Config.SourceTags,
+					Description: radio.IfTypeValue,
+					CustomFields: map[string]interface{}{
+						constants.CustomFieldSourceName: ds.SourceConfig.Name,
+					},
+				},
+				Name:         fmt.Sprintf("Radio%d", *radio.SlotID),
+				Device:       nbDevice,
+				Type:         radioInterfaceType(radio.IfTypeValue),
+				MAC:          strings.ToUpper(radio.MacAddress),
+				Status:       strings.EqualFold(radio.AdminStatus, "enabled"),
+				WirelessLANs: wirelessLANs,
+			})
+			if err != nil {
+				return fmt.Errorf("add access point radio interface: %s", err)
+			}
+		}
+	}
+	return nil
+}
+
+// isStack returns true if dnac device represents a stack of switches.
+func isStack(device dnac.ResponseDevicesGetDeviceListResponse) bool {
+	return strings.Contains(device.PlatformID, ",")
+}
+
+// stackMemberNumber extracts stack member number from the name of
+// interface or module of the stacked switch.
+func stackMemberNumber(name string) (int, bool) {
+	for _, regex := range []*regexp.Regexp{stackInterfaceRegex, stackModuleRegex} {
+		if matches := regex.FindStringSubmatch(name); len(matches) == 2 {
+			memberNumber, err := strconv.Atoi(matches[1])
+			if err == nil {
+				return memberNumber, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// stackMemberSourceID returns id of the stack member, that is stored in source_id custom field.
+// Members are identified by their serial number, because their member numbers can be renumbered.
+func stackMemberSourceID(device dnac.ResponseDevicesGetDeviceListResponse, member dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo) string {
+	if member.SerialNumber != "" {
+		return fmt.Sprintf("%s/%s", device.ID, member.SerialNumber)
+	}
+	return fmt.Sprintf("%s/%d", device.ID, *member.StackMemberNumber)
+}
+
+// memberDevice returns stack member, to which the interface or module belongs.
+// For devices that are not stacks, the device itself is returned.
+func (ds *Source) memberDevice(deviceID string, name string) *objects.Device {
+	if members, ok := ds.DeviceID2nbStackMembers[deviceID]; ok {
+		if memberNumber, ok := stackMemberNumber(name); ok {
+			if member, ok := members[memberNumber]; ok {
+				return member
+			}
+		}
+	}
+	return ds.DeviceID2nbDevice[deviceID]
+}
+
+// syncStackMembers syncs each stack member as a device in the virtual chassis.
+// The member with the lowest member number keeps the hostname of the stack,
+// so shared interfaces (e.g. vlan interfaces) and the primary ip are assigned to it.
+// Other members are named <hostname>-<member number>.
+//
+// Returns the device of the first member.
+func (ds *Source) syncStackMembers(nbi *inventory.NetboxInventory, device dnac.ResponseDevicesGetDeviceListResponse, stack dnac.ResponseDevicesGetStackDetailsForDeviceResponse, newDevice *objects.Device) (*objects.Device, error) {
+	newVirtualChassis := func(master *objects.Device) *objects.VirtualChassis {
+		return &objects.VirtualChassis{
+			NetboxObject: objects.NetboxObject{
+				Tags: ds.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: ds.SourceConfig.Name,
+				},
+			},
+			Name:   device.Hostname,
+			Master: master,
+		}
+	}
+	nbVirtualChassis, err := nbi.AddVirtualChassis(newVirtualChassis(nil))
+	if err != nil {
+		return nil, fmt.Errorf("add virtual chassis: %s", err)
+	}
+
+	members := make([]dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo, 0, len(*stack.StackSwitchInfo))
+	for _, member := range *stack.StackSwitchInfo {
+		if member.StackMemberNumber != nil {
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("stack %s has no numbered members", device.Hostname)
+	}
+	slices.SortFunc(members, func(a, b dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo) int {
+		return *a.StackMemberNumber - *b.StackMemberNumber
+	})
+
+	ds.DeviceID2nbStackMembers[device.ID] = make(map[int]*objects.Device, len(members))
+	var firstMember, master *objects.Device
+	for i, member := range members {
+		memberDevice := *newDevice
+		memberDevice.CustomFields = maps.Clone(newDevice.CustomFields)
+		memberDevice.CustomFields[constants.CustomFieldSourceIDName] = stackMemberSourceID(device, member)
+		memberDevice.SerialNumber = member.SerialNumber
+		memberDevice.VirtualChassis = nbVirtualChassis
+		memberDevice.VCPosition = *member.StackMemberNumber
+		if member.SwitchPriority != nil {
+			memberDevice.VCPriority = *member.SwitchPriority
+		}
+		if i > 0 {
+			memberDevice.Name = fmt.Sprintf("%s-%d", device.Hostname, *member.StackMemberNumber)
+		}
+		nbMember, err := nbi.AddDevice(&memberDevice)
+		if err != nil {
+			return nil, fmt.Errorf("add stack member: %s", err)
+		}
+		ds.DeviceID2nbStackMembers[device.ID][*member.StackMemberNumber] = nbMember
+		if firstMember == nil {
+			firstMember = nbMember
+		}
+		if strings.EqualFold(member.Role, "active") {
+			master = nbMember
+		}
+	}
+	if master == nil {
+		master = firstMember
+	}
+
+	if _, err = nbi.AddVirtualChassis(newVirtualChassis(master)); err != nil {
+		return nil, fmt.Errorf("set virtual chassis master: %s", err)
+	}
+	return firstMember, nil
+}
+
+// SyncInventoryItems syncs modules of devices (line cards, transceivers...) as inventory items.
+// Modules of stacked switches are assigned to the stack member, in which they are installed.
+func (ds *Source) SyncInventoryItems(nbi *inventory.NetboxInventory) error {
+	for deviceID, modules := range ds.Modules {
+		if _, ok := ds.DeviceID2nbDevice[deviceID]; !ok {
+			continue
+		}
+		for _, module := range modules {
+			if module.Name == "" {
+				continue
+			}
+			var manufacturer *objects.Manufacturer
+			if module.Manufacturer != "" {
+				var err error
+				manufacturer, err = nbi.AddManufacturer(&objects.Manufacturer{
+					Name: module.Manufacturer,
+					Slug: utils.Slugify(module.Manufacturer),
+				})
+				if err != nil {
+					return fmt.Errorf("add module manufacturer: %s", err)
+				}
+			}
+			description := module.Description
+			if len(description) > objects.MaxDescriptionLength {
+				description = description[:objects.MaxDescriptionLength]
+			}
+			_, err := nbi.AddInventoryItem(&objects.InventoryItem{
+				NetboxObject: objects.NetboxObject{
+					Tags:        ds.Config.SourceTags,
+					Description: description,
+					CustomFields: map[string]interface{}{
+						constants.CustomFieldSourceName: ds.SourceConfig.Name,
+					},
+				},
+				Device:       ds.memberDevice(deviceID, module.Name),
+				Name:         module.Name,
+				Manufacturer: manufacturer,
+				PartID:       module.PartNumber,
+				SerialNumber: module.SerialNumber,
+				Discovered:   true,
+			})
+			if err != nil {
+				return fmt.Errorf("add inventory item: %s", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+package dnac
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory/inventorytest"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+
+	dnac "github.com/cisco-en-programmability/dnacenter-go-sdk/v5/sdk"
+)
+
+func TestVlanNameToVid(t *testing.T) {
+	tests := []struct {
+		name      string
+		vlanName  string
+		expected  int
+		expectErr bool
+	}{
+		{name: "Capitalized name", vlanName: "Vlan10", expected: 10},
+		{name: "Lowercase name", vlanName: "vlan4094", expected: 4094},
+		{name: "Wrong format", vlanName: "Management", expectErr: true},
+		{name: "Missing vid", vlanName: "Vlan", expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vlanNameToVid(tt.vlanName)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("vlanNameToVid() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if got != tt.expected {
+				t.Errorf("vlanNameToVid() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSiteType(t *testing.T) {
+	tests := []struct {
+		name     string
+		site     dnac.ResponseSitesGetSiteResponse
+		expected string
+	}{
+		{name: "Global site", site: dnac.ResponseSitesGetSiteResponse{Name: "Global"}, expected: ""},
+		{
+			name: "Building",
+			site: dnac.ResponseSitesGetSiteResponse{
+				Name: "HQ",
+				AdditionalInfo: []dnac.ResponseSitesGetSiteResponseAdditionalInfo{
+					{Namespace: "ETA"},
+					{Namespace: "Location", Attributes: dnac.ResponseSitesGetSiteResponseAdditionalInfoAttributes{Type: "building"}},
+				},
+			},
+			expected: siteTypeBuilding,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := siteType(tt.site); got != tt.expected {
+				t.Errorf("siteType() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSSIDAuthType(t *testing.T) {
+	tests := []struct {
+		securityLevel string
+		expected      *objects.WirelessLANAuthType
+	}{
+		{securityLevel: "wpa2_enterprise", expected: &objects.WirelessLANAuthTypeWPAEnterprise},
+		{securityLevel: "wpa2_personal", expected: &objects.WirelessLANAuthTypeWPAPersonal},
+		{securityLevel: "open", expected: &objects.WirelessLANAuthTypeOpen},
+		{securityLevel: "unknown", expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.securityLevel, func(t *testing.T) {
+			if got := ssidAuthType(tt.securityLevel); got != tt.expected {
+				t.Errorf("ssidAuthType() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRadioInterfaceType(t *testing.T) {
+	tests := []struct {
+		radioType string
+		expected  *objects.InterfaceType
+	}{
+		{radioType: "802.11ax - 5 GHz", expected: &objects.IEEE80211AXInterfaceType},
+		{radioType: "802.11ac", expected: &objects.IEEE80211ACInterfaceType},
+		{radioType: "5 GHz", expected: &objects.IEEE80211AInterfaceType},
+		{radioType: "2.4 GHz", expected: &objects.IEEE80211GInterfaceType},
+		{radioType: "BLE", expected: &objects.OtherInterfaceType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.radioType, func(t *testing.T) {
+			if got := radioInterfaceType(tt.radioType); got != tt.expected {
+				t.Errorf("radioInterfaceType() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStackMemberNumber(t *testing.T) {
+	tests := []struct {
+		name         string
+		expected     int
+		expectedOkay bool
+	}{
+		{name: "GigabitEthernet2/0/1", expected: 2, expectedOkay: true},
+		{name: "TenGigabitEthernet1/1/4", expected: 1, expectedOkay: true},
+		{name: "Switch 3 - C9300-48P - Power Supply A", expected: 3, expectedOkay: true},
+		{name: "GigabitEthernet0/1", expected: 0, expectedOkay: false},
+		{name: "Vlan10", expected: 0, expectedOkay: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := stackMemberNumber(tt.name)
+			if got != tt.expected || ok != tt.expectedOkay {
+				t.Errorf("stackMemberNumber() = (%d, %t), want (%d, %t)", got, ok, tt.expected, tt.expectedOkay)
+			}
+		})
+	}
+}
+
+func TestSyncStackMembersSourceID(t *testing.T) {
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := &Source{Config: common.Config{Logger: testLogger, SourceConfig: &parser.SourceConfig{Name: "dnac"}}}
+	nbi, _ := inventorytest.NewInventory(t)
+	site, err := nbi.AddSite(&objects.Site{Name: "MySite", Slug: "mysite"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	device := dnac.ResponseDevicesGetDeviceListResponse{ID: "device-uuid", Hostname: "stack"}
+	syncStack := func(members map[int]string) map[string]*objects.Device {
+		stack := dnac.ResponseDevicesGetStackDetailsForDeviceResponse{StackSwitchInfo: &[]dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo{}}
+		for number, serial := range members {
+			number := number
+			*stack.StackSwitchInfo = append(*stack.StackSwitchInfo, dnac.ResponseDevicesGetStackDetailsForDeviceResponseStackSwitchInfo{
+				StackMemberNumber: &number,
+				SerialNumber:      serial,
+			})
+		}
+		ds.DeviceID2nbStackMembers = make(map[string]map[int]*objects.Device)
+		newDevice := &objects.Device{
+			NetboxObject: objects.NetboxObject{
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   "dnac",
+					constants.CustomFieldSourceIDName: device.ID,
+				},
+			},
+			Name: device.Hostname,
+			Site: site,
+		}
+		if _, err := ds.syncStackMembers(nbi, device, stack, newDevice); err != nil {
+			t.Fatal(err)
+		}
+		serial2Member := make(map[string]*objects.Device)
+		for _, member := range ds.DeviceID2nbStackMembers[device.ID] {
+			serial2Member[member.SerialNumber] = member
+		}
+		return serial2Member
+	}
+
+	members := syncStack(map[int]string{1: "SERIAL-A", 2: "SERIAL-B"})
+	// Stack was renumbered, so each switch has to keep its netbox device
+	renumberedMembers := syncStack(map[int]string{1: "SERIAL-B", 2: "SERIAL-A"})
+	for serial, member := range members {
+		renumberedMember := renumberedMembers[serial]
+		if renumberedMember == nil || renumberedMember.ID != member.ID {
+			t.Errorf("switch %s was synced to device %v, want device %d", serial, renumberedMember, member.ID)
+			continue
+		}
+		expectedSourceID := "device-uuid/" + serial
+		if sourceID := renumberedMember.CustomFields[constants.CustomFieldSourceIDName]; sourceID != expectedSourceID {
+			t.Errorf("source_id of switch %s = %v, want %s", serial, sourceID, expectedSourceID)
+		}
+	}
+}
+
+func TestSyncRegionsWithSameName(t *testing.T) {
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	area := func(name string, parentID string) dnac.ResponseSitesGetSiteResponse {
+		return dnac.ResponseSitesGetSiteResponse{
+			Name:     name,
+			ParentID: parentID,
+			AdditionalInfo: []dnac.ResponseSitesGetSiteResponseAdditionalInfo{
+				{Namespace: "Location", Attributes: dnac.ResponseSitesGetSiteResponseAdditionalInfoAttributes{Type: siteTypeArea}},
+			},
+		}
+	}
+	ds := &Source{
+		Config: common.Config{Logger: testLogger, SourceConfig: &parser.SourceConfig{Name: "dnac"}},
+		Sites: map[string]dnac.ResponseSitesGetSiteResponse{
+			"global":        {Name: "Global"},
+			"europe":        area("Europe", "global"),
+			"us":            area("US", "global"),
+			"europe-office": area("Office", "europe"),
+			"us-office":     area("Office", "us"),
+		},
+	}
+	nbi, fake := inventorytest.NewInventory(t)
+	// Second sync must match both regions named Office to their existing regions
+	for i := 0; i < 2; i++ {
+		ds.SiteID2nbRegion = make(map[string]*objects.Region)
+		ds.SiteID2nbSite = make(map[string]*objects.Site)
+		ds.SiteID2nbLocation = make(map[string]*objects.Location)
+		if err := ds.SyncSites(nbi); err != nil {
+			t.Fatal(err)
+		}
+	}
+	europe, us := ds.SiteID2nbRegion["europe"], ds.SiteID2nbRegion["us"]
+	europeOffice, usOffice := ds.SiteID2nbRegion["europe-office"], ds.SiteID2nbRegion["us-office"]
+	if europeOffice == nil || europeOffice.Parent == nil || europeOffice.Parent.ID != europe.ID {
+		t.Errorf("Europe's Office region = %+v, want region under Europe", europeOffice)
+	}
+	if usOffice == nil || usOffice.Parent == nil || usOffice.Parent.ID != us.ID || usOffice.ID == europeOffice.ID {
+		t.Errorf("US's Office region = %+v, want another region under US", usOffice)
+	}
+	for _, request := range fake.Requests {
+		if request.Method == http.MethodPatch {
+			t.Errorf("region was patched on the second sync: %s %v", request.Path, request.Body)
+		}
+	}
+}
+
+func TestSyncDeviceInterfacesPortMode(t *testing.T) {
+	tests := []struct {
+		name              string
+		portMode          string
+		vlanID            string
+		nativeVlanID      string
+		carriedVids       []int
+		expectedMode      objects.InterfaceMode
+		expectedUntagged  int
+		expectedTaggedIDs []int
+	}{
+		{
+			name:             "Access",
+			portMode:         "access",
+			vlanID:           "10",
+			carriedVids:      []int{10},
+			expectedMode:     objects.InterfaceModeAccess,
+			expectedUntagged: 10,
+		},
+		{
+			name:             "Dynamic port carrying single vlan is access",
+			portMode:         "dynamic_auto",
+			vlanID:           "20",
+			carriedVids:      []int{20},
+			expectedMode:     objects.InterfaceModeAccess,
+			expectedUntagged: 20,
+		},
+		{
+			name:              "Dynamic port carrying multiple vlans is trunk",
+			portMode:          "dynamic_desirable",
+			vlanID:            "10",
+			nativeVlanID:      "10",
+			carriedVids:       []int{10, 20, 30},
+			expectedMode:      objects.InterfaceModeTagged,
+			expectedUntagged:  10,
+			expectedTaggedIDs: []int{20, 30},
+		},
+		{
+			name:              "Native vlan is untagged and excluded from tagged",
+			portMode:          "trunk",
+			nativeVlanID:      "20",
+			carriedVids:       []int{10, 20, 30},
+			expectedMode:      objects.InterfaceModeTagged,
+			expectedUntagged:  20,
+			expectedTaggedIDs: []int{10, 30},
+		},
+		{
+			name:              "Trunk without native vlan",
+			portMode:          "trunk",
+			carriedVids:       []int{10, 30},
+			expectedMode:      objects.InterfaceModeTagged,
+			expectedTaggedIDs: []int{10, 30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testLogger, err := logger.New("", logger.ERROR, "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			nbi, _ := inventorytest.NewInventory(t)
+			site, err := nbi.AddSite(&objects.Site{Name: "MySite", Slug: "mysite"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			manufacturer, err := nbi.AddManufacturer(&objects.Manufacturer{Name: "Cisco", Slug: "cisco"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			deviceType, err := nbi.AddDeviceType(&objects.DeviceType{Manufacturer: manufacturer, Model: "C9300-48P", Slug: "c9300-48p"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			device, err := nbi.AddDevice(&objects.Device{Name: "switch", Site: site, DeviceType: deviceType})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ds := &Source{
+				Config:  common.Config{Logger: testLogger, SourceConfig: &parser.SourceConfig{Name: "dnac"}},
+				Devices: map[string]dnac.ResponseDevicesGetDeviceListResponse{"device-uuid": {ID: "device-uuid"}},
+				Interfaces: map[string]dnac.ResponseDevicesGetAllInterfacesResponse{
+					"iface-uuid": {
+						DeviceID:      "device-uuid",
+						PortName:      "GigabitEthernet1/0/1",
+						InterfaceType: "Physical",
+						Status:        "up",
+						Speed:         "1000000",
+						PortMode:      tt.portMode,
+						VLANID:        tt.vlanID,
+						NativeVLANID:  tt.nativeVlanID,
+					},
+				},
+				InterfaceID2Vids:        map[string][]int{"iface-uuid": tt.carriedVids},
+				VID2nbVlan:              make(map[int]*objects.Vlan),
+				DeviceID2nbDevice:       map[string]*objects.Device{"device-uuid": device},
+				InterfaceID2nbInterface: make(map[string]*objects.Interface),
+			}
+			vid2VlanID := map[int]int{0: 0}
+			for _, vid := range []int{10, 20, 30} {
+				vlan, err := nbi.AddVlan(&objects.Vlan{Name: fmt.Sprintf("vlan%d", vid), Vid: vid})
+				if err != nil {
+					t.Fatal(err)
+				}
+				ds.VID2nbVlan[vid] = vlan
+				vid2VlanID[vid] = vlan.ID
+			}
+
+			if err := ds.SyncDeviceInterfaces(nbi); err != nil {
+				t.Fatal(err)
+			}
+			iface := ds.InterfaceID2nbInterface["iface-uuid"]
+			if iface == nil {
+				t.Fatal("interface was not synced")
+			}
+			if iface.Mode == nil || iface.Mode.Value != tt.expectedMode.Value {
+				t.Errorf("interface mode = %v, want %s", iface.Mode, tt.expectedMode.Value)
+			}
+			untaggedID := 0
+			if iface.UntaggedVlan != nil {
+				untaggedID = iface.UntaggedVlan.ID
+			}
+			if untaggedID != vid2VlanID[tt.expectedUntagged] {
+				t.Errorf("untagged vlan = %d, want vlan %d (id %d)", untaggedID, tt.expectedUntagged, vid2VlanID[tt.expectedUntagged])
+			}
+			taggedIDs := []int{}
+			for _, vlan := range iface.TaggedVlans {
+				taggedIDs = append(taggedIDs, vlan.ID)
+			}
+			expectedTaggedIDs := []int{}
+			for _, vid := range tt.expectedTaggedIDs {
+				expectedTaggedIDs = append(expectedTaggedIDs, vid2VlanID[vid])
+			}
+			if !slices.Equal(taggedIDs, expectedTaggedIDs) {
+				t.Errorf("tagged vlans = %v, want %v (vids %v)", taggedIDs, expectedTaggedIDs, tt.expectedTaggedIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package netbox
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// Source represents another netbox instance, whose objects are federated
+// into the target netbox. Api token of the source netbox is stored in password.
+type Source struct {
+	common.Config
+
+	// Source netbox fetched data. Initialized in init functions.
+	Sites        map[int]*objects.Site        // SiteID -> Site
+	DeviceRoles  map[int]*objects.DeviceRole  // DeviceRoleID -> DeviceRole
+	Clusters     map[int]*objects.Cluster     // ClusterID -> Cluster
+	Devices      map[int]*objects.Device      // DeviceID -> Device
+	Interfaces   map[int]*objects.Interface   // InterfaceID -> Interface
+	Vms          map[int]*objects.VM          // VMID -> VM
+	VMInterfaces map[int]*objects.VMInterface // VMInterfaceID -> VMInterface
+	Vlans        map[int]*objects.Vlan        // VlanID -> Vlan
+	Prefixes     map[int]*objects.Prefix      // PrefixID -> Prefix
+	IPAddresses  map[int]*objects.IPAddress   // IPAddressID -> IPAddress
+
+	// Mappings of source netbox ids to objects in the target netbox. Initialized in sync functions.
+	SiteID2nbSite               map[int]*objects.Site
+	ClusterID2nbCluster         map[int]*objects.Cluster
+	DeviceID2nbDevice           map[int]*objects.Device
+	InterfaceID2nbInterface     map[int]*objects.Interface
+	VMID2nbVM                   map[int]*objects.VM
+	VMInterfaceID2nbVMInterface map[int]*objects.VMInterface
+	VlanID2nbVlan               map[int]*objects.Vlan
+	IPAddressID2nbIPAddress     map[int]*objects.IPAddress
+}
+
+// Function that collects all data from the source netbox.
+func (ns *Source) Init() error {
+	baseURL := fmt.Sprintf("%s://%s:%d", ns.SourceConfig.HTTPScheme, ns.SourceConfig.Hostname, ns.SourceConfig.Port)
+	api := service.NewNetBoxAPI(ns.Logger, baseURL, ns.SourceConfig.Password, ns.SourceConfig.ValidateCert, constants.DefaultTimeout)
+
+	ns.SubnetVRFRelations = utils.ConvertStringsToPairs(ns.SourceConfig.SubnetVRFRelations)
+	ns.Logger.Debug("SubnetVRFRelations: ", ns.SubnetVRFRelations)
+
+	initFunctions := []func(*service.NetboxAPI) error{
+		ns.InitSites,
+		ns.InitDeviceRoles,
+		ns.InitClusters,
+		ns.InitDevices,
+		ns.InitInterfaces,
+		ns.InitVMs,
+		ns.InitVMInterfaces,
+		ns.InitVlans,
+		ns.InitPrefixes,
+		ns.InitIPAddresses,
+	}
+	for _, initFunc := range initFunctions {
+		startTime := time.Now()
+		if err := initFunc(api); err != nil {
+			return fmt.Errorf("netbox initialization failure: %v", err)
+		}
+		duration := time.Since(startTime)
+		ns.Logger.Infof("Successfully initialized %s in %f seconds", utils.ExtractFunctionName(initFunc), duration.Seconds())
+	}
+	return nil
+}
+
+// Function that syncs all data from the source netbox to the target netbox.
+func (ns *Source) Sync(nbi *inventory.NetboxInventory) error {
+	// initialize variables, that are shared between sync functions
+	ns.SiteID2nbSite = make(map[int]*objects.Site)
+	ns.ClusterID2nbCluster = make(map[int]*objects.Cluster)
+	ns.DeviceID2nbDevice = make(map[int]*objects.Device)
+	ns.InterfaceID2nbInterface = make(map[int]*objects.Interface)
+	ns.VMID2nbVM = make(map[int]*objects.VM)
+	ns.VMInterfaceID2nbVMInterface = make(map[int]*objects.VMInterface)
+	ns.VlanID2nbVlan = make(map[int]*objects.Vlan)
+	ns.IPAddressID2nbIPAddress = make(map[int]*objects.IPAddress)
+
+	// Order matters, because objects reference each other
+	syncFunctions := []func(*inventory.NetboxInventory) error{
+		ns.syncSites,
+		ns.syncVlans,
+		ns.syncPrefixes,
+		ns.syncClusters,
+		ns.syncDevices,
+		ns.syncInterfaces,
+		ns.syncVMs,
+		ns.syncVMInterfaces,
+		ns.syncIPAddresses,
+		ns.syncPrimaryIPs,
+	}
+	for _, syncFunc := range syncFunctions {
+		startTime := time.Now()
+		err := syncFunc(nbi)
+		if err != nil {
+			return err
+		}
+		duration := time.Since(startTime)
+		ns.Logger.Infof("Successfully synced %s in %f seconds", utils.ExtractFunctionName(syncFunc), duration.Seconds())
+	}
+	return nil
+}
+
+// filterParams returns extra query params for service.GetAll, that filter
+// objects by configured tags, sites and tenants.
+func (ns *Source) filterParams(byTag, bySite, byTenant bool) string {
+	params := url.Values{}
+	if byTag {
+		for _, tag := range ns.SourceConfig.FilterTags {
+			params.Add("tag", tag)
+		}
+	}
+	if bySite {
+		for _, site := range ns.SourceConfig.FilterSites {
+			params.Add("site", site)
+		}
+	}
+	if byTenant {
+		for _, tenant := range ns.SourceConfig.FilterTenants {
+			params.Add("tenant", tenant)
+		}
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "&" + params.Encode()
+}
@@ -0,0 +1,143 @@
+package netbox
+
+import (
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+)
+
+// indexByID returns map of items indexed by their netbox id.
+func indexByID[T any](items []T, id func(*T) int) map[int]*T {
+	index := make(map[int]*T, len(items))
+	for i := range items {
+		index[id(&items[i])] = &items[i]
+	}
+	return index
+}
+
+// InitSites collects all sites. Only sites that are referenced by
+// other synced objects are later synced.
+func (ns *Source) InitSites(api *service.NetboxAPI) error {
+	sites, err := service.GetAll[objects.Site](api, "")
+	if err != nil {
+		return err
+	}
+	ns.Sites = indexByID(sites, func(s *objects.Site) int { return s.ID })
+	return nil
+}
+
+func (ns *Source) InitDeviceRoles(api *service.NetboxAPI) error {
+	deviceRoles, err := service.GetAll[objects.DeviceRole](api, "")
+	if err != nil {
+		return err
+	}
+	ns.DeviceRoles = indexByID(deviceRoles, func(dr *objects.DeviceRole) int { return dr.ID })
+	return nil
+}
+
+// InitClusters collects all clusters. Only clusters that are referenced by
+// synced vms are later synced.
+func (ns *Source) InitClusters(api *service.NetboxAPI) error {
+	clusters, err := service.GetAll[objects.Cluster](api, "")
+	if err != nil {
+		return err
+	}
+	ns.Clusters = indexByID(clusters, func(c *objects.Cluster) int { return c.ID })
+	return nil
+}
+
+func (ns *Source) InitDevices(api *service.NetboxAPI) error {
+	devices, err := service.GetAll[objects.Device](api, ns.filterParams(true, true, true))
+	if err != nil {
+		return err
+	}
+	ns.Devices = indexByID(devices, func(d *objects.Device) int { return d.ID })
+	return nil
+}
+
+// InitInterfaces collects interfaces of the collected devices.
+func (ns *Source) InitInterfaces(api *service.NetboxAPI) error {
+	interfaces, err := service.GetAll[objects.Interface](api, ns.filterParams(false, true, false))
+	if err != nil {
+		return err
+	}
+	ns.Interfaces = make(map[int]*objects.Interface, len(interfaces))
+	for i := range interfaces {
+		iface := &interfaces[i]
+		if iface.Device == nil || ns.Devices[iface.Device.ID] == nil {
+			continue
+		}
+		ns.Interfaces[iface.ID] = iface
+	}
+	return nil
+}
+
+func (ns *Source) InitVMs(api *service.NetboxAPI) error {
+	vms, err := service.GetAll[objects.VM](api, ns.filterParams(true, true, true))
+	if err != nil {
+		return err
+	}
+	ns.Vms = indexByID(vms, func(vm *objects.VM) int { return vm.ID })
+	return nil
+}
+
+// InitVMInterfaces collects interfaces of the collected vms.
+func (ns *Source) InitVMInterfaces(api *service.NetboxAPI) error {
+	vmInterfaces, err := service.GetAll[objects.VMInterface](api, "")
+	if err != nil {
+		return err
+	}
+	ns.VMInterfaces = make(map[int]*objects.VMInterface, len(vmInterfaces))
+	for i := range vmInterfaces {
+		vmIface := &vmInterfaces[i]
+		if vmIface.VM == nil || ns.Vms[vmIface.VM.ID] == nil {
+			continue
+		}
+		ns.VMInterfaces[vmIface.ID] = vmIface
+	}
+	return nil
+}
+
+func (ns *Source) InitVlans(api *service.NetboxAPI) error {
+	vlans, err := service.GetAll[objects.Vlan](api, ns.filterParams(true, true, true))
+	if err != nil {
+		return err
+	}
+	ns.Vlans = indexByID(vlans, func(v *objects.Vlan) int { return v.ID })
+	return nil
+}
+
+func (ns *Source) InitPrefixes(api *service.NetboxAPI) error {
+	prefixes, err := service.GetAll[objects.Prefix](api, ns.filterParams(true, true, true))
+	if err != nil {
+		return err
+	}
+	ns.Prefixes = indexByID(prefixes, func(p *objects.Prefix) int { return p.ID })
+	return nil
+}
+
+// InitIPAddresses collects ip addresses, that are assigned to the collected
+// interfaces or vm interfaces.
+func (ns *Source) InitIPAddresses(api *service.NetboxAPI) error {
+	ipAddresses, err := service.GetAll[objects.IPAddress](api, "")
+	if err != nil {
+		return err
+	}
+	ns.IPAddresses = make(map[int]*objects.IPAddress, len(ipAddresses))
+	for i := range ipAddresses {
+		ipAddress := &ipAddresses[i]
+		switch ipAddress.AssignedObjectType {
+		case objects.AssignedObjectTypeDeviceInterface:
+			if ns.Interfaces[ipAddress.AssignedObjectID] == nil {
+				continue
+			}
+		case objects.AssignedObjectTypeVMInterface:
+			if ns.VMInterfaces[ipAddress.AssignedObjectID] == nil {
+				continue
+			}
+		default:
+			continue
+		}
+		ns.IPAddresses[ipAddress.ID] = ipAddress
+	}
+	return nil
+}
@@ -0,0 +1,583 @@
+package netbox
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+)
+
+// netboxObject returns netbox object with source tags and custom fields,
+// which reference the object's id in the source netbox.
+func (ns *Source) netboxObject(remote objects.NetboxObject) objects.NetboxObject {
+	return objects.NetboxObject{
+		Tags:        ns.Config.SourceTags,
+		Description: remote.Description,
+		CustomFields: map[string]interface{}{
+			constants.CustomFieldSourceName:   ns.SourceConfig.Name,
+			constants.CustomFieldSourceIDName: strconv.Itoa(remote.ID),
+		},
+	}
+}
+
+// matchTenant returns tenant from the target netbox with the same name
+// as the tenant from the source netbox.
+func matchTenant(nbi *inventory.NetboxInventory, tenant *objects.Tenant) *objects.Tenant {
+	if tenant == nil {
+		return nil
+	}
+	return nbi.TenantsIndexByName[tenant.Name]
+}
+
+// matchVRF returns vrf of the ip address (or prefix) in the target netbox. Vrf relations
+// from the source config have precedence, otherwise vrf with the same name as the vrf
+// from the source netbox is used (and created if it doesn't exist yet).
+func (ns *Source) matchVRF(nbi *inventory.NetboxInventory, address string, vrf *objects.VRF) (*objects.VRF, error) {
+	nbVRF, err := ns.MatchIPToVRF(nbi, address, nil)
+	if err != nil || nbVRF != nil || vrf == nil {
+		return nbVRF, err
+	}
+	if nbVRF, ok := nbi.VRFsIndexByName[vrf.Name]; ok && !nbi.OrphanManager[service.VRFsAPIPath][nbVRF.ID] {
+		return nbVRF, nil
+	}
+	return nbi.AddVRF(&objects.VRF{
+		NetboxObject: ns.netboxObject(vrf.NetboxObject),
+		Name:         vrf.Name,
+		RD:           vrf.RD,
+	})
+}
+
+// syncSites syncs only sites, that are referenced by other synced objects.
+func (ns *Source) syncSites(nbi *inventory.NetboxInventory) error {
+	referencedSites := make(map[int]bool)
+	for _, device := range ns.Devices {
+		if device.Site != nil {
+			referencedSites[device.Site.ID] = true
+		}
+	}
+	for _, vm := range ns.Vms {
+		if vm.Site != nil {
+			referencedSites[vm.Site.ID] = true
+		}
+		if vm.Cluster != nil {
+			if cluster, ok := ns.Clusters[vm.Cluster.ID]; ok && cluster.Site != nil {
+				referencedSites[cluster.Site.ID] = true
+			}
+		}
+	}
+	for _, vlan := range ns.Vlans {
+		if vlan.Site != nil {
+			referencedSites[vlan.Site.ID] = true
+		}
+	}
+	for _, prefix := range ns.Prefixes {
+		if prefix.Site != nil {
+			referencedSites[prefix.Site.ID] = true
+		}
+	}
+
+	for siteID := range referencedSites {
+		site, ok := ns.Sites[siteID]
+		if !ok {
+			ns.Logger.Warningf("site with id %d is not present in the source netbox, so it will be skipped", siteID)
+			continue
+		}
+		nbSite, err := nbi.AddSite(&objects.Site{
+			NetboxObject:    ns.netboxObject(site.NetboxObject),
+			Name:            site.Name,
+			Slug:            site.Slug,
+			Status:          site.Status,
+			Tenant:          matchTenant(nbi, site.Tenant),
+			PhysicalAddress: site.PhysicalAddress,
+			Latitude:        site.Latitude,
+			Longitude:       site.Longitude,
+		})
+		if err != nil {
+			return fmt.Errorf("adding netbox site %s: %s", site.Name, err)
+		}
+		ns.SiteID2nbSite[siteID] = nbSite
+	}
+	return nil
+}
+
+// mapSite returns site from the target netbox, that matches site from the source netbox.
+func (ns *Source) mapSite(site *objects.Site) *objects.Site {
+	if site == nil {
+		return nil
+	}
+	return ns.SiteID2nbSite[site.ID]
+}
+
+func (ns *Source) syncVlans(nbi *inventory.NetboxInventory) error {
+	for vlanID, vlan := range ns.Vlans {
+		vlanGroup := nbi.VlanGroupsIndexByName[objects.DefaultVlanGroupName]
+		if vlan.Group != nil {
+			var err error
+			vlanGroup, err = nbi.AddVlanGroup(&objects.VlanGroup{
+				NetboxObject: objects.NetboxObject{
+					Tags: ns.Config.SourceTags,
+				},
+				Name:   vlan.Group.Name,
+				Slug:   vlan.Group.Slug,
+				MinVid: 1,
+				MaxVid: objects.MaxVID,
+			})
+			if err != nil {
+				return fmt.Errorf("adding netbox vlan group %s: %s", vlan.Group.Name, err)
+			}
+		}
+		nbVlan, err := nbi.AddVlan(&objects.Vlan{
+			NetboxObject: ns.netboxObject(vlan.NetboxObject),
+			Name:         vlan.Name,
+			Vid:          vlan.Vid,
+			Group:        vlanGroup,
+			Status:       vlan.Status,
+			Tenant:       matchTenant(nbi, vlan.Tenant),
+			Site:         ns.mapSite(vlan.Site),
+			Comments:     vlan.Comments,
+		})
+		if err != nil {
+			return fmt.Errorf("adding netbox vlan %s: %s", vlan.Name, err)
+		}
+		ns.VlanID2nbVlan[vlanID] = nbVlan
+	}
+	return nil
+}
+
+// mapVlan returns vlan from the target netbox, that matches vlan from the source netbox.
+func (ns *Source) mapVlan(vlan *objects.Vlan) *objects.Vlan {
+	if vlan == nil {
+		return nil
+	}
+	return ns.VlanID2nbVlan[vlan.ID]
+}
+
+// mapVlans returns vlans from the target netbox, that match vlans from the source netbox.
+// Vlans, that were not synced, are omitted.
+func (ns *Source) mapVlans(vlans []*objects.Vlan) []*objects.Vlan {
+	var nbVlans []*objects.Vlan
+	for _, vlan := range vlans {
+		if nbVlan := ns.mapVlan(vlan); nbVlan != nil {
+			nbVlans = append(nbVlans, nbVlan)
+		}
+	}
+	return nbVlans
+}
+
+func (ns *Source) syncPrefixes(nbi *inventory.NetboxInventory) error {
+	for _, prefix := range ns.Prefixes {
+		vrf, err := ns.matchVRF(nbi, prefix.Prefix, prefix.VRF)
+		if err != nil {
+			return fmt.Errorf("matching netbox prefix %s to vrf: %s", prefix.Prefix, err)
+		}
+		_, err = nbi.AddPrefix(&objects.Prefix{
+			NetboxObject: ns.netboxObject(prefix.NetboxObject),
+			Prefix:       prefix.Prefix,
+			VRF:          vrf,
+			Status:       prefix.Status,
+			Site:         ns.mapSite(prefix.Site),
+			Vlan:         ns.mapVlan(prefix.Vlan),
+			Tenant:       matchTenant(nbi, prefix.Tenant),
+			Comments:     prefix.Comments,
+		})
+		if err != nil {
+			return fmt.Errorf("adding netbox prefix %s: %s", prefix.Prefix, err)
+		}
+	}
+	return nil
+}
+
+// syncClusters syncs only clusters, that are referenced by synced vms.
+func (ns *Source) syncClusters(nbi *inventory.NetboxInventory) error {
+	referencedClusters := make(map[int]bool)
+	for _, vm := range ns.Vms {
+		if vm.Cluster != nil {
+			referencedClusters[vm.Cluster.ID] = true
+		}
+	}
+
+	for clusterID := range referencedClusters {
+		cluster, ok := ns.Clusters[clusterID]
+		if !ok {
+			ns.Logger.Warningf("cluster with id %d is not present in the source netbox, so it will be skipped", clusterID)
+			continue
+		}
+		var clusterType *objects.ClusterType
+		if cluster.Type != nil {
+			var err error
+			clusterType, err = nbi.AddClusterType(&objects.ClusterType{
+				NetboxObject: objects.NetboxObject{
+					Tags: ns.Config.SourceTags,
+				},
+				Name: cluster.Type.Name,
+				Slug: cluster.Type.Slug,
+			})
+			if err != nil {
+				return fmt.Errorf("adding netbox cluster type %s: %s", cluster.Type.Name, err)
+			}
+		}
+		var clusterGroup *objects.ClusterGroup
+		if cluster.Group != nil {
+			var err error
+			clusterGroup, err = nbi.AddClusterGroup(&objects.ClusterGroup{
+				NetboxObject: objects.NetboxObject{
+					Tags: ns.Config.SourceTags,
+				},
+				Name: cluster.Group.Name,
+				Slug: cluster.Group.Slug,
+			})
+			if err != nil {
+				return fmt.Errorf("adding netbox cluster group %s: %s", cluster.Group.Name, err)
+			}
+		}
+		err := nbi.AddCluster(&objects.Cluster{
+			NetboxObject: ns.netboxObject(cluster.NetboxObject),
+			Name:         cluster.Name,
+			Type:         clusterType,
+			Group:        clusterGroup,
+			Site:         ns.mapSite(cluster.Site),
+			Status:       cluster.Status,
+			Tenant:       matchTenant(nbi, cluster.Tenant),
+		})
+		if err != nil {
+			return fmt.Errorf("adding netbox cluster %s: %s", cluster.Name, err)
+		}
+		ns.ClusterID2nbCluster[clusterID] = nbi.ClustersIndexByName[cluster.Name]
+	}
+	return nil
+}
+
+// addPlatform returns platform from the target netbox, that matches platform from the source netbox.
+func (ns *Source) addPlatform(nbi *inventory.NetboxInventory, platform *objects.Platform) (*objects.Platform, error) {
+	if platform == nil {
+		return nil, nil
+	}
+	nbPlatform, err := nbi.AddPlatform(&objects.Platform{
+		NetboxObject: objects.NetboxObject{
+			Tags: ns.Config.SourceTags,
+		},
+		Name: platform.Name,
+		Slug: platform.Slug,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("adding netbox platform %s: %s", platform.Name, err)
+	}
+	return nbPlatform, nil
+}
+
+func (ns *Source) syncDevices(nbi *inventory.NetboxInventory) error {
+	for deviceID, device := range ns.Devices {
+		deviceSite := ns.mapSite(device.Site)
+		if deviceSite == nil {
+			ns.Logger.Warningf("netbox device %s has no synced site, so it will be skipped", device.Name)
+			continue
+		}
+
+		var deviceRole *objects.DeviceRole
+		if device.DeviceRole != nil {
+			role := device.DeviceRole
+			if fullRole, ok := ns.DeviceRoles[role.ID]; ok {
+				role = fullRole
+			}
+			var err error
+			deviceRole, err = nbi.AddDeviceRole(&objects.DeviceRole{
+				NetboxObject: objects.NetboxObject{
+					Tags: ns.Config.SourceTags,
+				},
+				Name:   role.Name,
+				Slug:   role.Slug,
+				Color:  role.Color,
+				VMRole: role.VMRole,
+			})
+			if err != nil {
+				return fmt.Errorf("adding netbox device role %s: %s", role.Name, err)
+			}
+		}
+
+		var deviceType *objects.DeviceType
+		if device.DeviceType != nil && device.DeviceType.Manufacturer != nil {
+			manufacturer, err := nbi.AddManufacturer(&objects.Manufacturer{
+				Name: device.DeviceType.Manufacturer.Name,
+				Slug: device.DeviceType.Manufacturer.Slug,
+			})
+			if err != nil {
+				return fmt.Errorf("adding netbox manufacturer %s: %s", device.DeviceType.Manufacturer.Name, err)
+			}
+			deviceType, err = nbi.AddDeviceType(&objects.DeviceType{
+				Manufacturer: manufacturer,
+				Model:        device.DeviceType.Model,
+				Slug:         device.DeviceType.Slug,
+			})
+			if err != nil {
+				return fmt.Errorf("adding netbox device type %s: %s", device.DeviceType.Model, err)
+			}
+		}
+
+		platform, err := ns.addPlatform(nbi, device.Platform)
+		if err != nil {
+			return err
+		}
+
+		nbDevice, err := nbi.AddDevice(&objects.Device{
+			NetboxObject: ns.netboxObject(device.NetboxObject),
+			Name:         device.Name,
+			DeviceRole:   deviceRole,
+			DeviceType:   deviceType,
+			Airflow:      device.Airflow,
+			SerialNumber: device.SerialNumber,
+			AssetTag:     device.AssetTag,
+			Site:         deviceSite,
+			Status:       device.Status,
+			Platform:     platform,
+			Tenant:       matchTenant(nbi, device.Tenant),
+			Comments:     device.Comments,
+		})
+		if err != nil {
+			return fmt.Errorf("adding netbox device %s: %s", device.Name, err)
+		}
+		ns.DeviceID2nbDevice[deviceID] = nbDevice
+	}
+	return nil
+}
+
+// syncInterfaces syncs interfaces of synced devices. Interfaces that reference
+// lag or parent interface are synced after all other interfaces are synced.
+func (ns *Source) syncInterfaces(nbi *inventory.NetboxInventory) error {
+	dependent := make([]int, 0)
+	for interfaceID, iface := range ns.Interfaces {
+		if iface.LAG != nil || iface.ParentInterface != nil {
+			dependent = append(dependent, interfaceID)
+			continue
+		}
+		if err := ns.addInterface(nbi, interfaceID, iface); err != nil {
+			return err
+		}
+	}
+	// Parent interfaces can also depend on each other, so they are synced
+	// until all dependencies are resolved
+	for len(dependent) > 0 {
+		unresolved := make([]int, 0, len(dependent))
+		for _, interfaceID := range dependent {
+			iface := ns.Interfaces[interfaceID]
+			if !ns.interfaceResolved(iface.LAG) || !ns.interfaceResolved(iface.ParentInterface) {
+				unresolved = append(unresolved, interfaceID)
+				continue
+			}
+			if err := ns.addInterface(nbi, interfaceID, iface); err != nil {
+				return err
+			}
+		}
+		if len(unresolved) == len(dependent) {
+			// Referenced interfaces are not synced, so they are omitted
+			for _, interfaceID := range unresolved {
+				if err := ns.addInterface(nbi, interfaceID, ns.Interfaces[interfaceID]); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		dependent = unresolved
+	}
+	return nil
+}
+
+// interfaceResolved returns true if the referenced interface is already synced
+// or it won't be synced at all.
+func (ns *Source) interfaceResolved(iface *objects.Interface) bool {
+	if iface == nil {
+		return true
+	}
+	if _, ok := ns.InterfaceID2nbInterface[iface.ID]; ok {
+		return true
+	}
+	_, collected := ns.Interfaces[iface.ID]
+	return !collected
+}
+
+func (ns *Source) addInterface(nbi *inventory.NetboxInventory, interfaceID int, iface *objects.Interface) error {
+	nbDevice, ok := ns.DeviceID2nbDevice[iface.Device.ID]
+	if !ok {
+		return nil
+	}
+	newInterface := &objects.Interface{
+		NetboxObject: ns.netboxObject(iface.NetboxObject),
+		Device:       nbDevice,
+		Name:         iface.Name,
+		Status:       iface.Status,
+		Type:         iface.Type,
+		Speed:        iface.Speed,
+		MTU:          iface.MTU,
+		MAC:          iface.MAC,
+		Duplex:       iface.Duplex,
+		Mode:         iface.Mode,
+		TaggedVlans:  ns.mapVlans(iface.TaggedVlans),
+		UntaggedVlan: ns.mapVlan(iface.UntaggedVlan),
+	}
+	if iface.LAG != nil {
+		newInterface.LAG = ns.InterfaceID2nbInterface[iface.LAG.ID]
+	}
+	if iface.ParentInterface != nil {
+		newInterface.ParentInterface = ns.InterfaceID2nbInterface[iface.ParentInterface.ID]
+	}
+	nbInterface, err := nbi.AddInterface(newInterface)
+	if err != nil {
+		return fmt.Errorf("adding netbox interface %s: %s", iface.Name, err)
+	}
+	ns.InterfaceID2nbInterface[interfaceID] = nbInterface
+	return nil
+}
+
+func (ns *Source) syncVMs(nbi *inventory.NetboxInventory) error {
+	for vmID, vm := range ns.Vms {
+		var vmCluster *objects.Cluster
+		if vm.Cluster != nil {
+			vmCluster = ns.ClusterID2nbCluster[vm.Cluster.ID]
+		}
+		var vmHost *objects.Device
+		if vm.Host != nil {
+			vmHost = ns.DeviceID2nbDevice[vm.Host.ID]
+		}
+		platform, err := ns.addPlatform(nbi, vm.Platform)
+		if err != nil {
+			return err
+		}
+		nbVM, err := nbi.AddVM(&objects.VM{
+			NetboxObject: ns.netboxObject(vm.NetboxObject),
+			Name:         vm.Name,
+			Status:       vm.Status,
+			Site:         ns.mapSite(vm.Site),
+			Cluster:      vmCluster,
+			Host:         vmHost,
+			Tenant:       matchTenant(nbi, vm.Tenant),
+			Platform:     platform,
+			VCPUs:        vm.VCPUs,
+			Memory:       vm.Memory,
+			Disk:         vm.Disk,
+			Comments:     vm.Comments,
+		})
+		if err != nil {
+			return fmt.Errorf("adding netbox vm %s: %s", vm.Name, err)
+		}
+		ns.VMID2nbVM[vmID] = nbVM
+	}
+	return nil
+}
+
+func (ns *Source) syncVMInterfaces(nbi *inventory.NetboxInventory) error {
+	for vmInterfaceID, vmIface := range ns.VMInterfaces {
+		nbVM, ok := ns.VMID2nbVM[vmIface.VM.ID]
+		if !ok {
+			continue
+		}
+		nbVMInterface, err := nbi.AddVMInterface(&objects.VMInterface{
+			NetboxObject: ns.netboxObject(vmIface.NetboxObject),
+			VM:           nbVM,
+			Name:         vmIface.Name,
+			MACAddress:   vmIface.MACAddress,
+			MTU:          vmIface.MTU,
+			Enabled:      vmIface.Enabled,
+			Mode:         vmIface.Mode,
+			TaggedVlans:  ns.mapVlans(vmIface.TaggedVlans),
+			UntaggedVlan: ns.mapVlan(vmIface.UntaggedVlan),
+		})
+		if err != nil {
+			return fmt.Errorf("adding netbox vm interface %s: %s", vmIface.Name, err)
+		}
+		ns.VMInterfaceID2nbVMInterface[vmInterfaceID] = nbVMInterface
+	}
+	return nil
+}
+
+func (ns *Source) syncIPAddresses(nbi *inventory.NetboxInventory) error {
+	for ipAddressID, ipAddress := range ns.IPAddresses {
+		if !ns.IsPermittedIPAddress(ipAddress.Address) {
+			continue
+		}
+		var assignedObjectID int
+		switch ipAddress.AssignedObjectType {
+		case objects.AssignedObjectTypeDeviceInterface:
+			nbInterface, ok := ns.InterfaceID2nbInterface[ipAddress.AssignedObjectID]
+			if !ok {
+				continue
+			}
+			assignedObjectID = nbInterface.ID
+		case objects.AssignedObjectTypeVMInterface:
+			nbVMInterface, ok := ns.VMInterfaceID2nbVMInterface[ipAddress.AssignedObjectID]
+			if !ok {
+				continue
+			}
+			assignedObjectID = nbVMInterface.ID
+		default:
+			continue
+		}
+		vrf, err := ns.matchVRF(nbi, ipAddress.Address, ipAddress.VRF)
+		if err != nil {
+			return fmt.Errorf("matching netbox ip address %s to vrf: %s", ipAddress.Address, err)
+		}
+		nbIPAddress, err := nbi.AddIPAddress(&objects.IPAddress{
+			NetboxObject:       ns.netboxObject(ipAddress.NetboxObject),
+			Address:            ipAddress.Address,
+			VRF:                vrf,
+			Status:             ipAddress.Status,
+			Role:               ipAddress.Role,
+			DNSName:            ipAddress.DNSName,
+			Tenant:             matchTenant(nbi, ipAddress.Tenant),
+			AssignedObjectType: ipAddress.AssignedObjectType,
+			AssignedObjectID:   assignedObjectID,
+		})
+		if err != nil {
+			return fmt.Errorf("adding netbox ip address %s: %s", ipAddress.Address, err)
+		}
+		ns.IPAddressID2nbIPAddress[ipAddressID] = nbIPAddress
+	}
+	return nil
+}
+
+// mapIPAddress returns ip address from the target netbox, that matches ip address from the source netbox.
+func (ns *Source) mapIPAddress(ipAddress *objects.IPAddress) *objects.IPAddress {
+	if ipAddress == nil {
+		return nil
+	}
+	return ns.IPAddressID2nbIPAddress[ipAddress.ID]
+}
+
+// syncPrimaryIPs sets primary ips of synced devices and vms, once their
+// ip addresses are synced.
+func (ns *Source) syncPrimaryIPs(nbi *inventory.NetboxInventory) error {
+	for deviceID, nbDevice := range ns.DeviceID2nbDevice {
+		device := ns.Devices[deviceID]
+		primaryIPv4 := ns.mapIPAddress(device.PrimaryIPv4)
+		primaryIPv6 := ns.mapIPAddress(device.PrimaryIPv6)
+		if primaryIPv4 == nil && primaryIPv6 == nil {
+			continue
+		}
+		deviceCopy := *nbDevice
+		deviceCopy.PrimaryIPv4 = primaryIPv4
+		deviceCopy.PrimaryIPv6 = primaryIPv6
+		nbDevice, err := nbi.AddDevice(&deviceCopy)
+		if err != nil {
+			return fmt.Errorf("adding primary ips of netbox device %s: %s", device.Name, err)
+		}
+		ns.DeviceID2nbDevice[deviceID] = nbDevice
+	}
+	for vmID, nbVM := range ns.VMID2nbVM {
+		vm := ns.Vms[vmID]
+		primaryIPv4 := ns.mapIPAddress(vm.PrimaryIPv4)
+		primaryIPv6 := ns.mapIPAddress(vm.PrimaryIPv6)
+		if primaryIPv4 == nil && primaryIPv6 == nil {
+			continue
+		}
+		vmCopy := *nbVM
+		vmCopy.PrimaryIPv4 = primaryIPv4
+		vmCopy.PrimaryIPv6 = primaryIPv6
+		nbVM, err := nbi.AddVM(&vmCopy)
+		if err != nil {
+			return fmt.Errorf("adding primary ips of netbox vm %s: %s", vm.Name, err)
+		}
+		ns.VMID2nbVM[vmID] = nbVM
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package netbox
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory/inventorytest"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+)
+
+// Objects of the source netbox, indexed by their api path. Their ids differ from ids
+// of the synced objects in the target netbox. Device 132 and vm 152 don't match the
+// configured tag filter, so only their interfaces and ip addresses are returned.
+var sourceNetboxObjects = map[string]string{
+	service.SitesAPIPath: `[
+		{"id": 101, "name": "Ljubljana", "slug": "ljubljana"},
+		{"id": 102, "name": "Maribor", "slug": "maribor"}
+	]`,
+	service.DeviceRolesAPIPath: `[{"id": 111, "name": "Server", "slug": "server", "color": "00ff00"}]`,
+	service.ClustersAPIPath:    `[{"id": 121, "name": "cluster01", "site": {"id": 101}}]`,
+	service.DevicesAPIPath:     `[{"id": 131, "name": "server01", "site": {"id": 101}, "role": {"id": 111}, "primary_ip4": {"id": 161}}]`,
+	service.InterfacesAPIPath: `[
+		{"id": 141, "name": "eth0", "device": {"id": 131}},
+		{"id": 142, "name": "eth1", "device": {"id": 131}, "lag": {"id": 143}},
+		{"id": 143, "name": "bond0", "device": {"id": 131}, "type": {"value": "lag"}},
+		{"id": 144, "name": "eth0", "device": {"id": 132}}
+	]`,
+	service.VirtualMachinesAPIPath: `[{"id": 151, "name": "vm01", "cluster": {"id": 121}, "primary_ip4": {"id": 163}}]`,
+	service.VMInterfacesAPIPath: `[
+		{"id": 171, "name": "ens18", "virtual_machine": {"id": 151}},
+		{"id": 172, "name": "ens18", "virtual_machine": {"id": 152}}
+	]`,
+	service.VlansAPIPath:    `[{"id": 181, "name": "mgmt", "vid": 10}]`,
+	service.PrefixesAPIPath: `[{"id": 191, "prefix": "10.0.0.0/24"}]`,
+	service.IPAddressesAPIPath: `[
+		{"id": 161, "address": "10.0.0.1/24", "assigned_object_type": "dcim.interface", "assigned_object_id": 141},
+		{"id": 162, "address": "10.0.0.2/24", "assigned_object_type": "dcim.interface", "assigned_object_id": 144},
+		{"id": 163, "address": "10.0.0.3/24", "assigned_object_type": "virtualization.vminterface", "assigned_object_id": 171},
+		{"id": 164, "address": "10.0.0.4/24", "assigned_object_type": "virtualization.vminterface", "assigned_object_id": 172},
+		{"id": 165, "address": "10.0.0.5/24"}
+	]`,
+}
+
+// initSourceNetbox collects objects of the source netbox, that serves sourceNetboxObjects, with the given token.
+func initSourceNetbox(t *testing.T, token string) (*Source, error) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		results, ok := sourceNetboxObjects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"count": 0, "next": null, "previous": null, "results": %s}`, results)
+	}))
+	t.Cleanup(server.Close)
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns := &Source{
+		Config: common.Config{
+			Logger:       testLogger,
+			SourceConfig: &parser.SourceConfig{Name: "federated-netbox", FilterTags: []string{"federated"}},
+		},
+	}
+	api := service.NewNetBoxAPI(testLogger, server.URL, token, false, 5)
+	initFunctions := []func(*service.NetboxAPI) error{
+		ns.InitSites, ns.InitDeviceRoles, ns.InitClusters, ns.InitDevices, ns.InitInterfaces,
+		ns.InitVMs, ns.InitVMInterfaces, ns.InitVlans, ns.InitPrefixes, ns.InitIPAddresses,
+	}
+	for _, initFunc := range initFunctions {
+		if err := initFunc(api); err != nil {
+			return nil, err
+		}
+	}
+	return ns, nil
+}
+
+// syncSourceNetbox syncs objects of the source netbox to a new inventory.
+func syncSourceNetbox(t *testing.T) (*Source, *inventory.NetboxInventory) {
+	t.Helper()
+	ns, err := initSourceNetbox(t, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbi, _ := inventorytest.NewInventory(t)
+	if _, err := nbi.AddVlanGroup(&objects.VlanGroup{Name: objects.DefaultVlanGroupName, Slug: "default"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.Sync(nbi); err != nil {
+		t.Fatal(err)
+	}
+	return ns, nbi
+}
+
+// assertSourceID fails the test, if the object doesn't reference its id in the source netbox.
+func assertSourceID(t *testing.T, name string, object objects.NetboxObject, sourceID string) {
+	t.Helper()
+	if object.CustomFields[constants.CustomFieldSourceName] != "federated-netbox" || object.CustomFields[constants.CustomFieldSourceIDName] != sourceID {
+		t.Errorf("custom fields of %s = %v, want source federated-netbox and source_id %s", name, object.CustomFields, sourceID)
+	}
+}
+
+func TestSyncDevices(t *testing.T) {
+	ns, nbi := syncSourceNetbox(t)
+	// Only sites referenced by synced objects are synced
+	if _, ok := nbi.SitesIndexByName["Maribor"]; ok {
+		t.Errorf("site Maribor is not referenced, but it was synced")
+	}
+	site := ns.SiteID2nbSite[101]
+	if site == nil || nbi.SitesIndexByName["Ljubljana"] != site {
+		t.Fatalf("site 101 was mapped to %v, want Ljubljana", site)
+	}
+	assertSourceID(t, "site Ljubljana", site.NetboxObject, "101")
+
+	if len(ns.DeviceID2nbDevice) != 1 {
+		t.Fatalf("synced devices = %v, want only server01", ns.DeviceID2nbDevice)
+	}
+	device := ns.DeviceID2nbDevice[131]
+	if device == nil || device.ID == 131 || device.Site == nil || device.Site.ID != site.ID {
+		t.Fatalf("device 131 was mapped to %+v, want new device in site Ljubljana", device)
+	}
+	assertSourceID(t, "device server01", device.NetboxObject, "131")
+	if role := nbi.DeviceRolesIndexByName["Server"]; role == nil || role.Color != "00ff00" || device.DeviceRole == nil || device.DeviceRole.ID != role.ID {
+		t.Errorf("role of server01 = %v, want Server with color from the source netbox", device.DeviceRole)
+	}
+
+	// Interfaces of devices, that were not collected, are skipped
+	if len(ns.InterfaceID2nbInterface) != 3 {
+		t.Errorf("synced interfaces = %v, want eth0, eth1 and bond0", ns.InterfaceID2nbInterface)
+	}
+	eth0, eth1, bond0 := ns.InterfaceID2nbInterface[141], ns.InterfaceID2nbInterface[142], ns.InterfaceID2nbInterface[143]
+	if eth0 == nil || eth1 == nil || bond0 == nil {
+		t.Fatalf("interfaces 141, 142 and 143 were mapped to %v, %v, %v", eth0, eth1, bond0)
+	}
+	for sourceID, iface := range map[string]*objects.Interface{"141": eth0, "142": eth1, "143": bond0} {
+		if iface.Device == nil || iface.Device.ID != device.ID {
+			t.Errorf("interface %s = %+v, want it on device server01", iface.Name, iface)
+		}
+		assertSourceID(t, "interface "+iface.Name, iface.NetboxObject, sourceID)
+	}
+	// Lag is synced before its member, and referenced by its id in the target netbox
+	if eth1.LAG == nil || eth1.LAG.ID != bond0.ID {
+		t.Errorf("lag of eth1 = %v, want bond0 with id %d", eth1.LAG, bond0.ID)
+	}
+
+	ipAddress := ns.IPAddressID2nbIPAddress[161]
+	if ipAddress == nil || ipAddress.AssignedObjectType != objects.AssignedObjectTypeDeviceInterface || ipAddress.AssignedObjectID != eth0.ID {
+		t.Fatalf("ip address 161 was mapped to %+v, want it assigned to interface eth0 with id %d", ipAddress, eth0.ID)
+	}
+	assertSourceID(t, "ip address 10.0.0.1/24", ipAddress.NetboxObject, "161")
+	if device = ns.DeviceID2nbDevice[131]; device.PrimaryIPv4 == nil || device.PrimaryIPv4.ID != ipAddress.ID {
+		t.Errorf("primary ipv4 of server01 = %v, want ip address with id %d", device.PrimaryIPv4, ipAddress.ID)
+	}
+}
+
+func TestSyncVMs(t *testing.T) {
+	ns, nbi := syncSourceNetbox(t)
+	cluster := nbi.ClustersIndexByName["cluster01"]
+	if cluster == nil || ns.ClusterID2nbCluster[121] != cluster || cluster.Site == nil || cluster.Site.ID != ns.SiteID2nbSite[101].ID {
+		t.Fatalf("cluster 121 was mapped to %v, want cluster01 in site Ljubljana", ns.ClusterID2nbCluster[121])
+	}
+	assertSourceID(t, "cluster cluster01", cluster.NetboxObject, "121")
+
+	vm := ns.VMID2nbVM[151]
+	if len(ns.VMID2nbVM) != 1 || vm == nil || vm.Cluster == nil || vm.Cluster.ID != cluster.ID {
+		t.Fatalf("synced vms = %v, want only vm01 in cluster01", ns.VMID2nbVM)
+	}
+	assertSourceID(t, "vm vm01", vm.NetboxObject, "151")
+	vmInterface := ns.VMInterfaceID2nbVMInterface[171]
+	if len(ns.VMInterfaceID2nbVMInterface) != 1 || vmInterface == nil || vmInterface.VM == nil || vmInterface.VM.ID != vm.ID {
+		t.Fatalf("synced vm interfaces = %v, want only ens18 of vm01", ns.VMInterfaceID2nbVMInterface)
+	}
+	assertSourceID(t, "vm interface ens18", vmInterface.NetboxObject, "171")
+
+	// Ip addresses of interfaces, that were not synced, and unassigned ip addresses are skipped
+	if len(ns.IPAddressID2nbIPAddress) != 2 {
+		t.Errorf("synced ip addresses = %v, want 161 and 163", ns.IPAddressID2nbIPAddress)
+	}
+	ipAddress := ns.IPAddressID2nbIPAddress[163]
+	if ipAddress == nil || ipAddress.AssignedObjectType != objects.AssignedObjectTypeVMInterface || ipAddress.AssignedObjectID != vmInterface.ID {
+		t.Fatalf("ip address 163 was mapped to %+v, want it assigned to vm interface ens18 with id %d", ipAddress, vmInterface.ID)
+	}
+	if vm = ns.VMID2nbVM[151]; vm.PrimaryIPv4 == nil || vm.PrimaryIPv4.ID != ipAddress.ID {
+		t.Errorf("primary ipv4 of vm01 = %v, want ip address with id %d", vm.PrimaryIPv4, ipAddress.ID)
+	}
+}
+
+func TestInitWrongToken(t *testing.T) {
+	if _, err := initSourceNetbox(t, "wrong"); err == nil {
+		t.Errorf("Init() expected error for wrong token")
+	}
+}
+
+func TestFilterParams(t *testing.T) {
+	ns := &Source{
+		Config: common.Config{
+			SourceConfig: &parser.SourceConfig{
+				FilterTags:    []string{"federated", "prod"},
+				FilterSites:   []string{"ljubljana"},
+				FilterTenants: []string{"acme"},
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		byTag    bool
+		bySite   bool
+		byTenant bool
+		expected string
+	}{
+		{name: "All filters", byTag: true, bySite: true, byTenant: true, expected: "&site=ljubljana&tag=federated&tag=prod&tenant=acme"},
+		{name: "Only site filter", bySite: true, expected: "&site=ljubljana"},
+		{name: "No filters", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ns.filterParams(tt.byTag, tt.bySite, tt.byTenant); got != tt.expected {
+				t.Errorf("filterParams() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
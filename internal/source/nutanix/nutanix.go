@@ -0,0 +1,104 @@
+package nutanix
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// Source represents a Nutanix Prism Central source.
+type Source struct {
+	common.Config
+
+	// Nutanix fetched data. Initialized in init functions.
+	Clusters map[string]Cluster // ClusterUUID -> Cluster
+	Hosts    map[string]Host    // HostUUID -> Host
+	Vms      map[string]VM      // VMUUID -> VM
+	Subnets  map[string]Subnet  // SubnetUUID -> Subnet
+
+	// Netbox related data for easier access. Initialized in sync functions.
+	ClusterUUID2nbCluster map[string]*objects.Cluster // ClusterUUID -> nbCluster
+	HostUUID2nbHost       map[string]*objects.Device  // HostUUID -> nbDevice
+
+	// User defined relations
+	HostSiteRelations      map[string]string
+	ClusterSiteRelations   map[string]string
+	ClusterTenantRelations map[string]string
+	HostTenantRelations    map[string]string
+	VMTenantRelations      map[string]string
+	VlanGroupRelations     map[string]string
+	VlanTenantRelations    map[string]string
+}
+
+// Function that initializes state from Prism Central API to local storage.
+func (ns *Source) Init() error {
+	// Initialize regex relations
+	ns.Logger.Debug("Initializing regex relations for nutanix source ", ns.SourceConfig.Name)
+	ns.HostSiteRelations = utils.ConvertStringsToRegexPairs(ns.SourceConfig.HostSiteRelations)
+	ns.Logger.Debug("HostSiteRelations: ", ns.HostSiteRelations)
+	ns.ClusterSiteRelations = utils.ConvertStringsToRegexPairs(ns.SourceConfig.ClusterSiteRelations)
+	ns.Logger.Debug("ClusterSiteRelations: ", ns.ClusterSiteRelations)
+	ns.ClusterTenantRelations = utils.ConvertStringsToRegexPairs(ns.SourceConfig.ClusterTenantRelations)
+	ns.Logger.Debug("ClusterTenantRelations: ", ns.ClusterTenantRelations)
+	ns.HostTenantRelations = utils.ConvertStringsToRegexPairs(ns.SourceConfig.HostTenantRelations)
+	ns.Logger.Debug("HostTenantRelations: ", ns.HostTenantRelations)
+	ns.VMTenantRelations = utils.ConvertStringsToRegexPairs(ns.SourceConfig.VMTenantRelations)
+	ns.Logger.Debug("VMTenantRelations: ", ns.VMTenantRelations)
+	ns.VlanGroupRelations = utils.ConvertStringsToRegexPairs(ns.SourceConfig.VlanGroupRelations)
+	ns.Logger.Debug("VlanGroupRelations: ", ns.VlanGroupRelations)
+	ns.VlanTenantRelations = utils.ConvertStringsToRegexPairs(ns.SourceConfig.VlanTenantRelations)
+	ns.Logger.Debug("VlanTenantRelations: ", ns.VlanTenantRelations)
+	ns.ClusterVRFRelations = utils.ConvertStringsToRegexPairs(ns.SourceConfig.ClusterVRFRelations)
+	ns.Logger.Debug("ClusterVRFRelations: ", ns.ClusterVRFRelations)
+	ns.SubnetVRFRelations = utils.ConvertStringsToPairs(ns.SourceConfig.SubnetVRFRelations)
+	ns.Logger.Debug("SubnetVRFRelations: ", ns.SubnetVRFRelations)
+
+	baseURL := fmt.Sprintf("%s://%s:%d", ns.SourceConfig.HTTPScheme, ns.SourceConfig.Hostname, ns.SourceConfig.Port)
+	client := newAPIClient(baseURL, ns.SourceConfig.Username, ns.SourceConfig.Password, ns.SourceConfig.ValidateCert)
+
+	// Initialize items from Prism Central API to local storage
+	initFunctions := []func(*apiClient) error{
+		ns.InitSubnets,
+		ns.InitClusters,
+		ns.InitHosts,
+		ns.InitVms,
+	}
+
+	for _, initFunc := range initFunctions {
+		startTime := time.Now()
+		if err := initFunc(client); err != nil {
+			return fmt.Errorf("nutanix initialization failure: %v", err)
+		}
+		duration := time.Since(startTime)
+		ns.Logger.Infof("Successfully initialized %s in %f seconds", utils.ExtractFunctionName(initFunc), duration.Seconds())
+	}
+	return nil
+}
+
+// Function that syncs all data from Prism Central to Netbox.
+func (ns *Source) Sync(nbi *inventory.NetboxInventory) error {
+	// initialize variables, that are shared between sync functions
+	ns.ClusterUUID2nbCluster = make(map[string]*objects.Cluster)
+	ns.HostUUID2nbHost = make(map[string]*objects.Device)
+
+	syncFunctions := []func(*inventory.NetboxInventory) error{
+		ns.syncNetworks,
+		ns.syncClusters,
+		ns.syncHosts,
+		ns.syncVms,
+	}
+	for _, syncFunc := range syncFunctions {
+		startTime := time.Now()
+		err := syncFunc(nbi)
+		if err != nil {
+			return err
+		}
+		duration := time.Since(startTime)
+		ns.Logger.Infof("Successfully synced %s in %f seconds", utils.ExtractFunctionName(syncFunc), duration.Seconds())
+	}
+	return nil
+}
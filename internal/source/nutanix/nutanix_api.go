@@ -0,0 +1,199 @@
+package nutanix
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+)
+
+// Max number of entities that Prism Central v3 API returns in one list call.
+const defaultPageLength = 250
+
+// Minimal client for Prism Central v3 API.
+type apiClient struct {
+	BaseURL    string
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+}
+
+func newAPIClient(baseURL string, username string, password string, validateCert bool) *apiClient {
+	return &apiClient{
+		BaseURL:  baseURL,
+		Username: username,
+		Password: password,
+		HTTPClient: &http.Client{
+			Timeout: time.Second * constants.DefaultTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: !validateCert}, //nolint:gosec
+			},
+		},
+	}
+}
+
+// Request body for all v3 list endpoints.
+type listRequest struct {
+	Kind   string `json:"kind"`
+	Length int    `json:"length"`
+	Offset int    `json:"offset"`
+}
+
+type listMetadata struct {
+	TotalMatches int `json:"total_matches"`
+	Length       int `json:"length"`
+	Offset       int `json:"offset"`
+}
+
+type listResponse[T any] struct {
+	Metadata listMetadata `json:"metadata"`
+	Entities []T          `json:"entities"`
+}
+
+// Reference to another entity (e.g. cluster_reference, host_reference).
+type Reference struct {
+	Kind string `json:"kind"`
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+type EntityMetadata struct {
+	UUID string `json:"uuid"`
+}
+
+type Cluster struct {
+	Metadata EntityMetadata `json:"metadata"`
+	Status   struct {
+		Name      string `json:"name"`
+		Resources struct {
+			Config struct {
+				ServiceList []string `json:"service_list"`
+			} `json:"config"`
+		} `json:"resources"`
+	} `json:"status"`
+}
+
+type Host struct {
+	Metadata EntityMetadata `json:"metadata"`
+	Status   struct {
+		Name             string    `json:"name"`
+		ClusterReference Reference `json:"cluster_reference"`
+		Resources        struct {
+			SerialNumber      string `json:"serial_number"`
+			CPUModel          string `json:"cpu_model"`
+			NumCPUSockets     int    `json:"num_cpu_sockets"`
+			NumCPUCores       int    `json:"num_cpu_cores"`
+			MemoryCapacityMib int64  `json:"memory_capacity_mib"`
+			Block             struct {
+				BlockSerialNumber string `json:"block_serial_number"`
+				BlockModel        string `json:"block_model"`
+			} `json:"block"`
+			Hypervisor struct {
+				IP                 string `json:"ip"`
+				HypervisorFullName string `json:"hypervisor_full_name"`
+			} `json:"hypervisor"`
+		} `json:"resources"`
+	} `json:"status"`
+}
+
+type VMDisk struct {
+	DiskSizeMib      int64 `json:"disk_size_mib"`
+	DeviceProperties struct {
+		DeviceType string `json:"device_type"`
+	} `json:"device_properties"`
+}
+
+type VMNic struct {
+	UUID            string    `json:"uuid"`
+	MacAddress      string    `json:"mac_address"`
+	IsConnected     bool      `json:"is_connected"`
+	SubnetReference Reference `json:"subnet_reference"`
+	IPEndpointList  []struct {
+		IP   string `json:"ip"`
+		Type string `json:"type"`
+	} `json:"ip_endpoint_list"`
+}
+
+type VM struct {
+	Metadata EntityMetadata `json:"metadata"`
+	Status   struct {
+		Name             string    `json:"name"`
+		Description      string    `json:"description"`
+		ClusterReference Reference `json:"cluster_reference"`
+		Resources        struct {
+			PowerState        string    `json:"power_state"`
+			NumSockets        int       `json:"num_sockets"`
+			NumVcpusPerSocket int       `json:"num_vcpus_per_socket"`
+			MemorySizeMib     int       `json:"memory_size_mib"`
+			HostReference     Reference `json:"host_reference"`
+			DiskList          []VMDisk  `json:"disk_list"`
+			NicList           []VMNic   `json:"nic_list"`
+		} `json:"resources"`
+	} `json:"status"`
+}
+
+type Subnet struct {
+	Metadata EntityMetadata `json:"metadata"`
+	Status   struct {
+		Name             string    `json:"name"`
+		ClusterReference Reference `json:"cluster_reference"`
+		Resources        struct {
+			VlanID     int    `json:"vlan_id"`
+			SubnetType string `json:"subnet_type"`
+			IPConfig   struct {
+				SubnetIP     string `json:"subnet_ip"`
+				PrefixLength int    `json:"prefix_length"`
+			} `json:"ip_config"`
+		} `json:"resources"`
+	} `json:"status"`
+}
+
+// listAll fetches all entities of the given kind, using pagination.
+func listAll[T any](c *apiClient, kind string) ([]T, error) {
+	entities := make([]T, 0)
+	offset := 0
+	for {
+		body, err := json.Marshal(listRequest{Kind: kind, Length: defaultPageLength, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		url := fmt.Sprintf("%s/api/nutanix/v3/%ss/list", c.BaseURL, kind)
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.SetBasicAuth(c.Username, c.Password)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %d for %s: %s", resp.StatusCode, url, respBody)
+		}
+
+		var listResp listResponse[T]
+		if err := json.Unmarshal(respBody, &listResp); err != nil {
+			return nil, fmt.Errorf("unmarshal %s list: %s", kind, err)
+		}
+		entities = append(entities, listResp.Entities...)
+
+		offset += len(listResp.Entities)
+		if len(listResp.Entities) == 0 || offset >= listResp.Metadata.TotalMatches {
+			break
+		}
+	}
+	return entities, nil
+}
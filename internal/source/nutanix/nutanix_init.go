@@ -0,0 +1,65 @@
+package nutanix
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Service that is listed on the Prism Central's own cluster entity.
+const prismCentralService = "PRISM_CENTRAL"
+
+func (ns *Source) InitSubnets(c *apiClient) error {
+	subnets, err := listAll[Subnet](c, "subnet")
+	if err != nil {
+		return fmt.Errorf("init subnets: %s", err)
+	}
+	ns.Subnets = make(map[string]Subnet, len(subnets))
+	for _, subnet := range subnets {
+		ns.Subnets[subnet.Metadata.UUID] = subnet
+	}
+	return nil
+}
+
+// Prism Central lists itself as a cluster, so we skip it.
+func (ns *Source) InitClusters(c *apiClient) error {
+	clusters, err := listAll[Cluster](c, "cluster")
+	if err != nil {
+		return fmt.Errorf("init clusters: %s", err)
+	}
+	ns.Clusters = make(map[string]Cluster, len(clusters))
+	for _, cluster := range clusters {
+		if slices.Contains(cluster.Status.Resources.Config.ServiceList, prismCentralService) {
+			continue
+		}
+		ns.Clusters[cluster.Metadata.UUID] = cluster
+	}
+	return nil
+}
+
+func (ns *Source) InitHosts(c *apiClient) error {
+	hosts, err := listAll[Host](c, "host")
+	if err != nil {
+		return fmt.Errorf("init hosts: %s", err)
+	}
+	ns.Hosts = make(map[string]Host, len(hosts))
+	for _, host := range hosts {
+		// Prism Central vm is also listed as host without a name
+		if host.Status.Name == "" {
+			continue
+		}
+		ns.Hosts[host.Metadata.UUID] = host
+	}
+	return nil
+}
+
+func (ns *Source) InitVms(c *apiClient) error {
+	vms, err := listAll[VM](c, "vm")
+	if err != nil {
+		return fmt.Errorf("init vms: %s", err)
+	}
+	ns.Vms = make(map[string]VM, len(vms))
+	for _, vm := range vms {
+		ns.Vms[vm.Metadata.UUID] = vm
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package nutanix
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// Syncs nutanix subnets with vlan ids as netbox vlans.
+func (ns *Source) syncNetworks(nbi *inventory.NetboxInventory) error {
+	for _, subnet := range ns.Subnets {
+		// Overlay subnets (and subnets without vlan) have vlan_id 0
+		if subnet.Status.Resources.VlanID == 0 {
+			continue
+		}
+		subnetName := subnet.Status.Name
+		vlanGroup, err := common.MatchVlanToGroup(nbi, subnetName, ns.VlanGroupRelations)
+		if err != nil {
+			return fmt.Errorf("vlan group: %s", err)
+		}
+		vlanTenant, err := common.MatchVlanToTenant(nbi, subnetName, ns.VlanTenantRelations)
+		if err != nil {
+			return fmt.Errorf("vlan tenant: %s", err)
+		}
+		_, err = nbi.AddVlan(&objects.Vlan{
+			NetboxObject: objects.NetboxObject{
+				Tags: ns.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: ns.SourceConfig.Name,
+				},
+			},
+			Name:   subnetName,
+			Group:  vlanGroup,
+			Vid:    subnet.Status.Resources.VlanID,
+			Status: &objects.VlanStatusActive,
+			Tenant: vlanTenant,
+		})
+		if err != nil {
+			return fmt.Errorf("adding vlan: %s", err)
+		}
+	}
+	return nil
+}
+
+func (ns *Source) syncClusters(nbi *inventory.NetboxInventory) error {
+	clusterType, err := nbi.AddClusterType(&objects.ClusterType{
+		NetboxObject: objects.NetboxObject{
+			Tags: ns.Config.SourceTags,
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName: ns.SourceConfig.Name,
+			},
+		},
+		Name: "Nutanix",
+		Slug: "nutanix",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add nutanix cluster type: %v", err)
+	}
+	for clusterUUID, cluster := range ns.Clusters {
+		clusterName := cluster.Status.Name
+		clusterSite, err := common.MatchClusterToSite(nbi, clusterName, ns.ClusterSiteRelations)
+		if err != nil {
+			return fmt.Errorf("cluster site: %s", err)
+		}
+		clusterTenant, err := common.MatchClusterToTenant(nbi, clusterName, ns.ClusterTenantRelations)
+		if err != nil {
+			return fmt.Errorf("cluster tenant: %s", err)
+		}
+		err = nbi.AddCluster(&objects.Cluster{
+			NetboxObject: objects.NetboxObject{
+				Tags: ns.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   ns.SourceConfig.Name,
+					constants.CustomFieldSourceIDName: clusterUUID,
+				},
+			},
+			Name:   clusterName,
+			Type:   clusterType,
+			Status: objects.ClusterStatusActive,
+			Site:   clusterSite,
+			Tenant: clusterTenant,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add nutanix cluster %s: %v", clusterName, err)
+		}
+		ns.ClusterUUID2nbCluster[clusterUUID] = nbi.ClustersIndexByName[clusterName]
+	}
+	return nil
+}
+
+// Host in nutanix is represented as device in netbox with a
+// custom role Server.
+func (ns *Source) syncHosts(nbi *inventory.NetboxInventory) error {
+	for hostUUID, host := range ns.Hosts {
+		hostName := host.Status.Name
+		hostCluster := ns.ClusterUUID2nbCluster[host.Status.ClusterReference.UUID]
+
+		hostSite, err := common.MatchHostToSite(nbi, hostName, ns.HostSiteRelations)
+		if err != nil {
+			return fmt.Errorf("hostSite: %s", err)
+		}
+		if hostSite == nil && hostCluster != nil {
+			hostSite = hostCluster.Site
+		}
+		if hostSite == nil {
+			ns.Logger.Warningf("nutanix host %s can't be matched to any site, so it will be skipped", hostName)
+			continue
+		}
+		hostTenant, err := common.MatchHostToTenant(nbi, hostName, ns.HostTenantRelations)
+		if err != nil {
+			return fmt.Errorf("hostTenant: %s", err)
+		}
+
+		hostManufacturer, err := nbi.AddManufacturer(&objects.Manufacturer{
+			Name: "Nutanix",
+			Slug: "nutanix",
+		})
+		if err != nil {
+			return fmt.Errorf("failed adding nutanix Manufacturer %v with error: %s", hostManufacturer, err)
+		}
+
+		hostModel := host.Status.Resources.Block.BlockModel
+		if hostModel == "" {
+			hostModel = constants.DefaultModel // Model is also required for adding device type into netbox
+		}
+		hostDeviceType, err := nbi.AddDeviceType(&objects.DeviceType{
+			Manufacturer: hostManufacturer,
+			Model:        hostModel,
+			Slug:         utils.Slugify(hostModel),
+		})
+		if err != nil {
+			return fmt.Errorf("failed adding nutanix DeviceType %v with error: %s", hostDeviceType, err)
+		}
+
+		platformName := host.Status.Resources.Hypervisor.HypervisorFullName
+		if platformName == "" {
+			platformName = utils.GeneratePlatformName(constants.DefaultOSName, constants.DefaultOSVersion)
+		}
+		hostPlatform, err := nbi.AddPlatform(&objects.Platform{
+			Name: platformName,
+			Slug: utils.Slugify(platformName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed adding nutanix Platform %v with error: %s", hostPlatform, err)
+		}
+
+		var hostDescription string
+		if blockSerial := host.Status.Resources.Block.BlockSerialNumber; blockSerial != "" {
+			hostDescription = fmt.Sprintf("Block %s (%s)", blockSerial, host.Status.Resources.Block.BlockModel)
+		}
+
+		mem := host.Status.Resources.MemoryCapacityMib / constants.KiB // Value is in MiB, we convert to GB
+
+		nbHost, err := nbi.AddDevice(&objects.Device{
+			NetboxObject: objects.NetboxObject{
+				Description: hostDescription,
+				Tags:        ns.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:       ns.SourceConfig.Name,
+					constants.CustomFieldSourceIDName:     hostUUID,
+					constants.CustomFieldHostCPUCoresName: fmt.Sprintf("%d", host.Status.Resources.NumCPUCores),
+					constants.CustomFieldHostMemoryName:   fmt.Sprintf("%d GB", mem),
+				},
+			},
+			Name:         hostName,
+			Status:       &objects.DeviceStatusActive,
+			Platform:     hostPlatform,
+			DeviceRole:   nbi.DeviceRolesIndexByName["Server"],
+			Site:         hostSite,
+			Tenant:       hostTenant,
+			Cluster:      hostCluster,
+			SerialNumber: host.Status.Resources.SerialNumber,
+			DeviceType:   hostDeviceType,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add nutanix host %s with error: %v", hostName, err)
+		}
+		ns.HostUUID2nbHost[hostUUID] = nbHost
+	}
+	return nil
+}
+
+func (ns *Source) syncVms(nbi *inventory.NetboxInventory) error {
+	for vmUUID, vm := range ns.Vms {
+		vmName := vm.Status.Name
+		vmCluster := ns.ClusterUUID2nbCluster[vm.Status.ClusterReference.UUID]
+		vmHost := ns.HostUUID2nbHost[vm.Status.Resources.HostReference.UUID]
+
+		// Tenant is received from VmTenantRelations
+		vmTenant, err := common.MatchVMToTenant(nbi, vmName, ns.VMTenantRelations)
+		if err != nil {
+			return fmt.Errorf("vm's Tenant: %s", err)
+		}
+
+		// Site is the same as the Host or the Cluster
+		var vmSite *objects.Site
+		if vmHost != nil {
+			vmSite = vmHost.Site
+		} else if vmCluster != nil {
+			vmSite = vmCluster.Site
+		}
+
+		vmStatus := &objects.VMStatusOffline
+		if vm.Status.Resources.PowerState == "ON" {
+			vmStatus = &objects.VMStatusActive
+		}
+
+		vmVCPUs := vm.Status.Resources.NumSockets * vm.Status.Resources.NumVcpusPerSocket
+
+		vmDiskSizeMib := int64(0)
+		for _, disk := range vm.Status.Resources.DiskList {
+			if disk.DeviceProperties.DeviceType == "DISK" {
+				vmDiskSizeMib += disk.DiskSizeMib
+			}
+		}
+
+		vmPlatformName := utils.GeneratePlatformName(constants.DefaultOSName, constants.DefaultOSVersion)
+		vmPlatform, err := nbi.AddPlatform(&objects.Platform{
+			Name: vmPlatformName,
+			Slug: utils.Slugify(vmPlatformName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed adding nutanix vm's Platform %v with error: %s", vmPlatform, err)
+		}
+
+		// netbox description has constraint <= len(200 characters)
+		// In this case we make a comment
+		vmDescription := vm.Status.Description
+		var vmComments string
+		if len(vmDescription) >= objects.MaxDescriptionLength {
+			vmComments = vmDescription
+			vmDescription = "See comments."
+		}
+
+		nbVM, err := nbi.AddVM(&objects.VM{
+			NetboxObject: objects.NetboxObject{
+				Tags:        ns.Config.SourceTags,
+				Description: vmDescription,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   ns.SourceConfig.Name,
+					constants.CustomFieldSourceIDName: vmUUID,
+				},
+			},
+			Name:     vmName,
+			Cluster:  vmCluster,
+			Site:     vmSite,
+			Tenant:   vmTenant,
+			Status:   vmStatus,
+			Host:     vmHost,
+			Platform: vmPlatform,
+			VCPUs:    float32(vmVCPUs),
+			Memory:   vm.Status.Resources.MemorySizeMib,  // MBs
+			Disk:     int(vmDiskSizeMib / constants.KiB), // GBs
+			Comments: vmComments,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to sync nutanix vm: %v", err)
+		}
+
+		err = ns.syncVMInterfaces(nbi, vm, nbVM)
+		if err != nil {
+			return fmt.Errorf("failed to sync nutanix vm's interfaces: %v", err)
+		}
+	}
+	return nil
+}
+
+// Syncs VM's nics to Netbox, together with their ip addresses.
+func (ns *Source) syncVMInterfaces(nbi *inventory.NetboxInventory, vm VM, nbVM *objects.VM) error {
+	var vmIPv4PrimaryAddress *objects.IPAddress
+	var vmIPv6PrimaryAddress *objects.IPAddress
+	for i, nic := range vm.Status.Resources.NicList {
+		subnet, subnetExists := ns.Subnets[nic.SubnetReference.UUID]
+
+		intName := fmt.Sprintf("vNic %d", i+1)
+		if subnetName := nic.SubnetReference.Name; subnetName != "" {
+			intName = fmt.Sprintf("%s (%s)", intName, subnetName)
+		}
+
+		var intMode *objects.VMInterfaceMode
+		var intUntaggedVlan *objects.Vlan
+		var intDescription string
+		if subnetExists && subnet.Status.Resources.VlanID != 0 {
+			vid := subnet.Status.Resources.VlanID
+			vlanGroup, err := common.MatchVlanToGroup(nbi, subnet.Status.Name, ns.VlanGroupRelations)
+			if err != nil {
+				return fmt.Errorf("vlan group: %s", err)
+			}
+			intMode = &objects.VMInterfaceModeAccess
+			intUntaggedVlan = nbi.VlansIndexByVlanGroupIDAndVID[vlanGroup.ID][vid]
+			intDescription = fmt.Sprintf("vlan ID: %d", vid)
+		}
+
+		nbVMInterface, err := nbi.AddVMInterface(&objects.VMInterface{
+			NetboxObject: objects.NetboxObject{
+				Tags:        ns.Config.SourceTags,
+				Description: intDescription,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   ns.SourceConfig.Name,
+					constants.CustomFieldSourceIDName: nic.UUID,
+				},
+			},
+			VM:           nbVM,
+			Name:         intName,
+			MACAddress:   strings.ToUpper(nic.MacAddress),
+			Mode:         intMode,
+			Enabled:      nic.IsConnected,
+			UntaggedVlan: intUntaggedVlan,
+		})
+		if err != nil {
+			return fmt.Errorf("adding VmInterface: %s", err)
+		}
+
+		for _, ipEndpoint := range nic.IPEndpointList {
+			if !ns.IsPermittedIPAddress(ipEndpoint.IP) {
+				continue
+			}
+			ipVersion := utils.GetIPVersion(ipEndpoint.IP)
+			prefixLength := 32
+			if ipVersion == constants.IPv6 {
+				prefixLength = 128
+			}
+			if subnetExists && subnet.Status.Resources.IPConfig.PrefixLength != 0 && utils.GetIPVersion(subnet.Status.Resources.IPConfig.SubnetIP) == ipVersion {
+				prefixLength = subnet.Status.Resources.IPConfig.PrefixLength
+			}
+			vrf, err := ns.MatchIPToVRF(nbi, ipEndpoint.IP, nbVM.Cluster)
+			if err != nil {
+				ns.Logger.Warningf("matching ip address to vrf: %s", err)
+				continue
+			}
+			nbIPAddress, err := nbi.AddIPAddress(&objects.IPAddress{
+				NetboxObject: objects.NetboxObject{
+					Tags: ns.Config.SourceTags,
+					CustomFields: map[string]interface{}{
+						constants.CustomFieldSourceName: ns.SourceConfig.Name,
+					},
+				},
+				Address:            fmt.Sprintf("%s/%d", ipEndpoint.IP, prefixLength),
+				VRF:                vrf,
+				Status:             &objects.IPAddressStatusActive,
+				DNSName:            ns.DNSName(ipEndpoint.IP, ""),
+				AssignedObjectType: objects.AssignedObjectTypeVMInterface,
+				AssignedObjectID:   nbVMInterface.ID,
+			})
+			if err != nil {
+				ns.Logger.Warningf("adding ip address: %s", err)
+				continue
+			}
+			switch ipVersion {
+			case constants.IPv4:
+				if vmIPv4PrimaryAddress == nil {
+					vmIPv4PrimaryAddress = nbIPAddress
+				}
+			case constants.IPv6:
+				if vmIPv6PrimaryAddress == nil {
+					vmIPv6PrimaryAddress = nbIPAddress
+				}
+			}
+		}
+	}
+
+	// Primary ips are the first collected ipv4 and ipv6 addresses
+	if vmIPv4PrimaryAddress != nil || vmIPv6PrimaryAddress != nil {
+		newNbVM := *nbVM
+		newNbVM.PrimaryIPv4 = vmIPv4PrimaryAddress
+		newNbVM.PrimaryIPv6 = vmIPv6PrimaryAddress
+		_, err := nbi.AddVM(&newNbVM)
+		if err != nil {
+			return fmt.Errorf("updating vm's primary ip: %s", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package nutanix
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory/inventorytest"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+)
+
+// Prism Central entities, indexed by entity kind. Prism Central lists itself
+// both as a cluster and as a host without a name.
+var prismCentralEntities = map[string][]string{
+	"cluster": {
+		`{"metadata": {"uuid": "c1"}, "status": {"name": "cluster1", "resources": {"config": {"service_list": ["AOS"]}}}}`,
+		`{"metadata": {"uuid": "pc"}, "status": {"name": "prism-central", "resources": {"config": {"service_list": ["PRISM_CENTRAL"]}}}}`,
+	},
+	"host": {
+		`{"metadata": {"uuid": "h1"}, "status": {"name": "host1", "cluster_reference": {"uuid": "c1"}, "resources": {"serial_number": "SN1", "num_cpu_cores": 32, "memory_capacity_mib": 524288, "block": {"block_serial_number": "BSN1", "block_model": "NX-3060-G7"}, "hypervisor": {"hypervisor_full_name": "AHV 20220304.342"}}}}`,
+		`{"metadata": {"uuid": "pc-host"}, "status": {"resources": {}}}`,
+	},
+	"vm": {
+		`{"metadata": {"uuid": "v1"}, "status": {"name": "vm1", "cluster_reference": {"uuid": "c1"}, "resources": {"power_state": "ON", "num_sockets": 2, "num_vcpus_per_socket": 2, "memory_size_mib": 4096, "host_reference": {"uuid": "h1"}, "disk_list": [{"disk_size_mib": 10240, "device_properties": {"device_type": "DISK"}}, {"disk_size_mib": 20480, "device_properties": {"device_type": "DISK"}}, {"disk_size_mib": 512, "device_properties": {"device_type": "CDROM"}}], "nic_list": [{"uuid": "n1", "mac_address": "50:6b:8d:00:00:01", "is_connected": true, "subnet_reference": {"uuid": "s1", "name": "prod"}, "ip_endpoint_list": [{"ip": "192.168.1.5", "type": "LEARNED"}, {"ip": "10.0.0.5", "type": "ASSIGNED"}]}]}}}`,
+		`{"metadata": {"uuid": "v2"}, "status": {"name": "vm2", "cluster_reference": {"uuid": "c1"}, "resources": {"power_state": "OFF"}}}`,
+		`{"metadata": {"uuid": "v3"}, "status": {"name": "vm3", "cluster_reference": {"uuid": "c1"}, "resources": {"power_state": "ON"}}}`,
+	},
+	"subnet": {
+		`{"metadata": {"uuid": "s1"}, "status": {"name": "prod", "cluster_reference": {"uuid": "c1"}, "resources": {"vlan_id": 100, "subnet_type": "VLAN", "ip_config": {"subnet_ip": "10.0.0.0", "prefix_length": 24}}}}`,
+	},
+}
+
+// newPrismCentral returns client of Prism Central, that serves prismCentralEntities.
+// At most two entities are returned per page, so all lists are paginated.
+func newPrismCentral(t *testing.T, password string) *apiClient {
+	t.Helper()
+	const pageLength = 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, pass, ok := r.BasicAuth(); !ok || username != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req listRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/api/nutanix/v3/"+req.Kind+"s/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		entities := prismCentralEntities[req.Kind]
+		page := make([]json.RawMessage, 0)
+		for _, entity := range entities[req.Offset:min(req.Offset+pageLength, len(entities))] {
+			page = append(page, json.RawMessage(entity))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"metadata": map[string]int{"total_matches": len(entities), "length": len(page), "offset": req.Offset},
+			"entities": page,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return newAPIClient(server.URL, "admin", password, false)
+}
+
+// syncPrismCentral collects all entities of Prism Central and syncs them to a new inventory.
+func syncPrismCentral(t *testing.T) *inventory.NetboxInventory {
+	t.Helper()
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns := &Source{
+		Config: common.Config{
+			Logger: testLogger,
+			SourceConfig: &parser.SourceConfig{
+				Name:                 "nutanix",
+				PermittedSubnets:     []string{"10.0.0.0/8"},
+				DisableReverseLookup: true,
+			},
+		},
+		HostSiteRelations: map[string]string{".*": "site1"},
+	}
+	client := newPrismCentral(t, "secret")
+	for _, initFunc := range []func(*apiClient) error{ns.InitSubnets, ns.InitClusters, ns.InitHosts, ns.InitVms} {
+		if err := initFunc(client); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nbi, _ := inventorytest.NewInventory(t)
+	if _, err := nbi.AddSite(&objects.Site{Name: "site1", Slug: "site1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nbi.AddVlanGroup(&objects.VlanGroup{Name: objects.DefaultVlanGroupName, Slug: "default"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ns.Sync(nbi); err != nil {
+		t.Fatal(err)
+	}
+	return nbi
+}
+
+func TestSyncSkipsPrismCentral(t *testing.T) {
+	nbi := syncPrismCentral(t)
+	if _, ok := nbi.ClustersIndexByName["prism-central"]; ok {
+		t.Errorf("prism central was synced as a cluster")
+	}
+	cluster, ok := nbi.ClustersIndexByName["cluster1"]
+	if !ok {
+		t.Fatalf("cluster1 was not synced")
+	}
+	hosts := nbi.DevicesIndexBySourceAndSourceID["nutanix"]
+	if len(hosts) != 1 || hosts["h1"] == nil {
+		t.Fatalf("synced hosts = %v, want only host1", hosts)
+	}
+	if host := hosts["h1"]; host.Cluster == nil || host.Cluster.ID != cluster.ID || host.SerialNumber != "SN1" {
+		t.Errorf("host1 = %+v, want serial number SN1 in cluster1", host)
+	}
+	// Vms are collected across all pages
+	if vms := nbi.VMsIndexBySourceAndSourceID["nutanix"]; len(vms) != 3 {
+		t.Errorf("synced %d vms, want 3", len(vms))
+	}
+}
+
+func TestSyncVMDiskSize(t *testing.T) {
+	nbi := syncPrismCentral(t)
+	vm := nbi.VMsIndexBySourceAndSourceID["nutanix"]["v1"]
+	if vm == nil {
+		t.Fatal("vm1 was not synced")
+	}
+	// Cdroms are not counted, only disks (10 GB + 20 GB)
+	if vm.Disk != 30 {
+		t.Errorf("disk of vm1 = %d GB, want 30 GB", vm.Disk)
+	}
+	if vm.VCPUs != 4 || vm.Memory != 4096 {
+		t.Errorf("vm1 has %f vcpus and %d MB memory, want 4 vcpus and 4096 MB", vm.VCPUs, vm.Memory)
+	}
+}
+
+func TestSyncVMIPAddresses(t *testing.T) {
+	nbi := syncPrismCentral(t)
+	var ipAddresses []*objects.IPAddress
+	for _, vrfIPAddresses := range nbi.IPAddressesIndexByVRFIDAndAddress {
+		for _, ipAddress := range vrfIPAddresses {
+			ipAddresses = append(ipAddresses, ipAddress)
+		}
+	}
+	// Address outside of permitted subnets is skipped, and prefix length is taken from nic's subnet
+	if len(ipAddresses) != 1 || ipAddresses[0].Address != "10.0.0.5/24" {
+		t.Fatalf("synced ip addresses = %v, want [10.0.0.5/24]", ipAddresses)
+	}
+	vm := nbi.VMsIndexBySourceAndSourceID["nutanix"]["v1"]
+	if vm.PrimaryIPv4 == nil || vm.PrimaryIPv4.ID != ipAddresses[0].ID {
+		t.Errorf("primary ipv4 of vm1 = %v, want 10.0.0.5/24", vm.PrimaryIPv4)
+	}
+}
+
+func TestInitWrongCredentials(t *testing.T) {
+	ns := &Source{}
+	if err := ns.InitClusters(newPrismCentral(t, "wrong")); err == nil {
+		t.Errorf("InitClusters() expected error for wrong credentials")
+	}
+}
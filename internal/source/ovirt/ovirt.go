@@ -17,12 +17,13 @@ import (
 //nolint:revive
 type OVirtSource struct {
 	common.Config
-	Disks       map[string]*ovirtsdk4.Disk
-	DataCenters map[string]*ovirtsdk4.DataCenter
-	Clusters    map[string]*ovirtsdk4.Cluster
-	Hosts       map[string]*ovirtsdk4.Host
-	Vms         map[string]*ovirtsdk4.Vm
-	Networks    *NetworkData
+	Disks          map[string]*ovirtsdk4.Disk
+	StorageDomains map[string]*ovirtsdk4.StorageDomain
+	DataCenters    map[string]*ovirtsdk4.DataCenter
+	Clusters       map[string]*ovirtsdk4.Cluster
+	Hosts          map[string]*ovirtsdk4.Host
+	Vms            map[string]*ovirtsdk4.Vm
+	Networks       *NetworkData
 
 	HostSiteRelations      map[string]string
 	ClusterSiteRelations   map[string]string
@@ -56,6 +57,10 @@ func (o *OVirtSource) Init() error {
 	o.Logger.Debug("VlanGroupRelations: ", o.VlanGroupRelations)
 	o.VlanTenantRelations = utils.ConvertStringsToRegexPairs(o.SourceConfig.VlanTenantRelations)
 	o.Logger.Debug("VlanTenantRelations: ", o.VlanTenantRelations)
+	o.ClusterVRFRelations = utils.ConvertStringsToRegexPairs(o.SourceConfig.ClusterVRFRelations)
+	o.Logger.Debug("ClusterVRFRelations: ", o.ClusterVRFRelations)
+	o.SubnetVRFRelations = utils.ConvertStringsToPairs(o.SourceConfig.SubnetVRFRelations)
+	o.Logger.Debug("SubnetVRFRelations: ", o.SubnetVRFRelations)
 	// Initialize the connection
 	o.Logger.Debug("Initializing oVirt source ", o.SourceConfig.Name)
 	conn, err := ovirtsdk4.NewConnectionBuilder().
@@ -75,6 +80,7 @@ func (o *OVirtSource) Init() error {
 	initFunctions := []func(*ovirtsdk4.Connection) error{
 		o.InitNetworks,
 		o.InitDisks,
+		o.InitStorageDomains,
 		o.InitDataCenters,
 		o.InitClusters,
 		o.InitHosts,
@@ -102,6 +108,9 @@ func (o *OVirtSource) Sync(nbi *inventory.NetboxInventory) error {
 		o.syncHosts,
 		o.syncVms,
 	}
+	if o.SourceConfig.AutoPrefixes {
+		syncFunctions = append(syncFunctions, o.SyncAutoPrefixes)
+	}
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		err := syncFunc(nbi)
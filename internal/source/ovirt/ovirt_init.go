@@ -50,6 +50,23 @@ func (o *OVirtSource) InitDisks(conn *ovirtsdk4.Connection) error {
 	return nil
 }
 
+func (o *OVirtSource) InitStorageDomains(conn *ovirtsdk4.Connection) error {
+	storageDomainsResponse, err := conn.SystemService().StorageDomainsService().List().Send()
+	if err != nil {
+		return fmt.Errorf("failed to get oVirt storage domains: %v", err)
+	}
+	o.StorageDomains = make(map[string]*ovirtsdk4.StorageDomain)
+	if storageDomains, ok := storageDomainsResponse.StorageDomains(); ok {
+		for _, storageDomain := range storageDomains.Slice() {
+			o.StorageDomains[storageDomain.MustId()] = storageDomain
+		}
+		o.Logger.Debug("Successfully initialized oVirt storage domains: ", o.StorageDomains)
+	} else {
+		o.Logger.Warning("Error initializing oVirt storage domains")
+	}
+	return nil
+}
+
 func (o *OVirtSource) InitDataCenters(conn *ovirtsdk4.Connection) error {
 	dataCentersResponse, err := conn.SystemService().DataCentersService().List().Send()
 	if err != nil {
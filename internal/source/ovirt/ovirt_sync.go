@@ -37,7 +37,7 @@ func (o *OVirtSource) syncNetworks(nbi *inventory.NetboxInventory) error {
 					NetboxObject: objects.NetboxObject{
 						Description: description,
 						Tags:        o.Config.SourceTags,
-						CustomFields: map[string]string{
+						CustomFields: map[string]interface{}{
 							constants.CustomFieldSourceName: o.SourceConfig.Name,
 						},
 					},
@@ -70,7 +70,7 @@ func (o *OVirtSource) syncDatacenters(nbi *inventory.NetboxInventory) error {
 			NetboxObject: objects.NetboxObject{
 				Description: description,
 				Tags:        o.Config.SourceTags,
-				CustomFields: map[string]string{
+				CustomFields: map[string]interface{}{
 					constants.CustomFieldSourceName: o.SourceConfig.Name,
 				},
 			},
@@ -89,7 +89,7 @@ func (o *OVirtSource) syncClusters(nbi *inventory.NetboxInventory) error {
 	clusterType := &objects.ClusterType{
 		NetboxObject: objects.NetboxObject{
 			Tags: o.Config.SourceTags,
-			CustomFields: map[string]string{
+			CustomFields: map[string]interface{}{
 				constants.CustomFieldSourceName: o.SourceConfig.Name,
 			},
 		},
@@ -151,7 +151,7 @@ func (o *OVirtSource) syncClusters(nbi *inventory.NetboxInventory) error {
 			NetboxObject: objects.NetboxObject{
 				Description: description,
 				Tags:        o.Config.SourceTags,
-				CustomFields: map[string]string{
+				CustomFields: map[string]interface{}{
 					constants.CustomFieldSourceName: o.SourceConfig.Name,
 				},
 			},
@@ -295,8 +295,9 @@ func (o *OVirtSource) syncHosts(nbi *inventory.NetboxInventory) error {
 			NetboxObject: objects.NetboxObject{
 				Description: hostDescription,
 				Tags:        o.Config.SourceTags,
-				CustomFields: map[string]string{
+				CustomFields: map[string]interface{}{
 					constants.CustomFieldSourceName:       o.SourceConfig.Name,
+					constants.CustomFieldSourceIDName:     hostID,
 					constants.CustomFieldHostCPUCoresName: hostCPUCores,
 					constants.CustomFieldHostMemoryName:   fmt.Sprintf("%d GB", mem),
 				},
@@ -340,7 +341,7 @@ func (o *OVirtSource) syncHostNics(nbi *inventory.NetboxInventory, ovirtHost *ov
 
 		var hostIP string
 		if hostAddress, exists := ovirtHost.Address(); exists {
-			hostIP = utils.Lookup(hostAddress)
+			hostIP = o.Lookup(hostAddress)
 		}
 
 		// First loop, we loop through all the nics and collect all the information
@@ -409,22 +410,31 @@ func (o *OVirtSource) syncHostNics(nbi *inventory.NetboxInventory, ovirtHost *ov
 		for nicID, ipv4 := range nicID2IPv4 {
 			nbNic := nicID2nic[nicID]
 			address := strings.Split(ipv4, "/")[0]
+			if !o.IsPermittedIPAddress(address) {
+				continue
+			}
+			vrf, err := o.MatchIPToVRF(nbi, ipv4, nbHost.Cluster)
+			if err != nil {
+				return fmt.Errorf("match ipv4 address to vrf: %s", err)
+			}
 			nbIPAddress, err := nbi.AddIPAddress(&objects.IPAddress{
 				NetboxObject: objects.NetboxObject{
 					Tags: o.Config.SourceTags,
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldSourceName: o.SourceConfig.Name,
 					},
 				},
 				Address:            ipv4,
+				VRF:                vrf,
 				Status:             &objects.IPAddressStatusActive, // TODO
-				DNSName:            utils.ReverseLookup(address),
+				DNSName:            o.DNSName(address, ""),
 				AssignedObjectType: objects.AssignedObjectTypeDeviceInterface,
 				AssignedObjectID:   nbNic.ID,
 			})
 			if err != nil {
 				return fmt.Errorf("add ipv4 address: %s", err)
 			}
+			o.CollectPrefix(ipv4, vrf, nbNic.UntaggedVlan, nbHost.Site, nbHost.Tenant)
 			if address == hostIP {
 				hostCopy := *nbHost
 				hostCopy.PrimaryIPv4 = nbIPAddress
@@ -437,22 +447,31 @@ func (o *OVirtSource) syncHostNics(nbi *inventory.NetboxInventory, ovirtHost *ov
 		for nicID, ipv6 := range nicID2IPv6 {
 			nbNic := nicID2nic[nicID]
 			address := strings.Split(ipv6, "/")[0]
-			_, err := nbi.AddIPAddress(&objects.IPAddress{
+			if !o.IsPermittedIPAddress(address) {
+				continue
+			}
+			vrf, err := o.MatchIPToVRF(nbi, ipv6, nbHost.Cluster)
+			if err != nil {
+				return fmt.Errorf("match ipv6 address to vrf: %s", err)
+			}
+			_, err = nbi.AddIPAddress(&objects.IPAddress{
 				NetboxObject: objects.NetboxObject{
 					Tags: o.Config.SourceTags,
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldSourceName: o.SourceConfig.Name,
 					},
 				},
 				Address:            ipv6,
+				VRF:                vrf,
 				Status:             &objects.IPAddressStatusActive, // TODO
-				DNSName:            utils.ReverseLookup(address),
+				DNSName:            o.DNSName(address, ""),
 				AssignedObjectType: objects.AssignedObjectTypeDeviceInterface,
 				AssignedObjectID:   nbNic.ID,
 			})
 			if err != nil {
 				return fmt.Errorf("add ipv6 address: %s", err)
 			}
+			o.CollectPrefix(ipv6, vrf, nbNic.UntaggedVlan, nbHost.Site, nbHost.Tenant)
 		}
 	}
 	return nil
@@ -556,7 +575,7 @@ func (o *OVirtSource) collectHostNicsData(nbHost *objects.Device, nbi *inventory
 			NetboxObject: objects.NetboxObject{
 				Tags:        o.Config.SourceTags,
 				Description: nicComment,
-				CustomFields: map[string]string{
+				CustomFields: map[string]interface{}{
 					constants.CustomFieldSourceName:   o.SourceConfig.Name,
 					constants.CustomFieldSourceIDName: nicID,
 				},
@@ -606,6 +625,8 @@ func (o *OVirtSource) collectHostNicsData(nbHost *objects.Device, nbi *inventory
 }
 
 func (o *OVirtSource) syncVms(nbi *inventory.NetboxInventory) error {
+	// Dns names of all ips reported by guest agents are resolved concurrently upfront
+	o.PrefetchDNSNames(o.vmReportedIPAddresses())
 	for vmID, ovirtVM := range o.Vms {
 		collectedVM, err := o.extractVMData(nbi, vmID, ovirtVM)
 		if err != nil {
@@ -617,6 +638,13 @@ func (o *OVirtSource) syncVms(nbi *inventory.NetboxInventory) error {
 			return fmt.Errorf("failed to sync oVirt vm: %v", err)
 		}
 
+		for _, virtualDisk := range o.extractVirtualDisks(ovirtVM) {
+			virtualDisk.VM = nbVM
+			if _, err := nbi.AddVirtualDisk(virtualDisk); err != nil {
+				return fmt.Errorf("failed to sync oVirt vm's virtual disk %s: %v", virtualDisk.Name, err)
+			}
+		}
+
 		err = o.syncVMInterfaces(nbi, ovirtVM, nbVM)
 		if err != nil {
 			return fmt.Errorf("failed to sync oVirt vm's interfaces: %v", err)
@@ -627,7 +655,7 @@ func (o *OVirtSource) syncVms(nbi *inventory.NetboxInventory) error {
 }
 
 func (o *OVirtSource) extractVMData(nbi *inventory.NetboxInventory, vmID string, vm *ovirtsdk4.Vm) (*objects.VM, error) {
-	// VM name, which is used as unique identifier for VMs in Netbox
+	// VM name, which is unique within the vm's cluster in Netbox
 	vmName, exists := vm.Name()
 	if !exists {
 		o.Logger.Warning("name for oVirt vm with id ", vmID, " is empty. VM has to have unique name to be synced to netbox. Skipping...")
@@ -690,17 +718,10 @@ func (o *OVirtSource) extractVMData(nbi *inventory.NetboxInventory, vmID string,
 		vmMemorySizeBytes = memory
 	}
 
-	// Disks
-	var vmDiskSizeBytes int64
-	if diskAttachment, exists := vm.DiskAttachments(); exists {
-		for _, diskAttachment := range diskAttachment.Slice() {
-			if ovirtDisk, exists := diskAttachment.Disk(); exists {
-				disk := o.Disks[ovirtDisk.MustId()]
-				if provisionedDiskSize, exists := disk.ProvisionedSize(); exists {
-					vmDiskSizeBytes += provisionedDiskSize
-				}
-			}
-		}
+	// Disks. Netbox requires vm's disk size to match sum of its virtual disks
+	var vmDiskSize int
+	for _, virtualDisk := range o.extractVirtualDisks(vm) {
+		vmDiskSize += virtualDisk.Size
 	}
 
 	// VM's comments
@@ -748,8 +769,9 @@ func (o *OVirtSource) extractVMData(nbi *inventory.NetboxInventory, vmID string,
 	return &objects.VM{
 		NetboxObject: objects.NetboxObject{
 			Tags: o.Config.SourceTags,
-			CustomFields: map[string]string{
-				constants.CustomFieldSourceName: o.SourceConfig.Name,
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName:   o.SourceConfig.Name,
+				constants.CustomFieldSourceIDName: vmID,
 			},
 		},
 		Name:        vmName,
@@ -762,13 +784,88 @@ func (o *OVirtSource) extractVMData(nbi *inventory.NetboxInventory, vmID string,
 		Platform:    vmPlatform,
 		Comments:    vmComments,
 		VCPUs:       vmVCPUs,
-		Memory:      int(vmMemorySizeBytes / constants.KiB / constants.KiB),               // MBs
-		Disk:        int(vmDiskSizeBytes / constants.KiB / constants.KiB / constants.KiB), // GBs
+		Memory:      int(vmMemorySizeBytes / constants.KiB / constants.KiB), // MBs
+		Disk:        vmDiskSize,                                             // GBs
 	}, nil
 }
 
+// extractVirtualDisks returns virtual disks attached to the oVirt vm, together with
+// their storage domain and provisioning type. Returned disks don't have VM set.
+func (o *OVirtSource) extractVirtualDisks(vm *ovirtsdk4.Vm) []*objects.VirtualDisk {
+	virtualDisks := make([]*objects.VirtualDisk, 0)
+	diskAttachments, exists := vm.DiskAttachments()
+	if !exists {
+		return virtualDisks
+	}
+	for _, diskAttachment := range diskAttachments.Slice() {
+		ovirtDisk, exists := diskAttachment.Disk()
+		if !exists {
+			continue
+		}
+		disk, ok := o.Disks[ovirtDisk.MustId()]
+		if !ok {
+			continue
+		}
+		diskName, exists := disk.Name()
+		if !exists {
+			diskName = disk.MustId()
+		}
+		diskCustomFields := map[string]interface{}{
+			constants.CustomFieldSourceName: o.SourceConfig.Name,
+		}
+		if sparse, exists := disk.Sparse(); exists {
+			diskCustomFields[constants.CustomFieldProvisioningTypeName] = constants.DiskProvisioningThick
+			if sparse {
+				diskCustomFields[constants.CustomFieldProvisioningTypeName] = constants.DiskProvisioningThin
+			}
+		}
+		if storageDomains, exists := disk.StorageDomains(); exists && len(storageDomains.Slice()) > 0 {
+			if storageDomain, ok := o.StorageDomains[storageDomains.Slice()[0].MustId()]; ok {
+				diskCustomFields[constants.CustomFieldDatastoreName] = storageDomain.MustName()
+			}
+		}
+		var diskSizeBytes int64
+		if provisionedDiskSize, exists := disk.ProvisionedSize(); exists {
+			diskSizeBytes = provisionedDiskSize
+		}
+		virtualDisks = append(virtualDisks, &objects.VirtualDisk{
+			NetboxObject: objects.NetboxObject{
+				Tags:         o.Config.SourceTags,
+				CustomFields: diskCustomFields,
+			},
+			Name: diskName,
+			Size: int(diskSizeBytes / constants.KiB / constants.KiB / constants.KiB), // GBs
+		})
+	}
+	return virtualDisks
+}
+
+// vmReportedIPAddresses returns ip addresses of all vms, reported by guest agents.
+func (o *OVirtSource) vmReportedIPAddresses() []string {
+	ipAddresses := []string{}
+	for _, ovirtVM := range o.Vms {
+		reportedDevices, exist := ovirtVM.ReportedDevices()
+		if !exist {
+			continue
+		}
+		for _, reportedDevice := range reportedDevices.Slice() {
+			reportedDeviceIps, exist := reportedDevice.Ips()
+			if !exist {
+				continue
+			}
+			for _, ip := range reportedDeviceIps.Slice() {
+				if ipAddress, exists := ip.Address(); exists {
+					ipAddresses = append(ipAddresses, ipAddress)
+				}
+			}
+		}
+	}
+	return ipAddresses
+}
+
 // Syncs VM's interfaces to Netbox.
 func (o *OVirtSource) syncVMInterfaces(nbi *inventory.NetboxInventory, ovirtVM *ovirtsdk4.Vm, netboxVM *objects.VM) error {
+	vmFqdn, _ := ovirtVM.Fqdn()
 	if reportedDevices, exist := ovirtVM.ReportedDevices(); exist {
 		for _, reportedDevice := range reportedDevices.Slice() {
 			if reportedDeviceType, exist := reportedDevice.Type(); exist {
@@ -781,7 +878,7 @@ func (o *OVirtSource) syncVMInterfaces(nbi *inventory.NetboxInventory, ovirtVM *
 							NetboxObject: objects.NetboxObject{
 								Tags:        o.Config.SourceTags,
 								Description: reportedDevice.MustDescription(),
-								CustomFields: map[string]string{
+								CustomFields: map[string]interface{}{
 									constants.CustomFieldSourceName: o.SourceConfig.Name,
 								},
 							},
@@ -808,12 +905,12 @@ func (o *OVirtSource) syncVMInterfaces(nbi *inventory.NetboxInventory, ovirtVM *
 									if err != nil {
 										return fmt.Errorf("failed to match oVirt vm's interface %s to a Netbox interface filter: %v", vmInterface.Name, err)
 									}
-									if !valid {
+									if !valid || !o.IsPermittedIPAddress(ipAddress) {
 										continue
 									}
 
-									// Try to do reverse lookup of IP to get DNS name
-									hostname := utils.ReverseLookup(ipAddress)
+									// Get DNS name of the IP, vm's fqdn is reported by the guest agent
+									hostname := o.DNSName(ipAddress, vmFqdn)
 
 									// Set default mask
 									var ipMask string
@@ -828,14 +925,20 @@ func (o *OVirtSource) syncVMInterfaces(nbi *inventory.NetboxInventory, ovirtVM *
 										}
 									}
 
+									vrf, err := o.MatchIPToVRF(nbi, ipAddress, netboxVM.Cluster)
+									if err != nil {
+										o.Logger.Warningf("match ip %s to vrf: %s", ipAddress, err)
+										continue
+									}
 									newIPAddress, err := nbi.AddIPAddress(&objects.IPAddress{
 										NetboxObject: objects.NetboxObject{
 											Tags: o.Config.SourceTags,
-											CustomFields: map[string]string{
+											CustomFields: map[string]interface{}{
 												constants.CustomFieldSourceName: o.SourceConfig.Name,
 											},
 										},
 										Address:            ipAddress + ipMask,
+										VRF:                vrf,
 										Tenant:             netboxVM.Tenant,
 										Status:             &objects.IPAddressStatusActive,
 										DNSName:            hostname,
@@ -851,7 +954,7 @@ func (o *OVirtSource) syncVMInterfaces(nbi *inventory.NetboxInventory, ovirtVM *
 
 									// Check if ip is primary
 									if ipVersion == "v4" {
-										vmIP := utils.Lookup(netboxVM.Name)
+										vmIP := o.Lookup(netboxVM.Name)
 										if vmIP != "" && vmIP == ipAddress || netboxVM.PrimaryIPv4 == nil {
 											vmCopy := *netboxVM
 											vmCopy.PrimaryIPv4 = newIPAddress
@@ -0,0 +1,92 @@
+package redfish
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// Source represents a redfish source, which consists of multiple BMCs
+// (e.g. iDRAC, iLO, XCC), that share the same credentials.
+type Source struct {
+	common.Config
+
+	// Redfish fetched data. Initialized in init and sync functions.
+	Servers []*ServerData
+	// Set of BMC endpoints that have already been queried.
+	Endpoints map[string]bool
+
+	// User defined relations
+	HostSiteRelations   map[string]string
+	HostTenantRelations map[string]string
+}
+
+// ServerData is all the data collected from one redfish computer system.
+type ServerData struct {
+	Endpoint           string
+	System             ComputerSystem
+	Manager            Manager
+	Processors         []Processor
+	EthernetInterfaces []EthernetInterface
+	PowerSupplies      []PowerSupply
+}
+
+// Function that initializes state from configured BMCs to local storage.
+func (rs *Source) Init() error {
+	// Initialize regex relations
+	rs.Logger.Debug("Initializing regex relations for redfish source ", rs.SourceConfig.Name)
+	rs.HostSiteRelations = utils.ConvertStringsToRegexPairs(rs.SourceConfig.HostSiteRelations)
+	rs.Logger.Debug("HostSiteRelations: ", rs.HostSiteRelations)
+	rs.HostTenantRelations = utils.ConvertStringsToRegexPairs(rs.SourceConfig.HostTenantRelations)
+	rs.Logger.Debug("HostTenantRelations: ", rs.HostTenantRelations)
+
+	rs.Servers = make([]*ServerData, 0)
+	rs.Endpoints = make(map[string]bool)
+	// Unreachable BMCs are skipped, so one failing BMC doesn't prevent syncing the others
+	endpoints := append([]string{rs.SourceConfig.Hostname}, rs.SourceConfig.BMCHostnames...)
+	var initErr error
+	initialized := 0
+	for _, endpoint := range endpoints {
+		startTime := time.Now()
+		if err := rs.InitServers(endpoint); err != nil {
+			initErr = fmt.Errorf("redfish initialization failure for %s: %v", endpoint, err)
+			rs.Logger.Errorf("%s. Skipping...", initErr)
+			continue
+		}
+		initialized++
+		duration := time.Since(startTime)
+		rs.Logger.Infof("Successfully initialized BMC %s in %f seconds", endpoint, duration.Seconds())
+	}
+	if initialized == 0 {
+		return initErr
+	}
+	return nil
+}
+
+// Function that syncs all data from BMCs to Netbox.
+func (rs *Source) Sync(nbi *inventory.NetboxInventory) error {
+	syncFunctions := []func(*inventory.NetboxInventory) error{
+		rs.discoverServers,
+		rs.syncServers,
+	}
+	for _, syncFunc := range syncFunctions {
+		startTime := time.Now()
+		err := syncFunc(nbi)
+		if err != nil {
+			return err
+		}
+		duration := time.Since(startTime)
+		rs.Logger.Infof("Successfully synced %s in %f seconds", utils.ExtractFunctionName(syncFunc), duration.Seconds())
+	}
+	return nil
+}
+
+func (rs *Source) newClient(endpoint string) *apiClient {
+	baseURL := fmt.Sprintf("%s://%s", rs.SourceConfig.HTTPScheme, net.JoinHostPort(endpoint, strconv.Itoa(rs.SourceConfig.Port)))
+	return newAPIClient(baseURL, rs.SourceConfig.Username, rs.SourceConfig.Password, rs.SourceConfig.ValidateCert)
+}
@@ -0,0 +1,162 @@
+package redfish
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+)
+
+// Minimal client for Redfish API of one BMC.
+type apiClient struct {
+	BaseURL    string
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+}
+
+func newAPIClient(baseURL string, username string, password string, validateCert bool) *apiClient {
+	return &apiClient{
+		BaseURL:  baseURL,
+		Username: username,
+		Password: password,
+		HTTPClient: &http.Client{
+			Timeout: time.Second * constants.DefaultTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: !validateCert}, //nolint:gosec
+			},
+		},
+	}
+}
+
+// get fetches redfish resource on odataID path (e.g. /redfish/v1/Systems) and
+// unmarshals it into v.
+func (c *apiClient) get(odataID string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.BaseURL+odataID, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.Username, c.Password)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d for %s", resp.StatusCode, odataID)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unmarshal %s: %s", odataID, err)
+	}
+	return nil
+}
+
+// getMembers fetches all members of redfish collection on odataID path.
+func getMembers[T any](c *apiClient, odataID string) ([]T, error) {
+	var collection Collection
+	if err := c.get(odataID, &collection); err != nil {
+		return nil, err
+	}
+	members := make([]T, 0, len(collection.Members))
+	for _, member := range collection.Members {
+		var m T
+		if err := c.get(member.ODataID, &m); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+// Link to another redfish resource.
+type Link struct {
+	ODataID string `json:"@odata.id"`
+}
+
+type Collection struct {
+	Members []Link `json:"Members"`
+}
+
+type Status struct {
+	State  string `json:"State"`
+	Health string `json:"Health"`
+}
+
+type ComputerSystem struct {
+	ID               string `json:"Id"`
+	HostName         string `json:"HostName"`
+	Manufacturer     string `json:"Manufacturer"`
+	Model            string `json:"Model"`
+	SKU              string `json:"SKU"`
+	SerialNumber     string `json:"SerialNumber"`
+	AssetTag         string `json:"AssetTag"`
+	BiosVersion      string `json:"BiosVersion"`
+	PowerState       string `json:"PowerState"`
+	ProcessorSummary struct {
+		Count int    `json:"Count"`
+		Model string `json:"Model"`
+	} `json:"ProcessorSummary"`
+	MemorySummary struct {
+		TotalSystemMemoryGiB float64 `json:"TotalSystemMemoryGiB"`
+	} `json:"MemorySummary"`
+	Processors         Link `json:"Processors"`
+	EthernetInterfaces Link `json:"EthernetInterfaces"`
+	Links              struct {
+		Chassis   []Link `json:"Chassis"`
+		ManagedBy []Link `json:"ManagedBy"`
+	} `json:"Links"`
+}
+
+type Processor struct {
+	ID           string `json:"Id"`
+	Model        string `json:"Model"`
+	TotalCores   int    `json:"TotalCores"`
+	TotalThreads int    `json:"TotalThreads"`
+}
+
+type EthernetInterface struct {
+	ID                  string `json:"Id"`
+	Name                string `json:"Name"`
+	Description         string `json:"Description"`
+	MACAddress          string `json:"MACAddress"`
+	PermanentMACAddress string `json:"PermanentMACAddress"`
+	SpeedMbps           int    `json:"SpeedMbps"`
+	MTUSize             int    `json:"MTUSize"`
+	InterfaceEnabled    *bool  `json:"InterfaceEnabled"`
+	LinkStatus          string `json:"LinkStatus"`
+}
+
+type Manager struct {
+	ID              string `json:"Id"`
+	Model           string `json:"Model"`
+	FirmwareVersion string `json:"FirmwareVersion"`
+}
+
+type PowerSupply struct {
+	MemberID           string  `json:"MemberId"`
+	Name               string  `json:"Name"`
+	Model              string  `json:"Model"`
+	SerialNumber       string  `json:"SerialNumber"`
+	FirmwareVersion    string  `json:"FirmwareVersion"`
+	PowerCapacityWatts float64 `json:"PowerCapacityWatts"`
+	Status             Status  `json:"Status"`
+}
+
+type Chassis struct {
+	ID    string `json:"Id"`
+	Power Link   `json:"Power"`
+}
+
+type Power struct {
+	PowerSupplies []PowerSupply `json:"PowerSupplies"`
+}
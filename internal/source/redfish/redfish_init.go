@@ -0,0 +1,53 @@
+package redfish
+
+import (
+	"fmt"
+)
+
+// Path of the systems collection on every redfish service.
+const systemsPath = "/redfish/v1/Systems"
+
+// InitServers collects data about all computer systems managed by BMC on endpoint.
+func (rs *Source) InitServers(endpoint string) error {
+	rs.Endpoints[endpoint] = true
+	client := rs.newClient(endpoint)
+	systems, err := getMembers[ComputerSystem](client, systemsPath)
+	if err != nil {
+		return fmt.Errorf("systems: %s", err)
+	}
+	for _, system := range systems {
+		server := &ServerData{Endpoint: endpoint, System: system}
+		if system.Processors.ODataID != "" {
+			server.Processors, err = getMembers[Processor](client, system.Processors.ODataID)
+			if err != nil {
+				return fmt.Errorf("processors: %s", err)
+			}
+		}
+		if system.EthernetInterfaces.ODataID != "" {
+			server.EthernetInterfaces, err = getMembers[EthernetInterface](client, system.EthernetInterfaces.ODataID)
+			if err != nil {
+				return fmt.Errorf("ethernet interfaces: %s", err)
+			}
+		}
+		if len(system.Links.ManagedBy) > 0 {
+			if err := client.get(system.Links.ManagedBy[0].ODataID, &server.Manager); err != nil {
+				return fmt.Errorf("manager: %s", err)
+			}
+		}
+		if len(system.Links.Chassis) > 0 {
+			var chassis Chassis
+			if err := client.get(system.Links.Chassis[0].ODataID, &chassis); err != nil {
+				return fmt.Errorf("chassis: %s", err)
+			}
+			if chassis.Power.ODataID != "" {
+				var power Power
+				if err := client.get(chassis.Power.ODataID, &power); err != nil {
+					return fmt.Errorf("power: %s", err)
+				}
+				server.PowerSupplies = power.PowerSupplies
+			}
+		}
+		rs.Servers = append(rs.Servers, server)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package redfish
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// discoverServers queries BMCs on ip addresses of netbox devices' management
+// interfaces (mgmt_only), that are part of bmcDiscoverySubnets. Ip addresses of other
+// interfaces (e.g. host os interfaces) are never probed. Addresses without redfish
+// service are skipped.
+func (rs *Source) discoverServers(nbi *inventory.NetboxInventory) error {
+	if len(rs.SourceConfig.BMCDiscoverySubnets) == 0 {
+		return nil
+	}
+	mgmtIPAddresses, err := nbi.GetMgmtIPAddresses(rs.SourceConfig.BMCDiscoverySubnets)
+	if err != nil {
+		return fmt.Errorf("discovery: %s", err)
+	}
+	addresses := make([]string, 0)
+	for _, ipAddress := range mgmtIPAddresses {
+		address := strings.Split(ipAddress.Address, "/")[0]
+		if !rs.Endpoints[address] && !slices.Contains(addresses, address) {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+	for _, address := range addresses {
+		if err := rs.InitServers(address); err != nil {
+			rs.Logger.Debugf("no redfish service found on %s: %s", address, err)
+			continue
+		}
+		rs.Logger.Infof("Discovered BMC on %s", address)
+	}
+	return nil
+}
+
+func (rs *Source) syncServers(nbi *inventory.NetboxInventory) error {
+	for _, server := range rs.Servers {
+		nbDevice, err := rs.syncServer(nbi, server)
+		if err != nil {
+			return fmt.Errorf("server %s: %s", server.Endpoint, err)
+		}
+		if nbDevice == nil {
+			continue
+		}
+		if err := rs.syncEthernetInterfaces(nbi, server, nbDevice); err != nil {
+			return fmt.Errorf("server %s ethernet interfaces: %s", server.Endpoint, err)
+		}
+		if err := rs.syncPowerSupplies(nbi, server, nbDevice); err != nil {
+			return fmt.Errorf("server %s power supplies: %s", server.Endpoint, err)
+		}
+	}
+	return nil
+}
+
+// findDevice returns existing netbox device with the same serial number or asset tag.
+func findDevice(nbi *inventory.NetboxInventory, serialNumber string, assetTag string) *objects.Device {
+	if serialNumber == "" && assetTag == "" {
+		return nil
+	}
+	device, _ := nbi.GetDevice(&objects.Device{SerialNumber: serialNumber, AssetTag: assetTag})
+	return device
+}
+
+// syncServer enriches existing device (matched by serial or asset tag) with
+// hardware data from the BMC, or creates a new device if it doesn't exist yet.
+// It returns nil if the device can't be matched to any site.
+//
+// Existing device stays owned by the source that added it (e.g. hypervisor source),
+// so its source and source_id are kept. Redfish owns only bios version and bmc firmware
+// custom fields of such device, fills in cpu cores and memory if they are not set yet,
+// and adds its source tags. All other fields are kept as they are, so redfish never
+// overwrites data of the device owner, regardless of sourcePriority.
+func (rs *Source) syncServer(nbi *inventory.NetboxInventory, server *ServerData) (*objects.Device, error) {
+	system := server.System
+	var newDevice *objects.Device
+	if existingDevice := findDevice(nbi, system.SerialNumber, system.AssetTag); existingDevice != nil {
+		device := *existingDevice
+		device.Tags = mergeTags(existingDevice.Tags, rs.Config.SourceTags)
+		device.CustomFields = maps.Clone(existingDevice.CustomFields)
+		if device.CustomFields == nil {
+			device.CustomFields = map[string]interface{}{}
+		}
+		newDevice = &device
+	} else {
+		var err error
+		newDevice, err = rs.newServerDevice(nbi, server)
+		if err != nil || newDevice == nil {
+			return nil, err
+		}
+	}
+
+	cpuCores := 0
+	for _, processor := range server.Processors {
+		cpuCores += processor.TotalCores
+	}
+	if _, ok := newDevice.CustomFields[constants.CustomFieldHostCPUCoresName]; !ok && cpuCores > 0 {
+		newDevice.CustomFields[constants.CustomFieldHostCPUCoresName] = fmt.Sprintf("%d", cpuCores)
+	}
+	if _, ok := newDevice.CustomFields[constants.CustomFieldHostMemoryName]; !ok && system.MemorySummary.TotalSystemMemoryGiB > 0 {
+		newDevice.CustomFields[constants.CustomFieldHostMemoryName] = fmt.Sprintf("%d GB", int(system.MemorySummary.TotalSystemMemoryGiB))
+	}
+	if system.BiosVersion != "" {
+		newDevice.CustomFields[constants.CustomFieldBIOSVersionName] = system.BiosVersion
+	}
+	if server.Manager.FirmwareVersion != "" {
+		newDevice.CustomFields[constants.CustomFieldBMCFirmwareName] = server.Manager.FirmwareVersion
+	}
+
+	nbDevice, err := nbi.AddDevice(newDevice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add redfish server %s with error: %v", newDevice.Name, err)
+	}
+	return nbDevice, nil
+}
+
+// newServerDevice returns a new device of the server, that doesn't exist in netbox yet.
+// It returns nil if the device can't be matched to any site.
+func (rs *Source) newServerDevice(nbi *inventory.NetboxInventory, server *ServerData) (*objects.Device, error) {
+	system := server.System
+	manufacturerName, err := utils.MatchStringToValue(system.Manufacturer, objects.ManufacturerMap)
+	if err != nil {
+		return nil, fmt.Errorf("matching manufacturer: %s", err)
+	}
+	if manufacturerName == "" {
+		manufacturerName = system.Manufacturer
+	}
+	if manufacturerName == "" {
+		manufacturerName = constants.DefaultManufacturer
+	}
+	manufacturer, err := nbi.AddManufacturer(&objects.Manufacturer{
+		Name: manufacturerName,
+		Slug: utils.Slugify(manufacturerName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed adding redfish Manufacturer %v with error: %s", manufacturer, err)
+	}
+
+	model := system.Model
+	if model == "" {
+		model = constants.DefaultModel // Model is also required for adding device type into netbox
+	}
+	deviceType, err := nbi.AddDeviceType(&objects.DeviceType{
+		Manufacturer: manufacturer,
+		Model:        model,
+		Slug:         utils.Slugify(model),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed adding redfish DeviceType %v with error: %s", deviceType, err)
+	}
+
+	newDevice := &objects.Device{
+		NetboxObject: objects.NetboxObject{
+			Tags: rs.Config.SourceTags,
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName: rs.SourceConfig.Name,
+			},
+		},
+		Name:         system.HostName,
+		SerialNumber: system.SerialNumber,
+		AssetTag:     system.AssetTag,
+		DeviceType:   deviceType,
+		DeviceRole:   nbi.DeviceRolesIndexByName["Server"],
+		Status:       &objects.DeviceStatusOffline,
+	}
+	if newDevice.Name == "" {
+		newDevice.Name = server.Endpoint
+	}
+	if system.PowerState == "On" {
+		newDevice.Status = &objects.DeviceStatusActive
+	}
+	newDevice.Site, err = common.MatchHostToSite(nbi, newDevice.Name, rs.HostSiteRelations)
+	if err != nil {
+		return nil, fmt.Errorf("hostSite: %s", err)
+	}
+	if newDevice.Site == nil {
+		rs.Logger.Warningf("redfish server %s can't be matched to any site, so it will be skipped", newDevice.Name)
+		return nil, nil
+	}
+	newDevice.Tenant, err = common.MatchHostToTenant(nbi, newDevice.Name, rs.HostTenantRelations)
+	if err != nil {
+		return nil, fmt.Errorf("hostTenant: %s", err)
+	}
+	return newDevice, nil
+}
+
+// mergeTags returns tags extended with extraTags, that are not in tags yet.
+func mergeTags(tags []*objects.Tag, extraTags []*objects.Tag) []*objects.Tag {
+	merged := slices.Clone(tags)
+	for _, extraTag := range extraTags {
+		if !slices.ContainsFunc(merged, func(tag *objects.Tag) bool { return tag.ID == extraTag.ID }) {
+			merged = append(merged, extraTag)
+		}
+	}
+	return merged
+}
+
+// syncEthernetInterfaces adds server's nics to netbox. If device already has an
+// interface with the same mac address (e.g. vmnic0 from vmware), that interface is
+// enriched instead, so we don't end up with duplicates.
+func (rs *Source) syncEthernetInterfaces(nbi *inventory.NetboxInventory, server *ServerData, nbDevice *objects.Device) error {
+	for _, ethInterface := range server.EthernetInterfaces {
+		mac := ethInterface.PermanentMACAddress
+		if mac == "" {
+			mac = ethInterface.MACAddress
+		}
+		mac = strings.ToUpper(mac)
+
+		intName := ethInterface.ID
+		for name, nbInterface := range nbi.GetDeviceInterfaces(nbDevice) {
+			if mac != "" && strings.EqualFold(nbInterface.MAC, mac) {
+				intName = name
+				break
+			}
+		}
+
+		intSpeed := objects.InterfaceSpeed(ethInterface.SpeedMbps * constants.KB) // Value is in Mbps, we convert to kbps
+		intType := objects.IfaceSpeed2IfaceType[intSpeed]
+		if intType == nil {
+			intType = &objects.OtherInterfaceType
+		}
+		intEnabled := ethInterface.LinkStatus == "LinkUp"
+		if ethInterface.InterfaceEnabled != nil {
+			intEnabled = *ethInterface.InterfaceEnabled
+		}
+
+		_, err := nbi.AddInterface(&objects.Interface{
+			NetboxObject: objects.NetboxObject{
+				Tags:        rs.Config.SourceTags,
+				Description: ethInterface.Description,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: rs.SourceConfig.Name,
+				},
+			},
+			Device: nbDevice,
+			Name:   intName,
+			Status: intEnabled,
+			Type:   intType,
+			Speed:  intSpeed,
+			MTU:    ethInterface.MTUSize,
+			MAC:    mac,
+		})
+		if err != nil {
+			return fmt.Errorf("adding interface %s: %s", intName, err)
+		}
+	}
+	return nil
+}
+
+// syncPowerSupplies adds server's power supplies as device's power ports.
+func (rs *Source) syncPowerSupplies(nbi *inventory.NetboxInventory, server *ServerData, nbDevice *objects.Device) error {
+	for _, psu := range server.PowerSupplies {
+		// Redfish reports absent power supply slots as well
+		if psu.Status.State == "Absent" {
+			continue
+		}
+		psuName := psu.Name
+		if psuName == "" {
+			psuName = fmt.Sprintf("PSU %s", psu.MemberID)
+		}
+		descriptionParts := []string{}
+		if psu.Model != "" {
+			descriptionParts = append(descriptionParts, fmt.Sprintf("Model: %s", psu.Model))
+		}
+		if psu.SerialNumber != "" {
+			descriptionParts = append(descriptionParts, fmt.Sprintf("Serial: %s", psu.SerialNumber))
+		}
+		if psu.FirmwareVersion != "" {
+			descriptionParts = append(descriptionParts, fmt.Sprintf("Firmware: %s", psu.FirmwareVersion))
+		}
+		_, err := nbi.AddPowerPort(&objects.PowerPort{
+			NetboxObject: objects.NetboxObject{
+				Tags:        rs.Config.SourceTags,
+				Description: strings.Join(descriptionParts, ", "),
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: rs.SourceConfig.Name,
+				},
+			},
+			Device:      nbDevice,
+			Name:        psuName,
+			MaximumDraw: int(psu.PowerCapacityWatts),
+		})
+		if err != nil {
+			return fmt.Errorf("adding power port %s: %s", psuName, err)
+		}
+	}
+	return nil
+}
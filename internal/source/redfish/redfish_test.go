@@ -0,0 +1,451 @@
+package redfish
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory/inventorytest"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+)
+
+// Mocked redfish resources of a single server, indexed by their @odata.id.
+var mockResources = map[string]string{
+	"/redfish/v1/Systems": `{"Members": [{"@odata.id": "/redfish/v1/Systems/System.Embedded.1"}]}`,
+	"/redfish/v1/Systems/System.Embedded.1": `{
+		"Id": "System.Embedded.1", "HostName": "esxi01.example.com", "Manufacturer": "Dell Inc.", "Model": "PowerEdge R650",
+		"SerialNumber": "7XYZ123", "AssetTag": "", "BiosVersion": "1.10.2", "PowerState": "On",
+		"ProcessorSummary": {"Count": 2, "Model": "Intel(R) Xeon(R) Gold 6338"},
+		"MemorySummary": {"TotalSystemMemoryGiB": 512},
+		"Processors": {"@odata.id": "/redfish/v1/Systems/System.Embedded.1/Processors"},
+		"EthernetInterfaces": {"@odata.id": "/redfish/v1/Systems/System.Embedded.1/EthernetInterfaces"},
+		"Links": {"Chassis": [{"@odata.id": "/redfish/v1/Chassis/System.Embedded.1"}], "ManagedBy": [{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1"}]}
+	}`,
+	"/redfish/v1/Systems/System.Embedded.1/Processors":              `{"Members": [{"@odata.id": "/redfish/v1/Systems/System.Embedded.1/Processors/CPU.Socket.1"}, {"@odata.id": "/redfish/v1/Systems/System.Embedded.1/Processors/CPU.Socket.2"}]}`,
+	"/redfish/v1/Systems/System.Embedded.1/Processors/CPU.Socket.1": `{"Id": "CPU.Socket.1", "TotalCores": 32, "TotalThreads": 64}`,
+	"/redfish/v1/Systems/System.Embedded.1/Processors/CPU.Socket.2": `{"Id": "CPU.Socket.2", "TotalCores": 32, "TotalThreads": 64}`,
+	"/redfish/v1/Systems/System.Embedded.1/EthernetInterfaces":      `{"Members": [{"@odata.id": "/redfish/v1/Systems/System.Embedded.1/EthernetInterfaces/NIC.Integrated.1-1-1"}]}`,
+	"/redfish/v1/Systems/System.Embedded.1/EthernetInterfaces/NIC.Integrated.1-1-1": `{
+		"Id": "NIC.Integrated.1-1-1", "MACAddress": "b4:96:91:00:00:01", "PermanentMACAddress": "b4:96:91:00:00:01", "SpeedMbps": 25000, "LinkStatus": "LinkUp"
+	}`,
+	"/redfish/v1/Managers/iDRAC.Embedded.1": `{"Id": "iDRAC.Embedded.1", "FirmwareVersion": "6.10.30.00"}`,
+	"/redfish/v1/Chassis/System.Embedded.1": `{"Id": "System.Embedded.1", "Power": {"@odata.id": "/redfish/v1/Chassis/System.Embedded.1/Power"}}`,
+	"/redfish/v1/Chassis/System.Embedded.1/Power": `{"PowerSupplies": [
+		{"MemberId": "PSU.Slot.1", "Name": "PS1 Status", "Model": "PWR SPLY,1400W", "SerialNumber": "CNDED001", "PowerCapacityWatts": 1400, "Status": {"State": "Enabled"}},
+		{"MemberId": "PSU.Slot.2", "Name": "PS2 Status", "Status": {"State": "Absent"}}
+	]}`,
+}
+
+func newMockServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "root" || password != "calvin" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resource, ok := mockResources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(resource))
+	}))
+}
+
+func newTestSource(t *testing.T, serverURL string, password string) *Source {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Source{
+		Config: common.Config{
+			Logger: testLogger,
+			SourceConfig: &parser.SourceConfig{
+				Name:       "redfish-test",
+				HTTPScheme: parser.HTTP,
+				Hostname:   u.Hostname(),
+				Port:       port,
+				Username:   "root",
+				Password:   password,
+			},
+		},
+	}
+}
+
+func TestSync(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+	rs := newTestSource(t, server.URL, "calvin")
+	if err := rs.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	rs.HostSiteRelations = map[string]string{".*": "MySite"}
+	nbi, _ := inventorytest.NewInventory(t)
+	site, err := nbi.AddSite(&objects.Site{Name: "MySite", Slug: "mysite"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rs.Sync(nbi); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	nbDevice := nbi.DevicesIndexByNameAndSiteID["esxi01.example.com"][site.ID]
+	if nbDevice == nil {
+		t.Fatalf("server esxi01.example.com was not synced")
+	}
+	if nbDevice.CustomFields[constants.CustomFieldBMCFirmwareName] != "6.10.30.00" {
+		t.Errorf("bmc firmware = %v, want 6.10.30.00", nbDevice.CustomFields[constants.CustomFieldBMCFirmwareName])
+	}
+	nbInterface := nbi.InterfacesIndexByDeviceIDAndName[nbDevice.ID]["NIC.Integrated.1-1-1"]
+	if nbInterface == nil || nbInterface.MAC != "B4:96:91:00:00:01" || nbInterface.Speed != 25000*constants.KB {
+		t.Errorf("interface NIC.Integrated.1-1-1 = %v, want 25G interface with mac B4:96:91:00:00:01", nbInterface)
+	}
+	// Absent power supply slot is skipped
+	powerPorts := nbi.PowerPortsIndexByDeviceIDAndName[nbDevice.ID]
+	if len(powerPorts) != 1 || powerPorts["PS1 Status"] == nil || powerPorts["PS1 Status"].MaximumDraw != 1400 {
+		t.Errorf("power ports = %v, want only PS1 Status with maximum draw 1400", powerPorts)
+	}
+}
+
+func TestInitWrongCredentials(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	rs := newTestSource(t, server.URL, "wrong")
+	if err := rs.Init(); err == nil {
+		t.Errorf("Init() expected error for wrong credentials")
+	}
+}
+
+func TestInitSkipsFailingBMC(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	// Mock server listens only on 127.0.0.1, so the second BMC is unreachable
+	rs := newTestSource(t, server.URL, "calvin")
+	rs.SourceConfig.BMCHostnames = []string{"127.0.0.2"}
+	if err := rs.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if len(rs.Servers) != 1 {
+		t.Errorf("got %d servers, want 1", len(rs.Servers))
+	}
+}
+
+func TestFindDevice(t *testing.T) {
+	nbi, _ := inventorytest.NewInventory(t)
+	device1 := addTestDevice(t, nbi, &objects.Device{Name: "esxi01", SerialNumber: "7XYZ123"})
+	device2 := addTestDevice(t, nbi, &objects.Device{Name: "esxi02", AssetTag: "ASSET-2"})
+	tests := []struct {
+		name         string
+		serialNumber string
+		assetTag     string
+		expected     *objects.Device
+	}{
+		{name: "Match by serial number", serialNumber: "7xyz123", expected: device1},
+		{name: "Match by asset tag", serialNumber: "OTHER", assetTag: "ASSET-2", expected: device2},
+		{name: "No match", serialNumber: "OTHER", assetTag: "OTHER", expected: nil},
+		{name: "Empty serial and asset tag", expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findDevice(nbi, tt.serialNumber, tt.assetTag); got != tt.expected {
+				t.Errorf("findDevice() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// newTestServer returns data of a server collected from the mocked BMC.
+func newTestServer(t *testing.T) *ServerData {
+	t.Helper()
+	server := newMockServer()
+	defer server.Close()
+	rs := newTestSource(t, server.URL, "calvin")
+	if err := rs.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return rs.Servers[0]
+}
+
+// addTestDevice adds a device, that already exists in netbox, to the inventory.
+func addTestDevice(t *testing.T, nbi *inventory.NetboxInventory, device *objects.Device) *objects.Device {
+	t.Helper()
+	site, err := nbi.AddSite(&objects.Site{Name: "MySite", Slug: "mysite"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manufacturer, err := nbi.AddManufacturer(&objects.Manufacturer{Name: "VMware", Slug: "vmware"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deviceType, err := nbi.AddDeviceType(&objects.DeviceType{Manufacturer: manufacturer, Model: "PowerEdge R650", Slug: "poweredge-r650"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	device.Site = site
+	device.DeviceType = deviceType
+	nbDevice, err := nbi.AddDevice(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return nbDevice
+}
+
+func TestSyncServerEnrichesExistingDevice(t *testing.T) {
+	nbi, fake := inventorytest.NewInventory(t)
+	vmwareTag, err := nbi.AddTag(&objects.Tag{Name: "Source: vmware", Slug: "source-vmware"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	existingDevice := addTestDevice(t, nbi, &objects.Device{
+		NetboxObject: objects.NetboxObject{
+			Tags: []*objects.Tag{vmwareTag},
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName:       "vmware",
+				constants.CustomFieldSourceIDName:     "host-1",
+				constants.CustomFieldHostCPUCoresName: "48",
+			},
+		},
+		Name:         "esxi01",
+		SerialNumber: "7XYZ123",
+		Status:       &objects.DeviceStatusActive,
+	})
+	rs := newTestSource(t, "http://127.0.0.1:1", "calvin")
+	redfishTag, err := nbi.AddTag(&objects.Tag{Name: "Source: redfish-test", Slug: "source-redfish-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs.SourceTags = []*objects.Tag{redfishTag}
+	server := newTestServer(t)
+
+	// Second sync must not patch the device again, so tags must not be duplicated
+	for i := 0; i < 2; i++ {
+		nbDevice, err := rs.syncServer(nbi, server)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nbDevice.ID != existingDevice.ID || nbDevice.Name != "esxi01" {
+			t.Fatalf("syncServer() = device %d %s, want existing device %d", nbDevice.ID, nbDevice.Name, existingDevice.ID)
+		}
+	}
+	patches := fake.Patches(service.DevicesAPIPath, existingDevice.ID)
+	if len(patches) != 1 {
+		t.Fatalf("device was patched %d times, want once: %v", len(patches), patches)
+	}
+	// Only custom fields owned by redfish and missing hardware custom fields are patched
+	expectedCustomFields := map[string]interface{}{
+		constants.CustomFieldHostMemoryName:  "512 GB",
+		constants.CustomFieldBIOSVersionName: "1.10.2",
+		constants.CustomFieldBMCFirmwareName: "6.10.30.00",
+	}
+	if customFields := patches[0]["custom_fields"]; !reflect.DeepEqual(customFields, expectedCustomFields) {
+		t.Errorf("patched custom fields = %v, want %v", customFields, expectedCustomFields)
+	}
+	for _, field := range []string{"name", "site", "device_type", "serial", "status"} {
+		if _, ok := patches[0][field]; ok {
+			t.Errorf("field %s of the existing device was patched: %v", field, patches[0])
+		}
+	}
+}
+
+func TestSyncServerCreatesDevice(t *testing.T) {
+	nbi, _ := inventorytest.NewInventory(t)
+	site, err := nbi.AddSite(&objects.Site{Name: "MySite", Slug: "mysite"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := newTestSource(t, "http://127.0.0.1:1", "calvin")
+	rs.HostSiteRelations = map[string]string{".*": "MySite"}
+
+	nbDevice, err := rs.syncServer(nbi, newTestServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nbDevice == nil || nbDevice.Name != "esxi01.example.com" || nbDevice.Site.ID != site.ID || nbDevice.SerialNumber != "7XYZ123" {
+		t.Fatalf("syncServer() = %v, want new device esxi01.example.com", nbDevice)
+	}
+	if source := nbDevice.CustomFields[constants.CustomFieldSourceName]; source != "redfish-test" {
+		t.Errorf("source of the new device = %v, want redfish-test", source)
+	}
+	if cpuCores := nbDevice.CustomFields[constants.CustomFieldHostCPUCoresName]; cpuCores != "64" {
+		t.Errorf("cpu cores of the new device = %v, want 64", cpuCores)
+	}
+
+	// Servers that can't be matched to any site are skipped
+	rs.HostSiteRelations = map[string]string{}
+	server := newTestServer(t)
+	server.System.SerialNumber = "OTHER"
+	if nbDevice, err := rs.syncServer(nbi, server); err != nil || nbDevice != nil {
+		t.Errorf("syncServer() = %v, %v, want server without site to be skipped", nbDevice, err)
+	}
+}
+
+func TestDiscoverServers(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+	// In scoped init, interfaces and ip addresses are outside of the scope, so they are created
+	// directly in Netbox and are reachable only through lookup
+	for _, scopedInit := range []bool{false, true} {
+		rs := newTestSource(t, server.URL, "calvin")
+		rs.SourceConfig.BMCDiscoverySubnets = []string{"127.0.0.0/8"}
+		rs.Endpoints = map[string]bool{}
+
+		nbi, _ := inventorytest.NewInventory(t)
+		device := addTestDevice(t, nbi, &objects.Device{Name: "esxi01"})
+		nbi.NetboxConfig.ScopedInit = scopedInit
+		for _, intf := range []struct {
+			name     string
+			mgmtOnly bool
+			address  string
+		}{
+			{name: "iDRAC", mgmtOnly: true, address: "127.0.0.1/8"},
+			{name: "vmk0", address: "127.0.0.2/8"},
+		} {
+			nbInterface := &objects.Interface{Device: device, Name: intf.name, Type: &objects.OtherInterfaceType, MgmtOnly: intf.mgmtOnly}
+			ipAddress := &objects.IPAddress{Address: intf.address, AssignedObjectType: objects.AssignedObjectTypeDeviceInterface}
+			var err error
+			if scopedInit {
+				if nbInterface, err = service.Create(nbi.NetboxAPI, nbInterface); err != nil {
+					t.Fatal(err)
+				}
+				ipAddress.AssignedObjectID = nbInterface.ID
+				_, err = service.Create(nbi.NetboxAPI, ipAddress)
+			} else {
+				if nbInterface, err = nbi.AddInterface(nbInterface); err != nil {
+					t.Fatal(err)
+				}
+				ipAddress.AssignedObjectID = nbInterface.ID
+				_, err = nbi.AddIPAddress(ipAddress)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := rs.discoverServers(nbi); err != nil {
+			t.Fatal(err)
+		}
+		// Only ip address of the management interface is probed
+		if !rs.Endpoints["127.0.0.1"] || rs.Endpoints["127.0.0.2"] {
+			t.Errorf("probed endpoints with scopedInit %t = %v, want only 127.0.0.1", scopedInit, rs.Endpoints)
+		}
+		if len(rs.Servers) != 1 {
+			t.Errorf("discovered %d servers with scopedInit %t, want 1", len(rs.Servers), scopedInit)
+		}
+	}
+}
+
+func TestSyncEthernetInterfacesMatchesMAC(t *testing.T) {
+	nbi, fake := inventorytest.NewInventory(t)
+	device := addTestDevice(t, nbi, &objects.Device{Name: "esxi01"})
+	// Interface of the same nic, that was added by hypervisor source
+	vmnic, err := nbi.AddInterface(&objects.Interface{Device: device, Name: "vmnic0", Type: &objects.OtherInterfaceType, MAC: "B4:96:91:00:00:01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := newTestSource(t, "http://127.0.0.1:1", "calvin")
+	server := &ServerData{EthernetInterfaces: []EthernetInterface{
+		{ID: "NIC.Integrated.1-1-1", MACAddress: "b4:96:91:00:00:01", SpeedMbps: 25000, LinkStatus: "LinkUp"},
+		{ID: "NIC.Integrated.1-2-1", PermanentMACAddress: "b4:96:91:00:00:02", MACAddress: "02:00:00:00:00:01", LinkStatus: "LinkDown"},
+	}}
+
+	if err := rs.syncEthernetInterfaces(nbi, server, device); err != nil {
+		t.Fatal(err)
+	}
+	deviceInterfaces := nbi.InterfacesIndexByDeviceIDAndName[device.ID]
+	if _, ok := deviceInterfaces["NIC.Integrated.1-1-1"]; ok {
+		t.Errorf("nic with the mac of vmnic0 was added as a new interface")
+	}
+	if patches := fake.Patches(service.InterfacesAPIPath, vmnic.ID); len(patches) != 1 || patches[0]["speed"] != float64(25000*constants.KB) {
+		t.Errorf("vmnic0 patches = %v, want speed of the nic", patches)
+	}
+	// Permanent mac address has precedence over the current one
+	if nbInterface := deviceInterfaces["NIC.Integrated.1-2-1"]; nbInterface == nil || nbInterface.MAC != "B4:96:91:00:00:02" || nbInterface.Status {
+		t.Errorf("interface NIC.Integrated.1-2-1 = %v, want disabled interface with mac B4:96:91:00:00:02", nbInterface)
+	}
+}
+
+func TestSyncServerScopedInit(t *testing.T) {
+	nbi, fake := inventorytest.NewInventory(t)
+	nbi.NetboxConfig.ScopedInit = true
+	site, err := nbi.AddSite(&objects.Site{Name: "MySite", Slug: "mysite"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Device and its interface are outside of the scope, so they are reachable only through lookup
+	existingDevice, err := service.Create(nbi.NetboxAPI, &objects.Device{
+		NetboxObject: objects.NetboxObject{
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName:   "vmware",
+				constants.CustomFieldSourceIDName: "host-1",
+			},
+		},
+		Name:         "esxi01",
+		Site:         site,
+		SerialNumber: "7XYZ123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vmnic, err := service.Create(nbi.NetboxAPI, &objects.Interface{Device: existingDevice, Name: "vmnic0", Type: &objects.OtherInterfaceType, MAC: "B4:96:91:00:00:01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := nbi.DevicesIndexBySerialNumber["7xyz123"]; ok {
+		t.Fatal("device outside of the scope is already in the inventory")
+	}
+	rs := newTestSource(t, "http://127.0.0.1:1", "calvin")
+	rs.HostSiteRelations = map[string]string{".*": "MySite"}
+	server := newTestServer(t)
+
+	nbDevice, err := rs.syncServer(nbi, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nbDevice.ID != existingDevice.ID {
+		t.Fatalf("syncServer() = device %d, want existing device %d", nbDevice.ID, existingDevice.ID)
+	}
+	// Fields of the owner are kept, also when the device was matched by lookup
+	patches := fake.Patches(service.DevicesAPIPath, existingDevice.ID)
+	if len(patches) != 1 {
+		t.Fatalf("device was patched %d times, want once: %v", len(patches), patches)
+	}
+	for _, field := range []string{"name", "site", "device_type", "role", "status"} {
+		if value, ok := patches[0][field]; ok {
+			t.Errorf("field %s of the existing device was patched to %v", field, value)
+		}
+	}
+	if customFields, _ := patches[0]["custom_fields"].(map[string]interface{}); customFields[constants.CustomFieldSourceName] != nil {
+		t.Errorf("source of the existing device was patched to %v", customFields[constants.CustomFieldSourceName])
+	}
+
+	if err := rs.syncEthernetInterfaces(nbi, server, nbDevice); err != nil {
+		t.Fatal(err)
+	}
+	// Nic is matched to the looked up interface with the same mac
+	if _, ok := nbi.InterfacesIndexByDeviceIDAndName[nbDevice.ID]["NIC.Integrated.1-1-1"]; ok {
+		t.Errorf("nic with the mac of vmnic0 was added as a new interface")
+	}
+	if patches := fake.Patches(service.InterfacesAPIPath, vmnic.ID); len(patches) != 1 {
+		t.Errorf("vmnic0 patches = %v, want speed of the nic", patches)
+	}
+}
@@ -0,0 +1,120 @@
+package snmp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// Source represents snmp source, which polls network devices on hostname
+// and on all addresses within permittedSubnets. SNMPv2c community is stored in password.
+type Source struct {
+	common.Config
+
+	// Snmp fetched data. Initialized in init functions.
+	Devices map[string]*DeviceData // SysName -> DeviceData
+
+	// Netbox related data for easier access. Initialized in sync functions.
+	SysName2nbDevice map[string]*objects.Device
+	// SysName -> IfIndex -> netbox interface
+	SysName2nbInterfaces map[string]map[int]*objects.Interface
+
+	// User defined relations
+	HostSiteRelations   map[string]string
+	HostTenantRelations map[string]string
+}
+
+// DeviceData is all the data polled from one snmp agent.
+type DeviceData struct {
+	// Address on which the device was polled
+	Address      string
+	SysName      string
+	SysDescr     string
+	SysObjectID  string
+	Model        string
+	SerialNumber string
+	Interfaces   map[int]*InterfaceData // IfIndex -> InterfaceData
+	IPAddresses  []IPAddressData
+	Neighbors    []NeighborData
+}
+
+// InterfaceData represents one row of IF-MIB ifTable (and ifXTable).
+type InterfaceData struct {
+	IfIndex     int
+	Name        string
+	Alias       string
+	Type        int
+	MTU         int
+	Speed       objects.InterfaceSpeed // Speed in kbps
+	MAC         string
+	AdminStatus int
+}
+
+// IPAddressData represents one row of IP-MIB ipAddrTable.
+type IPAddressData struct {
+	Address string
+	// Mask is the number of prefix bits
+	Mask    int
+	IfIndex int
+}
+
+// NeighborData represents one LLDP neighbor, discovered on the local interface.
+type NeighborData struct {
+	LocalIfIndex      int
+	SysName           string
+	PortID            string
+	ManagementAddress string
+}
+
+// Function that collects data from all reachable snmp agents.
+func (ss *Source) Init() error {
+	// Initialize regex relations
+	ss.Logger.Debug("Initializing regex relations for snmp source ", ss.SourceConfig.Name)
+	ss.HostSiteRelations = utils.ConvertStringsToRegexPairs(ss.SourceConfig.HostSiteRelations)
+	ss.Logger.Debug("HostSiteRelations: ", ss.HostSiteRelations)
+	ss.HostTenantRelations = utils.ConvertStringsToRegexPairs(ss.SourceConfig.HostTenantRelations)
+	ss.Logger.Debug("HostTenantRelations: ", ss.HostTenantRelations)
+	ss.ClusterVRFRelations = utils.ConvertStringsToRegexPairs(ss.SourceConfig.ClusterVRFRelations)
+	ss.Logger.Debug("ClusterVRFRelations: ", ss.ClusterVRFRelations)
+	ss.SubnetVRFRelations = utils.ConvertStringsToPairs(ss.SourceConfig.SubnetVRFRelations)
+	ss.Logger.Debug("SubnetVRFRelations: ", ss.SubnetVRFRelations)
+
+	initFunctions := []func() error{
+		ss.InitDevices,
+	}
+	for _, initFunc := range initFunctions {
+		startTime := time.Now()
+		if err := initFunc(); err != nil {
+			return fmt.Errorf("snmp initialization failure: %v", err)
+		}
+		duration := time.Since(startTime)
+		ss.Logger.Infof("Successfully initialized %s in %f seconds", utils.ExtractFunctionName(initFunc), duration.Seconds())
+	}
+	return nil
+}
+
+// Function that syncs all polled data to netbox.
+func (ss *Source) Sync(nbi *inventory.NetboxInventory) error {
+	ss.SysName2nbDevice = make(map[string]*objects.Device)
+	ss.SysName2nbInterfaces = make(map[string]map[int]*objects.Interface)
+
+	syncFunctions := []func(*inventory.NetboxInventory) error{
+		ss.syncDevices,
+		ss.syncInterfaces,
+		ss.syncCables,
+	}
+	for _, syncFunc := range syncFunctions {
+		startTime := time.Now()
+		err := syncFunc(nbi)
+		if err != nil {
+			return err
+		}
+		duration := time.Since(startTime)
+		ss.Logger.Infof("Successfully synced %s in %f seconds", utils.ExtractFunctionName(syncFunc), duration.Seconds())
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package snmp
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// OIDs of the objects that are polled from each device.
+const (
+	// SNMPv2-MIB.
+	oidSysDescr    = ".1.3.6.1.2.1.1.1.0"
+	oidSysObjectID = ".1.3.6.1.2.1.1.2.0"
+	oidSysName     = ".1.3.6.1.2.1.1.5.0"
+
+	// IF-MIB.
+	oidIfDescr       = ".1.3.6.1.2.1.2.2.1.2"
+	oidIfType        = ".1.3.6.1.2.1.2.2.1.3"
+	oidIfMtu         = ".1.3.6.1.2.1.2.2.1.4"
+	oidIfSpeed       = ".1.3.6.1.2.1.2.2.1.5"
+	oidIfPhysAddress = ".1.3.6.1.2.1.2.2.1.6"
+	oidIfAdminStatus = ".1.3.6.1.2.1.2.2.1.7"
+	oidIfName        = ".1.3.6.1.2.1.31.1.1.1.1"
+	oidIfHighSpeed   = ".1.3.6.1.2.1.31.1.1.1.15"
+	oidIfAlias       = ".1.3.6.1.2.1.31.1.1.1.18"
+
+	// IP-MIB.
+	oidIPAdEntIfIndex = ".1.3.6.1.2.1.4.20.1.2"
+	oidIPAdEntNetMask = ".1.3.6.1.2.1.4.20.1.3"
+
+	// ENTITY-MIB.
+	oidEntPhysicalClass     = ".1.3.6.1.2.1.47.1.1.1.1.5"
+	oidEntPhysicalSerialNum = ".1.3.6.1.2.1.47.1.1.1.1.11"
+	oidEntPhysicalModelName = ".1.3.6.1.2.1.47.1.1.1.1.13"
+
+	// LLDP-MIB.
+	oidLldpLocPortID         = ".1.0.8802.1.1.2.1.3.7.1.3"
+	oidLldpRemPortID         = ".1.0.8802.1.1.2.1.4.1.1.7"
+	oidLldpRemSysName        = ".1.0.8802.1.1.2.1.4.1.1.9"
+	oidLldpRemManAddrIfIndex = ".1.0.8802.1.1.2.1.4.2.1.3"
+
+	// Prefix of sysObjectID, followed by IANA enterprise number.
+	oidEnterprises = ".1.3.6.1.4.1."
+)
+
+// Length of mac address in bytes.
+const macLength = 6
+
+// Values of ifAdminStatus.
+const ifAdminStatusUp = 1
+
+// Values of entPhysicalClass.
+const entPhysicalClassChassis = 3
+
+// Values of ifType (IANAifType-MIB) that are not physical interfaces.
+const (
+	ifTypeSoftwareLoopback = 24
+	ifTypePropVirtual      = 53
+	ifTypeTunnel           = 131
+	ifTypeL2Vlan           = 135
+	ifTypeL3IPVlan         = 136
+	ifTypeIEEE8023adLag    = 161
+)
+
+const (
+	// Timeout for a single snmp request.
+	requestTimeout = 2 * time.Second
+	// Number of retries for a single snmp request.
+	requestRetries = 1
+)
+
+// newClient returns connected SNMPv2c client for the given target.
+func newClient(target string, port int, community string) (*gosnmp.GoSNMP, error) {
+	client := &gosnmp.GoSNMP{
+		Target:             target,
+		Port:               uint16(port),
+		Community:          community,
+		Version:            gosnmp.Version2c,
+		Timeout:            requestTimeout,
+		Retries:            requestRetries,
+		MaxOids:            gosnmp.MaxOids,
+		MaxRepetitions:     gosnmp.Default.MaxRepetitions,
+		ExponentialTimeout: false,
+	}
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// walk returns all values in the subtree of the given oid, indexed by the
+// remaining part of their oid (e.g. ifIndex for IF-MIB tables).
+func walk(client *gosnmp.GoSNMP, oid string) (map[string]gosnmp.SnmpPDU, error) {
+	pdus, err := client.BulkWalkAll(oid)
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %s", oid, err)
+	}
+	index2pdu := make(map[string]gosnmp.SnmpPDU, len(pdus))
+	for _, pdu := range pdus {
+		index2pdu[strings.TrimPrefix(pdu.Name, oid+".")] = pdu
+	}
+	return index2pdu, nil
+}
+
+// pduString returns value of the pdu as a string. Empty string is returned
+// for non-existing objects.
+func pduString(pdu gosnmp.SnmpPDU) string {
+	switch value := pdu.Value.(type) {
+	case []byte:
+		return strings.TrimSpace(string(value))
+	case string:
+		return strings.TrimSpace(value)
+	default:
+		return ""
+	}
+}
+
+// pduInt returns value of the pdu as an int. 0 is returned for non-numeric values.
+func pduInt(pdu gosnmp.SnmpPDU) int {
+	switch pdu.Type {
+	case gosnmp.Integer, gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Counter64, gosnmp.Uinteger32:
+		return int(gosnmp.ToBigInt(pdu.Value).Int64())
+	default:
+		return 0
+	}
+}
+
+// pduMAC returns value of the pdu formatted as uppercase mac address
+// (e.g. 00:1A:2B:3C:4D:5E). Empty string is returned for values that are not mac addresses.
+func pduMAC(pdu gosnmp.SnmpPDU) string {
+	value, ok := pdu.Value.([]byte)
+	if !ok || len(value) != macLength {
+		return ""
+	}
+	return strings.ToUpper(net.HardwareAddr(value).String())
+}
+
+// parseOIDIndex converts oid index (e.g. 10.0.0.1) to slice of ints.
+func parseOIDIndex(index string) ([]int, error) {
+	parts := strings.Split(index, ".")
+	ints := make([]int, 0, len(parts))
+	for _, part := range parts {
+		i, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oid index %s: %s", index, err)
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
+
+// enterpriseNumber returns IANA enterprise number from sysObjectID
+// (e.g. 9 for .1.3.6.1.4.1.9.1.2066). It returns 0 if it can't be parsed.
+func enterpriseNumber(sysObjectID string) int {
+	if !strings.HasPrefix(sysObjectID, oidEnterprises) {
+		return 0
+	}
+	enterprise := strings.Split(strings.TrimPrefix(sysObjectID, oidEnterprises), ".")[0]
+	number, err := strconv.Atoi(enterprise)
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// Mapping of IANA enterprise numbers to manufacturer names.
+var enterprise2manufacturer = map[int]string{
+	9:     "Cisco",
+	11:    "HPE",
+	674:   "Dell",
+	2011:  "Huawei",
+	2636:  "Juniper",
+	4526:  "Netgear",
+	6027:  "Dell",
+	6486:  "Alcatel-Lucent",
+	12356: "Fortinet",
+	14988: "MikroTik",
+	25506: "HPE",
+	30065: "Arista",
+	41112: "Ubiquiti",
+}
@@ -0,0 +1,348 @@
+package snmp
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/gosnmp/gosnmp"
+)
+
+const (
+	// Number of agents that are polled concurrently.
+	pollWorkers = 16
+	// Subnets with more host bits than this are not swept.
+	maxSubnetHostBits = 16
+	// Minimal prefix length of ipv4 subnets, that don't include network and broadcast address.
+	minPointToPointPrefix = 31
+	// Number of sub-identifiers in lldpRemTable index (time mark, local port, remote index).
+	lldpRemIndexLength = 3
+	// IANA address family number of ipv4, used as lldpRemManAddrSubtype.
+	addressFamilyIPv4 = 1
+)
+
+// expandTargets returns list of unique addresses to poll: hostname followed by
+// all host addresses of permittedSubnets.
+func expandTargets(hostname string, permittedSubnets []string) ([]string, error) {
+	targets := []string{hostname}
+	seen := map[string]bool{hostname: true}
+	for _, subnet := range permittedSubnets {
+		if ip := net.ParseIP(subnet); ip != nil {
+			if !seen[ip.String()] {
+				seen[ip.String()] = true
+				targets = append(targets, ip.String())
+			}
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, fmt.Errorf("parsing subnet %s: %s", subnet, err)
+		}
+		ones, bits := ipNet.Mask.Size()
+		if bits-ones > maxSubnetHostBits {
+			return nil, fmt.Errorf("subnet %s is too large to be swept", subnet)
+		}
+		for ip := ipNet.IP.Mask(ipNet.Mask); ipNet.Contains(ip); ip = nextIP(ip) {
+			// Skip network and broadcast address
+			if ip.To4() != nil && ones < minPointToPointPrefix && (ip.Equal(ipNet.IP) || !ipNet.Contains(nextIP(ip))) {
+				continue
+			}
+			if !seen[ip.String()] {
+				seen[ip.String()] = true
+				targets = append(targets, ip.String())
+			}
+		}
+	}
+	return targets, nil
+}
+
+// nextIP returns the address that follows ip.
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// InitDevices polls all targets concurrently. Hostname of the source must be
+// reachable, other addresses without snmp agent are skipped.
+func (ss *Source) InitDevices() error {
+	targets, err := expandTargets(ss.SourceConfig.Hostname, ss.SourceConfig.PermittedSubnets)
+	if err != nil {
+		return fmt.Errorf("targets: %s", err)
+	}
+
+	results := make([]*DeviceData, len(targets))
+	errs := make([]error, len(targets))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < pollWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = ss.pollDevice(targets[i])
+			}
+		}()
+	}
+	for i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if errs[0] != nil {
+		return fmt.Errorf("polling %s: %s", targets[0], errs[0])
+	}
+
+	ss.Devices = make(map[string]*DeviceData)
+	for i, device := range results {
+		if errs[i] != nil {
+			ss.Logger.Debugf("no snmp agent found on %s: %s", targets[i], errs[i])
+			continue
+		}
+		// The same device can be reachable on multiple addresses
+		if _, ok := ss.Devices[device.SysName]; ok {
+			continue
+		}
+		ss.Devices[device.SysName] = device
+	}
+	return nil
+}
+
+// pollDevice collects all data from snmp agent on the given address.
+func (ss *Source) pollDevice(address string) (*DeviceData, error) {
+	client, err := newClient(address, ss.SourceConfig.Port, ss.SourceConfig.Password)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %s", err)
+	}
+	defer client.Conn.Close()
+
+	device := &DeviceData{Address: address}
+	pollFunctions := []func(*gosnmp.GoSNMP, *DeviceData) error{
+		pollSystem,
+		pollEntities,
+		pollInterfaces,
+		pollIPAddresses,
+		pollNeighbors,
+	}
+	for _, pollFunc := range pollFunctions {
+		if err := pollFunc(client, device); err != nil {
+			return nil, err
+		}
+	}
+	return device, nil
+}
+
+// pollSystem collects SNMPv2-MIB system group.
+func pollSystem(client *gosnmp.GoSNMP, device *DeviceData) error {
+	packet, err := client.Get([]string{oidSysName, oidSysDescr, oidSysObjectID})
+	if err != nil {
+		return fmt.Errorf("system: %s", err)
+	}
+	for _, pdu := range packet.Variables {
+		switch pdu.Name {
+		case oidSysName:
+			device.SysName = pduString(pdu)
+		case oidSysDescr:
+			device.SysDescr = pduString(pdu)
+		case oidSysObjectID:
+			device.SysObjectID = pduString(pdu)
+		}
+	}
+	if device.SysName == "" {
+		device.SysName = device.Address
+	}
+	return nil
+}
+
+// pollEntities collects model and serial number of the chassis from ENTITY-MIB.
+// If agent doesn't report any chassis, the first entity with the serial number is used.
+func pollEntities(client *gosnmp.GoSNMP, device *DeviceData) error {
+	classes, err := walk(client, oidEntPhysicalClass)
+	if err != nil {
+		return err
+	}
+	serials, err := walk(client, oidEntPhysicalSerialNum)
+	if err != nil {
+		return err
+	}
+	models, err := walk(client, oidEntPhysicalModelName)
+	if err != nil {
+		return err
+	}
+	for index, class := range classes {
+		if pduInt(class) == entPhysicalClassChassis && pduString(serials[index]) != "" {
+			device.SerialNumber = pduString(serials[index])
+			device.Model = pduString(models[index])
+			return nil
+		}
+	}
+	indexes := make([]string, 0, len(serials))
+	for index := range serials {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		a, _ := strconv.Atoi(indexes[i])
+		b, _ := strconv.Atoi(indexes[j])
+		return a < b
+	})
+	for _, index := range indexes {
+		if serial := pduString(serials[index]); serial != "" {
+			device.SerialNumber = serial
+			device.Model = pduString(models[index])
+			return nil
+		}
+	}
+	return nil
+}
+
+// pollInterfaces collects interfaces from IF-MIB ifTable and ifXTable.
+func pollInterfaces(client *gosnmp.GoSNMP, device *DeviceData) error {
+	columns := map[string]map[string]gosnmp.SnmpPDU{}
+	for _, oid := range []string{oidIfDescr, oidIfType, oidIfMtu, oidIfSpeed, oidIfPhysAddress, oidIfAdminStatus, oidIfName, oidIfHighSpeed, oidIfAlias} {
+		column, err := walk(client, oid)
+		if err != nil {
+			return err
+		}
+		columns[oid] = column
+	}
+
+	device.Interfaces = make(map[int]*InterfaceData)
+	for index, descr := range columns[oidIfDescr] {
+		ifIndex, err := strconv.Atoi(index)
+		if err != nil {
+			continue
+		}
+		iface := &InterfaceData{
+			IfIndex:     ifIndex,
+			Name:        pduString(columns[oidIfName][index]),
+			Alias:       pduString(columns[oidIfAlias][index]),
+			Type:        pduInt(columns[oidIfType][index]),
+			MTU:         pduInt(columns[oidIfMtu][index]),
+			MAC:         pduMAC(columns[oidIfPhysAddress][index]),
+			AdminStatus: pduInt(columns[oidIfAdminStatus][index]),
+		}
+		if iface.Name == "" {
+			iface.Name = pduString(descr)
+		}
+		// ifHighSpeed is in Mbps and ifSpeed in bps, netbox expects kbps
+		if highSpeed := pduInt(columns[oidIfHighSpeed][index]); highSpeed > 0 {
+			iface.Speed = objects.InterfaceSpeed(highSpeed * constants.KB)
+		} else {
+			iface.Speed = objects.InterfaceSpeed(pduInt(columns[oidIfSpeed][index]) / constants.KB)
+		}
+		device.Interfaces[ifIndex] = iface
+	}
+	return nil
+}
+
+// pollIPAddresses collects ipv4 addresses from IP-MIB ipAddrTable.
+func pollIPAddresses(client *gosnmp.GoSNMP, device *DeviceData) error {
+	ifIndexes, err := walk(client, oidIPAdEntIfIndex)
+	if err != nil {
+		return err
+	}
+	masks, err := walk(client, oidIPAdEntNetMask)
+	if err != nil {
+		return err
+	}
+	device.IPAddresses = make([]IPAddressData, 0, len(ifIndexes))
+	for address, ifIndex := range ifIndexes {
+		mask := net.IPMask(net.ParseIP(pduString(masks[address])).To4())
+		maskBits, _ := mask.Size()
+		if mask == nil || maskBits == 0 {
+			maskBits = net.IPv4len * 8
+		}
+		device.IPAddresses = append(device.IPAddresses, IPAddressData{
+			Address: address,
+			Mask:    maskBits,
+			IfIndex: pduInt(ifIndex),
+		})
+	}
+	return nil
+}
+
+// pollNeighbors collects remote systems from LLDP-MIB lldpRemTable.
+func pollNeighbors(client *gosnmp.GoSNMP, device *DeviceData) error {
+	sysNames, err := walk(client, oidLldpRemSysName)
+	if err != nil {
+		return err
+	}
+	portIDs, err := walk(client, oidLldpRemPortID)
+	if err != nil {
+		return err
+	}
+	manAddrs, err := walk(client, oidLldpRemManAddrIfIndex)
+	if err != nil {
+		return err
+	}
+	locPortIDs, err := walk(client, oidLldpLocPortID)
+	if err != nil {
+		return err
+	}
+
+	// Index of lldpRemManAddrTable is lldpRemTimeMark.lldpRemLocalPortNum.lldpRemIndex
+	// followed by address subtype, address length and address octets
+	remIndex2manAddr := make(map[string]string)
+	for index := range manAddrs {
+		octets, err := parseOIDIndex(index)
+		if err != nil || len(octets) != lldpRemIndexLength+2+net.IPv4len {
+			continue
+		}
+		if octets[lldpRemIndexLength] != addressFamilyIPv4 || octets[lldpRemIndexLength+1] != net.IPv4len {
+			continue
+		}
+		address := octets[lldpRemIndexLength+2:]
+		remIndex := fmt.Sprintf("%d.%d.%d", octets[0], octets[1], octets[2])
+		remIndex2manAddr[remIndex] = fmt.Sprintf("%d.%d.%d.%d", address[0], address[1], address[2], address[3])
+	}
+
+	device.Neighbors = make([]NeighborData, 0, len(sysNames))
+	for index, sysName := range sysNames {
+		octets, err := parseOIDIndex(index)
+		if err != nil || len(octets) != lldpRemIndexLength {
+			continue
+		}
+		localPortNum := octets[1]
+		device.Neighbors = append(device.Neighbors, NeighborData{
+			LocalIfIndex:      device.localPortToIfIndex(localPortNum, pduString(locPortIDs[strconv.Itoa(localPortNum)])),
+			SysName:           pduString(sysName),
+			PortID:            pduString(portIDs[index]),
+			ManagementAddress: remIndex2manAddr[index],
+		})
+	}
+	return nil
+}
+
+// localPortToIfIndex maps lldp local port to ifIndex. Local port id usually
+// contains interface name, otherwise local port number is usually the same as ifIndex.
+func (d *DeviceData) localPortToIfIndex(localPortNum int, localPortID string) int {
+	for ifIndex, iface := range d.Interfaces {
+		if localPortID != "" && strings.EqualFold(iface.Name, localPortID) {
+			return ifIndex
+		}
+	}
+	return localPortNum
+}
+
+// portIDToIfIndex maps port id, that is advertised by lldp, to ifIndex. Port id is usually
+// interface name or alias, but it can also be its mac address. Returns 0, if no interface matches.
+func (d *DeviceData) portIDToIfIndex(portID string) int {
+	for ifIndex, iface := range d.Interfaces {
+		if strings.EqualFold(iface.Name, portID) || (iface.Alias != "" && strings.EqualFold(iface.Alias, portID)) || (iface.MAC != "" && strings.EqualFold(iface.MAC, portID)) {
+			return ifIndex
+		}
+	}
+	return 0
+}
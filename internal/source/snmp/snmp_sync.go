@@ -0,0 +1,231 @@
+package snmp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// Name of the device role for all devices discovered with snmp.
+const networkDeviceRoleName = "Network Device"
+
+func (ss *Source) syncDevices(nbi *inventory.NetboxInventory) error {
+	deviceRole, err := nbi.AddDeviceRole(&objects.DeviceRole{
+		Name:   networkDeviceRoleName,
+		Slug:   utils.Slugify(networkDeviceRoleName),
+		Color:  objects.ColorAqua,
+		VMRole: false,
+	})
+	if err != nil {
+		return fmt.Errorf("adding snmp device role: %s", err)
+	}
+
+	for sysName, device := range ss.Devices {
+		deviceSite, err := common.MatchHostToSite(nbi, sysName, ss.HostSiteRelations)
+		if err != nil {
+			return fmt.Errorf("hostSite: %s", err)
+		}
+		if deviceSite == nil {
+			ss.Logger.Warningf("snmp device %s can't be matched to any site, so it will be skipped", sysName)
+			continue
+		}
+		deviceTenant, err := common.MatchHostToTenant(nbi, sysName, ss.HostTenantRelations)
+		if err != nil {
+			return fmt.Errorf("hostTenant: %s", err)
+		}
+
+		manufacturerName, ok := enterprise2manufacturer[enterpriseNumber(device.SysObjectID)]
+		if !ok {
+			manufacturerName = constants.DefaultManufacturer
+		}
+		manufacturer, err := nbi.AddManufacturer(&objects.Manufacturer{
+			Name: manufacturerName,
+			Slug: utils.Slugify(manufacturerName),
+		})
+		if err != nil {
+			return fmt.Errorf("adding snmp manufacturer: %s", err)
+		}
+
+		model := device.Model
+		if model == "" {
+			model = constants.DefaultModel // Model is also required for adding device type into netbox
+		}
+		deviceType, err := nbi.AddDeviceType(&objects.DeviceType{
+			Manufacturer: manufacturer,
+			Model:        model,
+			Slug:         utils.Slugify(model),
+		})
+		if err != nil {
+			return fmt.Errorf("adding snmp device type: %s", err)
+		}
+
+		// SysDescr is usually multiline, so only the first line is used as description
+		description := strings.Split(device.SysDescr, "\n")[0]
+		var comments string
+		if len(description) > objects.MaxDescriptionLength {
+			comments = description
+			description = "See comments"
+		}
+
+		nbDevice, err := nbi.AddDevice(&objects.Device{
+			NetboxObject: objects.NetboxObject{
+				Tags:        ss.Config.SourceTags,
+				Description: description,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: ss.SourceConfig.Name,
+				},
+			},
+			Name:         sysName,
+			Site:         deviceSite,
+			Tenant:       deviceTenant,
+			DeviceRole:   deviceRole,
+			DeviceType:   deviceType,
+			SerialNumber: device.SerialNumber,
+			Comments:     comments,
+			Status:       &objects.DeviceStatusActive,
+		})
+		if err != nil {
+			return fmt.Errorf("adding snmp device %s: %s", sysName, err)
+		}
+		ss.SysName2nbDevice[sysName] = nbDevice
+	}
+	return nil
+}
+
+// ifType2InterfaceType returns netbox interface type from IANAifType and speed.
+func ifType2InterfaceType(ifType int, speed objects.InterfaceSpeed) *objects.InterfaceType {
+	switch ifType {
+	case ifTypeSoftwareLoopback, ifTypePropVirtual, ifTypeTunnel, ifTypeL2Vlan, ifTypeL3IPVlan:
+		return &objects.VirtualInterfaceType
+	case ifTypeIEEE8023adLag:
+		return &objects.LAGInterfaceType
+	}
+	if ifaceType, ok := objects.IfaceSpeed2IfaceType[speed]; ok {
+		return ifaceType
+	}
+	return &objects.OtherInterfaceType
+}
+
+// syncInterfaces syncs interfaces and their ip addresses of all synced devices.
+// Address on which the device was polled is set as device's primary ip.
+func (ss *Source) syncInterfaces(nbi *inventory.NetboxInventory) error {
+	for sysName, nbDevice := range ss.SysName2nbDevice {
+		device := ss.Devices[sysName]
+
+		ifIndexes := make([]int, 0, len(device.Interfaces))
+		for ifIndex := range device.Interfaces {
+			ifIndexes = append(ifIndexes, ifIndex)
+		}
+		sort.Ints(ifIndexes)
+
+		ifIndex2nbInterface := make(map[int]*objects.Interface, len(ifIndexes))
+		for _, ifIndex := range ifIndexes {
+			iface := device.Interfaces[ifIndex]
+			if iface.Name == "" {
+				ss.Logger.Warningf("interface with ifIndex %d on device %s has no name, so it will be skipped", ifIndex, sysName)
+				continue
+			}
+			nbInterface, err := nbi.AddInterface(&objects.Interface{
+				NetboxObject: objects.NetboxObject{
+					Tags:        ss.Config.SourceTags,
+					Description: iface.Alias,
+					CustomFields: map[string]interface{}{
+						constants.CustomFieldSourceName: ss.SourceConfig.Name,
+					},
+				},
+				Device: nbDevice,
+				Name:   iface.Name,
+				Status: iface.AdminStatus == ifAdminStatusUp,
+				Type:   ifType2InterfaceType(iface.Type, iface.Speed),
+				Speed:  iface.Speed,
+				MTU:    iface.MTU,
+				MAC:    iface.MAC,
+			})
+			if err != nil {
+				return fmt.Errorf("adding snmp interface %s: %s", iface.Name, err)
+			}
+			ifIndex2nbInterface[ifIndex] = nbInterface
+		}
+
+		for _, ipAddress := range device.IPAddresses {
+			nbInterface, ok := ifIndex2nbInterface[ipAddress.IfIndex]
+			if !ok || strings.HasPrefix(ipAddress.Address, "127.") || !ss.IsPermittedIPAddress(ipAddress.Address) {
+				continue
+			}
+			vrf, err := ss.MatchIPToVRF(nbi, ipAddress.Address, nil)
+			if err != nil {
+				return fmt.Errorf("matching snmp ip address %s to vrf: %s", ipAddress.Address, err)
+			}
+			nbIPAddress, err := nbi.AddIPAddress(&objects.IPAddress{
+				NetboxObject: objects.NetboxObject{
+					Tags: ss.Config.SourceTags,
+					CustomFields: map[string]interface{}{
+						constants.CustomFieldSourceName: ss.SourceConfig.Name,
+					},
+				},
+				Address:            fmt.Sprintf("%s/%d", ipAddress.Address, ipAddress.Mask),
+				VRF:                vrf,
+				Status:             &objects.IPAddressStatusActive,
+				DNSName:            ss.DNSName(ipAddress.Address, ""),
+				AssignedObjectType: objects.AssignedObjectTypeDeviceInterface,
+				AssignedObjectID:   nbInterface.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("adding snmp ip address %s: %s", ipAddress.Address, err)
+			}
+			if ipAddress.Address == device.Address {
+				deviceCopy := *nbDevice
+				deviceCopy.PrimaryIPv4 = nbIPAddress
+				nbDevice, err = nbi.AddDevice(&deviceCopy)
+				if err != nil {
+					return fmt.Errorf("adding primary ipv4 address: %s", err)
+				}
+				ss.SysName2nbDevice[sysName] = nbDevice
+			}
+		}
+		ss.SysName2nbInterfaces[sysName] = ifIndex2nbInterface
+	}
+	return nil
+}
+
+// syncCables creates cables between interfaces of synced devices, that are lldp neighbors.
+// Both devices usually report the same link, and the second report only matches the existing cable.
+func (ss *Source) syncCables(nbi *inventory.NetboxInventory) error {
+	for sysName, ifIndex2nbInterface := range ss.SysName2nbInterfaces {
+		for _, neighbor := range ss.Devices[sysName].Neighbors {
+			neighborDevice, ok := ss.Devices[neighbor.SysName]
+			if !ok {
+				ss.Logger.Debugf("lldp neighbor %s (%s) of device %s is not reachable with snmp", neighbor.SysName, neighbor.ManagementAddress, sysName)
+				continue
+			}
+			localInterface, ok := ifIndex2nbInterface[neighbor.LocalIfIndex]
+			if !ok {
+				continue
+			}
+			neighborInterface, ok := ss.SysName2nbInterfaces[neighbor.SysName][neighborDevice.portIDToIfIndex(neighbor.PortID)]
+			if !ok {
+				ss.Logger.Debugf("port %s of lldp neighbor %s of device %s is not synced, skipping the cable", neighbor.PortID, neighbor.SysName, sysName)
+				continue
+			}
+			_, err := nbi.AddCable(localInterface, neighborInterface, &objects.Cable{
+				NetboxObject: objects.NetboxObject{
+					Tags: ss.Config.SourceTags,
+					CustomFields: map[string]interface{}{
+						constants.CustomFieldSourceName: ss.SourceConfig.Name,
+					},
+				},
+				Status: &objects.CableStatusConnected,
+			})
+			if err != nil {
+				return fmt.Errorf("adding cable %s %s <-> %s %s: %s", sysName, localInterface.Name, neighbor.SysName, neighborInterface.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+package snmp
+
+import (
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory/inventorytest"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/service"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/gosnmp/gosnmp"
+)
+
+const testCommunity = "public"
+
+// Mocked mib of a single switch, indexed by oid.
+var mockMib = []gosnmp.SnmpPDU{
+	{Name: oidSysDescr, Type: gosnmp.OctetString, Value: []byte("Cisco IOS Software, C9300 Software\nTechnical Support: http://www.cisco.com/techsupport")},
+	{Name: oidSysObjectID, Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.9.1.2494"},
+	{Name: oidSysName, Type: gosnmp.OctetString, Value: []byte("sw01.example.com")},
+	{Name: oidIfDescr + ".1", Type: gosnmp.OctetString, Value: []byte("GigabitEthernet1/0/1")},
+	{Name: oidIfDescr + ".2", Type: gosnmp.OctetString, Value: []byte("Vlan10")},
+	{Name: oidIfType + ".1", Type: gosnmp.Integer, Value: 6},
+	{Name: oidIfType + ".2", Type: gosnmp.Integer, Value: 53},
+	{Name: oidIfMtu + ".1", Type: gosnmp.Integer, Value: 9000},
+	{Name: oidIfMtu + ".2", Type: gosnmp.Integer, Value: 1500},
+	{Name: oidIfSpeed + ".1", Type: gosnmp.Gauge32, Value: uint(1000000000)},
+	{Name: oidIfSpeed + ".2", Type: gosnmp.Gauge32, Value: uint(1000000000)},
+	{Name: oidIfPhysAddress + ".1", Type: gosnmp.OctetString, Value: []byte{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x01}},
+	{Name: oidIfPhysAddress + ".2", Type: gosnmp.OctetString, Value: []byte{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x02}},
+	{Name: oidIfAdminStatus + ".1", Type: gosnmp.Integer, Value: 1},
+	{Name: oidIfAdminStatus + ".2", Type: gosnmp.Integer, Value: 2},
+	{Name: oidIPAdEntIfIndex + ".10.0.10.1", Type: gosnmp.Integer, Value: 2},
+	{Name: oidIPAdEntNetMask + ".10.0.10.1", Type: gosnmp.IPAddress, Value: "255.255.255.0"},
+	{Name: oidIfName + ".1", Type: gosnmp.OctetString, Value: []byte("Gi1/0/1")},
+	{Name: oidIfName + ".2", Type: gosnmp.OctetString, Value: []byte("Vl10")},
+	{Name: oidIfHighSpeed + ".1", Type: gosnmp.Gauge32, Value: uint(1000)},
+	{Name: oidIfAlias + ".1", Type: gosnmp.OctetString, Value: []byte("uplink to sw02")},
+	{Name: oidEntPhysicalClass + ".1", Type: gosnmp.Integer, Value: 3},
+	{Name: oidEntPhysicalClass + ".1000", Type: gosnmp.Integer, Value: 9},
+	{Name: oidEntPhysicalSerialNum + ".1", Type: gosnmp.OctetString, Value: []byte("FOC1234X0AB")},
+	{Name: oidEntPhysicalSerialNum + ".1000", Type: gosnmp.OctetString, Value: []byte("FOC0000X0AA")},
+	{Name: oidEntPhysicalModelName + ".1", Type: gosnmp.OctetString, Value: []byte("C9300-48P")},
+	{Name: oidEntPhysicalModelName + ".1000", Type: gosnmp.OctetString, Value: []byte("C9300-NM-8X")},
+	{Name: oidLldpLocPortID + ".1", Type: gosnmp.OctetString, Value: []byte("Gi1/0/1")},
+	{Name: oidLldpRemPortID + ".0.1.1", Type: gosnmp.OctetString, Value: []byte("Gi1/0/48")},
+	{Name: oidLldpRemSysName + ".0.1.1", Type: gosnmp.OctetString, Value: []byte("sw02.example.com")},
+	{Name: oidLldpRemManAddrIfIndex + ".0.1.1.1.4.10.0.10.2", Type: gosnmp.Integer, Value: 2},
+	{Name: oidLldpRemPortID + ".0.2.1", Type: gosnmp.OctetString, Value: []byte("eth0")},
+	{Name: oidLldpRemSysName + ".0.2.1", Type: gosnmp.OctetString, Value: []byte("ap01.example.com")},
+	{Name: oidLldpRemManAddrIfIndex + ".0.2.1.2.16.32.1.13.184.0.0.0.0.0.0.0.0.0.0.0.1", Type: gosnmp.Integer, Value: 2},
+}
+
+// compareOIDs compares oids numerically, returning true if a precedes b.
+func compareOIDs(a, b string) bool {
+	aParts := strings.Split(strings.TrimPrefix(a, "."), ".")
+	bParts := strings.Split(strings.TrimPrefix(b, "."), ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aInt, _ := strconv.Atoi(aParts[i])
+		bInt, _ := strconv.Atoi(bParts[i])
+		if aInt != bInt {
+			return aInt < bInt
+		}
+	}
+	return len(aParts) < len(bParts)
+}
+
+// startMockAgent starts minimal SNMPv2c agent simulator, that answers get,
+// getnext and getbulk requests from mockMib. It returns the port of the agent.
+func startMockAgent(t *testing.T) int {
+	t.Helper()
+	mib := append([]gosnmp.SnmpPDU{}, mockMib...)
+	sort.Slice(mib, func(i, j int) bool { return compareOIDs(mib[i].Name, mib[j].Name) })
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	// next returns index of the first pdu after oid
+	next := func(oid string) int {
+		return sort.Search(len(mib), func(i int) bool { return compareOIDs(oid, mib[i].Name) })
+	}
+	go func() {
+		decoder := &gosnmp.GoSNMP{Version: gosnmp.Version2c}
+		buf := make([]byte, 65535)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			request, err := decoder.SnmpDecodePacket(buf[:n])
+			if err != nil || request.Community != testCommunity {
+				continue
+			}
+			variables := []gosnmp.SnmpPDU{}
+			for _, variable := range request.Variables {
+				switch request.PDUType {
+				case gosnmp.GetRequest:
+					i := next(variable.Name) - 1
+					if i >= 0 && mib[i].Name == variable.Name {
+						variables = append(variables, mib[i])
+					} else {
+						variables = append(variables, gosnmp.SnmpPDU{Name: variable.Name, Type: gosnmp.NoSuchObject})
+					}
+				case gosnmp.GetNextRequest, gosnmp.GetBulkRequest:
+					repetitions := 1
+					if request.PDUType == gosnmp.GetBulkRequest {
+						repetitions = int(request.MaxRepetitions)
+					}
+					i := next(variable.Name)
+					for r := 0; r < repetitions; r++ {
+						if i+r >= len(mib) {
+							variables = append(variables, gosnmp.SnmpPDU{Name: variable.Name, Type: gosnmp.EndOfMibView})
+							break
+						}
+						variables = append(variables, mib[i+r])
+					}
+				}
+			}
+			response := &gosnmp.SnmpPacket{
+				Version:   gosnmp.Version2c,
+				Community: request.Community,
+				PDUType:   gosnmp.GetResponse,
+				RequestID: request.RequestID,
+				Variables: variables,
+			}
+			out, err := response.MarshalMsg()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteToUDP(out, addr)
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func newTestSource(t *testing.T, port int, community string) *Source {
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Source{
+		Config: common.Config{
+			Logger: testLogger,
+			SourceConfig: &parser.SourceConfig{
+				Name:             "snmp-test",
+				Type:             constants.SNMP,
+				Hostname:         "127.0.0.1",
+				Port:             port,
+				Password:         community,
+				PermittedSubnets: []string{"127.0.0.1/32"},
+			},
+		},
+	}
+}
+
+func TestSync(t *testing.T) {
+	port := startMockAgent(t)
+	polledSource := newTestSource(t, port, testCommunity)
+	if err := polledSource.Init(); err != nil {
+		t.Fatal(err)
+	}
+	ss, nbi, _ := newSyncSource(t, polledSource.Devices["sw01.example.com"])
+	if err := ss.Sync(nbi); err != nil {
+		t.Fatal(err)
+	}
+
+	device := ss.SysName2nbDevice["sw01.example.com"]
+	if device == nil {
+		t.Fatalf("sw01.example.com was not synced, polled devices %v", polledSource.Devices)
+	}
+	// Chassis is preferred over other entities with the serial number
+	if device.SerialNumber != "FOC1234X0AB" || device.Description != "Cisco IOS Software, C9300 Software" {
+		t.Errorf("device = %+v, want serial number FOC1234X0AB and the first line of sysDescr", device)
+	}
+	if deviceType := nbi.DeviceTypesIndexByModel["C9300-48P"]; deviceType == nil || nbi.ManufacturersIndexByName["Cisco"] == nil {
+		t.Errorf("device type C9300-48P of manufacturer Cisco was not synced")
+	}
+
+	interfaces := nbi.InterfacesIndexByDeviceIDAndName[device.ID]
+	uplink, vlan := interfaces["Gi1/0/1"], interfaces["Vl10"]
+	if uplink == nil || vlan == nil {
+		t.Fatalf("interfaces = %v, want Gi1/0/1 and Vl10 named by ifName", interfaces)
+	}
+	if uplink.Type.Value != objects.IfaceSpeed2IfaceType[objects.GBPS1].Value || uplink.Speed != objects.GBPS1 || uplink.MTU != 9000 || uplink.MAC != "00:1A:2B:3C:4D:01" || !uplink.Status || uplink.Description != "uplink to sw02" {
+		t.Errorf("Gi1/0/1 = %+v, want enabled 1 Gbps interface with mtu 9000, mac and alias", uplink)
+	}
+	// Speed of Vl10 is reported only by ifSpeed
+	if vlan.Type.Value != objects.VirtualInterfaceType.Value || vlan.Status || vlan.Speed != objects.GBPS1 {
+		t.Errorf("Vl10 = %+v, want disabled 1 Gbps virtual interface", vlan)
+	}
+	ipAddress := nbi.IPAddressesIndexByVRFIDAndAddress[0]["10.0.10.1/24"]
+	if ipAddress == nil || ipAddress.AssignedObjectID != vlan.ID {
+		t.Errorf("ip address 10.0.10.1/24 = %v, want it assigned to Vl10", ipAddress)
+	}
+}
+
+func TestPollNeighbors(t *testing.T) {
+	port := startMockAgent(t)
+	client, err := newClient("127.0.0.1", port, testCommunity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Conn.Close()
+	device := &DeviceData{Interfaces: map[int]*InterfaceData{5: {IfIndex: 5, Name: "Gi1/0/1"}}}
+	if err := pollNeighbors(client, device); err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(device.Neighbors, func(i, j int) bool { return device.Neighbors[i].SysName > device.Neighbors[j].SysName })
+	expected := []NeighborData{
+		// Local port is matched to ifIndex by its port id
+		{LocalIfIndex: 5, SysName: "sw02.example.com", PortID: "Gi1/0/48", ManagementAddress: "10.0.10.2"},
+		// Local port without port id is used as ifIndex, and ipv6 management addresses are skipped
+		{LocalIfIndex: 2, SysName: "ap01.example.com", PortID: "eth0"},
+	}
+	if !reflect.DeepEqual(device.Neighbors, expected) {
+		t.Errorf("neighbors = %+v, want %+v", device.Neighbors, expected)
+	}
+}
+
+func TestInitWrongCommunity(t *testing.T) {
+	port := startMockAgent(t)
+	ss := newTestSource(t, port, "wrong")
+	if err := ss.Init(); err == nil {
+		t.Errorf("Init() expected error for wrong community")
+	}
+}
+
+func TestExpandTargets(t *testing.T) {
+	tests := []struct {
+		name             string
+		hostname         string
+		permittedSubnets []string
+		expected         []string
+		wantErr          bool
+	}{
+		{name: "Only hostname", hostname: "sw01.example.com", expected: []string{"sw01.example.com"}},
+		{name: "Single ip addresses", hostname: "10.0.0.1", permittedSubnets: []string{"10.0.0.1", "10.0.0.5"}, expected: []string{"10.0.0.1", "10.0.0.5"}},
+		{name: "Subnet without network and broadcast address", hostname: "10.0.0.2", permittedSubnets: []string{"10.0.0.0/29"}, expected: []string{"10.0.0.2", "10.0.0.1", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6"}},
+		{name: "Point to point subnet", hostname: "10.0.0.1", permittedSubnets: []string{"10.0.0.0/31"}, expected: []string{"10.0.0.1", "10.0.0.0"}},
+		{name: "Subnet too large", hostname: "10.0.0.1", permittedSubnets: []string{"10.0.0.0/8"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandTargets(tt.hostname, tt.permittedSubnets)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandTargets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expandTargets() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIfType2InterfaceType(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifType   int
+		speed    objects.InterfaceSpeed
+		expected *objects.InterfaceType
+	}{
+		{name: "Gigabit ethernet", ifType: 6, speed: objects.GBPS1, expected: objects.IfaceSpeed2IfaceType[objects.GBPS1]},
+		{name: "Ethernet with unknown speed", ifType: 6, speed: 0, expected: &objects.OtherInterfaceType},
+		{name: "Loopback", ifType: ifTypeSoftwareLoopback, expected: &objects.VirtualInterfaceType},
+		{name: "Port channel", ifType: ifTypeIEEE8023adLag, speed: objects.GBPS10, expected: &objects.LAGInterfaceType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ifType2InterfaceType(tt.ifType, tt.speed); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ifType2InterfaceType() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// newSyncSource returns source with the polled devices, that are all matched to a site
+// of the returned inventory.
+func newSyncSource(t *testing.T, devices ...*DeviceData) (*Source, *inventory.NetboxInventory, *inventorytest.FakeNetbox) {
+	t.Helper()
+	ss := newTestSource(t, 0, testCommunity)
+	ss.HostSiteRelations = map[string]string{".*": "site1"}
+	ss.Devices = make(map[string]*DeviceData)
+	for _, device := range devices {
+		ss.Devices[device.SysName] = device
+	}
+	nbi, fake := inventorytest.NewInventory(t)
+	if _, err := nbi.AddSite(&objects.Site{Name: "site1", Slug: "site1"}); err != nil {
+		t.Fatal(err)
+	}
+	return ss, nbi, fake
+}
+
+func TestSyncCables(t *testing.T) {
+	ss, nbi, fake := newSyncSource(t,
+		&DeviceData{
+			SysName:    "sw01",
+			Interfaces: map[int]*InterfaceData{1: {IfIndex: 1, Name: "Gi1/0/1"}},
+			Neighbors: []NeighborData{
+				{LocalIfIndex: 1, SysName: "sw02", PortID: "Gi1/0/48"},
+				{LocalIfIndex: 1, SysName: "ap01", PortID: "eth0", ManagementAddress: "10.0.10.3"},
+			},
+		},
+		&DeviceData{
+			SysName:    "sw02",
+			Interfaces: map[int]*InterfaceData{48: {IfIndex: 48, Name: "Gi1/0/48"}, 49: {IfIndex: 49, Name: "Gi1/0/49", MAC: "00:1A:2B:3C:4D:49"}},
+			Neighbors: []NeighborData{
+				{LocalIfIndex: 48, SysName: "sw01", PortID: "gi1/0/1"},
+				// Neighbor port advertised with its mac address, but not reported back by sw01
+				{LocalIfIndex: 49, SysName: "sw01", PortID: "00:1a:2b:3c:4d:01"},
+			},
+		},
+	)
+	if err := ss.Sync(nbi); err != nil {
+		t.Fatal(err)
+	}
+
+	var createdCables []inventorytest.Request
+	for _, request := range fake.Requests {
+		if request.Method == http.MethodPost && request.Path == service.CablesAPIPath {
+			createdCables = append(createdCables, request)
+		}
+	}
+	if len(createdCables) != 1 {
+		t.Fatalf("created cables = %v, want a single cable", createdCables)
+	}
+	localInterface := ss.SysName2nbInterfaces["sw01"][1]
+	neighborInterface := ss.SysName2nbInterfaces["sw02"][48]
+	cable := nbi.CablesIndexByInterfaceID[localInterface.ID]
+	if cable == nil || nbi.CablesIndexByInterfaceID[neighborInterface.ID] != cable {
+		t.Errorf("interfaces %s and %s are not connected with the same cable", localInterface.Name, neighborInterface.Name)
+	}
+}
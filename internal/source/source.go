@@ -9,15 +9,22 @@ import (
 	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
 	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/resolver"
 	"github.com/bl4ko/netbox-ssot/internal/source/common"
 	"github.com/bl4ko/netbox-ssot/internal/source/dnac"
+	"github.com/bl4ko/netbox-ssot/internal/source/netbox"
+	"github.com/bl4ko/netbox-ssot/internal/source/nutanix"
 	"github.com/bl4ko/netbox-ssot/internal/source/ovirt"
+	"github.com/bl4ko/netbox-ssot/internal/source/redfish"
+	"github.com/bl4ko/netbox-ssot/internal/source/snmp"
 	"github.com/bl4ko/netbox-ssot/internal/source/vmware"
+	"github.com/bl4ko/netbox-ssot/internal/source/xen"
 	"github.com/bl4ko/netbox-ssot/internal/utils"
 )
 
 // NewSource creates a Source from the given configuration.
-func NewSource(config *parser.SourceConfig, logger *logger.Logger, netboxInventory *inventory.NetboxInventory) (common.Source, error) {
+// Dns resolver is shared between all sources, so its cache is used across sources.
+func NewSource(config *parser.SourceConfig, logger *logger.Logger, netboxInventory *inventory.NetboxInventory, dnsResolver *resolver.Resolver) (common.Source, error) {
 	// First we create default tags for the source
 	sourceTag, err := netboxInventory.AddTag(&objects.Tag{
 		Name:        config.Tag,
@@ -41,6 +48,7 @@ func NewSource(config *parser.SourceConfig, logger *logger.Logger, netboxInvento
 		Logger:       logger,
 		SourceConfig: config,
 		SourceTags:   []*objects.Tag{sourceTag, sourceTypeTag},
+		Resolver:     dnsResolver,
 	}
 
 	switch config.Type {
@@ -50,6 +58,16 @@ func NewSource(config *parser.SourceConfig, logger *logger.Logger, netboxInvento
 		return &vmware.VmwareSource{Config: commonConfig}, nil
 	case constants.Dnac:
 		return &dnac.Source{Config: commonConfig}, nil
+	case constants.Nutanix:
+		return &nutanix.Source{Config: commonConfig}, nil
+	case constants.Xen:
+		return &xen.Source{Config: commonConfig}, nil
+	case constants.Redfish:
+		return &redfish.Source{Config: commonConfig}, nil
+	case constants.SNMP:
+		return &snmp.Source{Config: commonConfig}, nil
+	case constants.Netbox:
+		return &netbox.Source{Config: commonConfig}, nil
 	default:
 		return nil, fmt.Errorf("unsupported source type: %s", config.Type)
 	}
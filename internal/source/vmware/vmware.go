@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"time"
 
 	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
@@ -13,6 +14,10 @@ import (
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/session/cache"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -31,14 +36,28 @@ type VmwareSource struct {
 	Vms         map[string]mo.VirtualMachine
 	Networks    NetworkData
 
+	// Folders and resource pools are only used to resolve vm's location in the inventory
+	Folders       map[string]mo.Folder
+	ResourcePools map[string]mo.ResourcePool
+
 	// Relations between objects "object_id": "object_id"
 	Cluster2Datacenter map[string]string // ClusterKey -> DatacenterKey
 	Host2Cluster       map[string]string // HostKey -> ClusterKey
 	VM2Host            map[string]string // VmKey ->  HostKey
+	VM2FolderPath      map[string]string // VmKey -> full inventory folder path (e.g. /Team A/Production)
+	VM2ResourcePool    map[string]string // VmKey -> full resource pool path (e.g. /Team A/Databases)
 
 	// CustomField2Name is a map of custom field ids to their names
 	CustomFieldID2Name map[int32]string
 
+	// Object2Tags is a map of vsphere tags attached to clusters, hosts and vms
+	Object2Tags map[string][]VsphereTag // ObjectKey -> attached vsphere tags
+
+	// Incremental sync state. With incremental sync Vms contain only vms
+	// that have changed since the previous run
+	SyncState  *SyncState
+	DeletedVms map[string]string // VmKey -> vm name of vms deleted since the previous run
+
 	// Netbox relations
 	ClusterSiteRelations   map[string]string
 	ClusterTenantRelations map[string]string
@@ -48,8 +67,23 @@ type VmwareSource struct {
 	VlanGroupRelations     map[string]string
 	VlanTenantRelations    map[string]string
 
+	// Netbox relations matched against vm's folder path and resource pool
+	FolderSiteRelations         map[string]string
+	FolderTenantRelations       map[string]string
+	ResourcePoolSiteRelations   map[string]string
+	ResourcePoolTenantRelations map[string]string
+
 	// Mappings of custom fields to contacts
 	CustomFieldMappings map[string]string
+	// Mappings of vsphere tag categories to tenants or custom fields
+	TagCategoryMappings map[string]string
+}
+
+// VsphereTag represents a vsphere tag attached to an object.
+type VsphereTag struct {
+	Name        string
+	Description string
+	Category    string
 }
 
 type NetworkData struct {
@@ -102,8 +136,22 @@ func (vc *VmwareSource) Init() error {
 	vc.Logger.Debug("VlanGroupRelations: ", vc.VlanGroupRelations)
 	vc.VlanTenantRelations = utils.ConvertStringsToRegexPairs(vc.SourceConfig.VlanTenantRelations)
 	vc.Logger.Debug("VlanTenantRelations: ", vc.VlanTenantRelations)
+	vc.FolderSiteRelations = utils.ConvertStringsToRegexPairs(vc.SourceConfig.FolderSiteRelations)
+	vc.Logger.Debug("FolderSiteRelations: ", vc.FolderSiteRelations)
+	vc.FolderTenantRelations = utils.ConvertStringsToRegexPairs(vc.SourceConfig.FolderTenantRelations)
+	vc.Logger.Debug("FolderTenantRelations: ", vc.FolderTenantRelations)
+	vc.ResourcePoolSiteRelations = utils.ConvertStringsToRegexPairs(vc.SourceConfig.ResourcePoolSiteRelations)
+	vc.Logger.Debug("ResourcePoolSiteRelations: ", vc.ResourcePoolSiteRelations)
+	vc.ResourcePoolTenantRelations = utils.ConvertStringsToRegexPairs(vc.SourceConfig.ResourcePoolTenantRelations)
+	vc.Logger.Debug("ResourcePoolTenantRelations: ", vc.ResourcePoolTenantRelations)
+	vc.ClusterVRFRelations = utils.ConvertStringsToRegexPairs(vc.SourceConfig.ClusterVRFRelations)
+	vc.Logger.Debug("ClusterVRFRelations: ", vc.ClusterVRFRelations)
+	vc.SubnetVRFRelations = utils.ConvertStringsToPairs(vc.SourceConfig.SubnetVRFRelations)
+	vc.Logger.Debug("SubnetVRFRelations: ", vc.SubnetVRFRelations)
 	vc.CustomFieldMappings = utils.ConvertStringsToPairs(vc.SourceConfig.CustomFieldMappings)
 	vc.Logger.Debug("CustomFieldMappings: ", vc.CustomFieldMappings)
+	vc.TagCategoryMappings = utils.ConvertStringsToPairs(vc.SourceConfig.TagCategoryMappings)
+	vc.Logger.Debug("TagCategoryMappings: ", vc.TagCategoryMappings)
 
 	// Initialize the connection
 	vc.Logger.Debug("Initializing oVirt source ", vc.SourceConfig.Name)
@@ -121,24 +169,24 @@ func (vc *VmwareSource) Init() error {
 		return fmt.Errorf("failed parsing url for %s with error %s", vc.SourceConfig.Hostname, err)
 	}
 
-	conn, err := govmomi.NewClient(ctx, url, !vc.SourceConfig.ValidateCert)
+	client, err := vc.newClient(ctx, url)
 	if err != nil {
-		return fmt.Errorf("failed creating a govmomi client with an error: %s", err)
+		return err
 	}
 
 	// View manager is used to create and manage views. Views are a mechanism in vSphere
 	// to group and manage objects in the inventory.
-	viewManager := view.NewManager(conn.Client)
+	viewManager := view.NewManager(client)
 
 	// viewType specifies the types of objects to be included in our container view.
 	// Each string in this slice represents a different vSphere Managed Object type.
 	viewType := []string{
-		"Datastore", "Datacenter", "ClusterComputeResource", "HostSystem", "VirtualMachine", "Network",
+		"Datastore", "Datacenter", "ClusterComputeResource", "HostSystem", "VirtualMachine", "Network", "Folder", "ResourcePool",
 	}
 
 	// A container view is a subset of the vSphere inventory, focusing on the specified
 	// object types, making it easier to manage and retrieve data for these objects.
-	containerView, err := viewManager.CreateContainerView(ctx, conn.Client.ServiceContent.RootFolder, viewType, true)
+	containerView, err := viewManager.CreateContainerView(ctx, client.ServiceContent.RootFolder, viewType, true)
 	if err != nil {
 		return fmt.Errorf("failed creating containerView: %s", err)
 	}
@@ -148,18 +196,24 @@ func (vc *VmwareSource) Init() error {
 	// Create CustomFieldManager to map custom field ids to their names
 	// This is required to determine which custom field key is used for
 	// which custom field name (e.g.g 202 -> vm owner, 203 -> vm description...)
-	err = vc.CreateCustomFieldRelation(ctx, conn.Client)
+	err = vc.CreateCustomFieldRelation(ctx, client)
 	if err != nil {
 		return fmt.Errorf("create custom field relation failed: %s", err)
 	}
 
 	// Find relation between data centers and clusters. Currently we have to manually traverse
 	// the tree to get this relation.
-	err = vc.CreateClusterDataCenterRelation(ctx, conn.Client)
+	err = vc.CreateClusterDataCenterRelation(ctx, client)
 	if err != nil {
 		return fmt.Errorf("create cluster datacenter relation failed: %s", err)
 	}
 
+	// With incremental sync only vms changed since the previous run are retrieved
+	initVms := vc.InitVms
+	if vc.SourceConfig.IncrementalSync {
+		initVms = vc.InitVmsIncremental
+	}
+
 	// Initialize items from vsphere API to local storage
 	initFunctions := []func(context.Context, *view.ContainerView) error{
 		vc.InitNetworks,
@@ -167,7 +221,9 @@ func (vc *VmwareSource) Init() error {
 		vc.InitDataCenters,
 		vc.InitClusters,
 		vc.InitHosts,
-		vc.InitVms,
+		vc.InitFolders,
+		vc.InitResourcePools,
+		initVms,
 	}
 
 	for _, initFunc := range initFunctions {
@@ -179,13 +235,29 @@ func (vc *VmwareSource) Init() error {
 		vc.Logger.Infof("Successfully initialized %s in %f seconds", utils.ExtractFunctionName(initFunc), duration.Seconds())
 	}
 
+	// Vsphere tags are available only through the vAPI, so we collect them separately
+	if vc.SourceConfig.SyncTags || len(vc.TagCategoryMappings) > 0 {
+		startTime := time.Now()
+		err = vc.CreateTagRelation(ctx, client)
+		if err != nil {
+			return fmt.Errorf("create tag relation failed: %s", err)
+		}
+		vc.Logger.Infof("Successfully initialized vsphere tags in %f seconds", time.Since(startTime).Seconds())
+	}
+
 	// Ensure the containerView is destroyed after we are done with it
 	err = containerView.Destroy(ctx)
 	if err != nil {
 		vc.Logger.Errorf("failed destroying containerView: %s", err)
 	}
 
-	err = conn.Logout(ctx)
+	// With incremental sync the session is kept alive, so the property collector
+	// can be reused in the next run
+	if vc.SourceConfig.IncrementalSync {
+		return nil
+	}
+
+	err = session.NewManager(client).Logout(ctx)
 	if err != nil {
 		return fmt.Errorf("error occurred when ending vmware connection to host %s: %s", vc.SourceConfig.Hostname, err)
 	}
@@ -195,6 +267,28 @@ func (vc *VmwareSource) Init() error {
 	return nil
 }
 
+// newClient creates an authenticated vsphere client. With incremental sync the session
+// is cached in SyncStateDir, so that subsequent runs reuse it while it is still valid.
+func (vc *VmwareSource) newClient(ctx context.Context, url *url.URL) (*vim25.Client, error) {
+	if vc.SourceConfig.IncrementalSync {
+		sessionCache := &cache.Session{
+			URL:      url,
+			Insecure: !vc.SourceConfig.ValidateCert,
+			DirSOAP:  filepath.Join(vc.SourceConfig.SyncStateDir, "sessions"),
+		}
+		client := new(vim25.Client)
+		if err := sessionCache.Login(ctx, client, nil); err != nil {
+			return nil, fmt.Errorf("failed creating a cached vsphere session with an error: %s", err)
+		}
+		return client, nil
+	}
+	conn, err := govmomi.NewClient(ctx, url, !vc.SourceConfig.ValidateCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating a govmomi client with an error: %s", err)
+	}
+	return conn.Client, nil
+}
+
 // Function that syncs all data from oVirt to Netbox.
 func (vc *VmwareSource) Sync(nbi *inventory.NetboxInventory) error {
 	syncFunctions := []func(*inventory.NetboxInventory) error{
@@ -204,6 +298,12 @@ func (vc *VmwareSource) Sync(nbi *inventory.NetboxInventory) error {
 		vc.syncHosts,
 		vc.syncVms,
 	}
+	if vc.SourceConfig.IncrementalSync {
+		syncFunctions = append(syncFunctions, vc.retainUnchangedVms)
+	}
+	if vc.SourceConfig.AutoPrefixes {
+		syncFunctions = append(syncFunctions, vc.SyncAutoPrefixes)
+	}
 	for _, syncFunc := range syncFunctions {
 		startTime := time.Now()
 		err := syncFunc(nbi)
@@ -213,6 +313,12 @@ func (vc *VmwareSource) Sync(nbi *inventory.NetboxInventory) error {
 		duration := time.Since(startTime)
 		vc.Logger.Infof("Successfully synced %s in %f seconds", utils.ExtractFunctionName(syncFunc), duration.Seconds())
 	}
+	// Sync state is persisted only after successful sync, so failed runs are retried
+	if vc.SourceConfig.IncrementalSync {
+		if err := vc.saveSyncState(); err != nil {
+			return fmt.Errorf("save sync state: %s", err)
+		}
+	}
 	return nil
 }
 
@@ -256,3 +362,59 @@ func (vc *VmwareSource) CreateCustomFieldRelation(ctx context.Context, client *v
 
 	return nil
 }
+
+// Creates a map of clusters, hosts and vms to their attached vsphere tags.
+// Tags are retrieved from the vAPI tagging service, which requires a separate rest session.
+func (vc *VmwareSource) CreateTagRelation(ctx context.Context, client *vim25.Client) error {
+	restClient := rest.NewClient(client)
+	err := restClient.Login(ctx, url.UserPassword(vc.SourceConfig.Username, vc.SourceConfig.Password))
+	if err != nil {
+		return fmt.Errorf("vapi login: %s", err)
+	}
+	defer func() {
+		if err := restClient.Logout(ctx); err != nil {
+			vc.Logger.Errorf("failed ending vapi session: %s", err)
+		}
+	}()
+
+	tagManager := tags.NewManager(restClient)
+	categories, err := tagManager.GetCategories(ctx)
+	if err != nil {
+		return fmt.Errorf("tag categories: %s", err)
+	}
+	categoryID2Name := make(map[string]string, len(categories))
+	for _, category := range categories {
+		categoryID2Name[category.ID] = category.Name
+	}
+
+	objectRefs := make([]mo.Reference, 0, len(vc.Clusters)+len(vc.Hosts)+len(vc.Vms))
+	for _, cluster := range vc.Clusters {
+		objectRefs = append(objectRefs, cluster.Self)
+	}
+	for _, host := range vc.Hosts {
+		objectRefs = append(objectRefs, host.Self)
+	}
+	for _, vm := range vc.Vms {
+		objectRefs = append(objectRefs, vm.Self)
+	}
+
+	vc.Object2Tags = make(map[string][]VsphereTag)
+	if len(objectRefs) == 0 {
+		return nil
+	}
+	attachedTags, err := tagManager.GetAttachedTagsOnObjects(ctx, objectRefs)
+	if err != nil {
+		return fmt.Errorf("attached tags: %s", err)
+	}
+	for _, attached := range attachedTags {
+		objectKey := attached.ObjectID.Reference().Value
+		for _, tag := range attached.Tags {
+			vc.Object2Tags[objectKey] = append(vc.Object2Tags[objectKey], VsphereTag{
+				Name:        tag.Name,
+				Description: tag.Description,
+				Category:    categoryID2Name[tag.CategoryID],
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package vmware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// vmChangeProperties are properties of vms, that are watched for changes in incremental sync.
+// Frequently changing properties (e.g. summary.quickStats) are intentionally left out,
+// otherwise every running vm would be reported as changed.
+//
+// Vsphere tag assignments and renames of vm's folders and resource pools are not
+// reported by the property collector. They are detected by outdatedVM instead.
+var vmChangeProperties = []string{
+	"name", "runtime.powerState", "runtime.host", "config.hardware", "config.guestFullName", "config.template",
+	"guest.net", "guest.ipStack", "guest.guestFullName", "summary.customValue", "parent", "resourcePool",
+}
+
+// SyncState is persisted between runs of incremental sync.
+//
+// Version token is valid only for the property collector that returned it, and property
+// collector lives only as long as vsphere session. That is why the session itself is
+// persisted using govmomi's session cache.
+type SyncState struct {
+	// Collector is the value of the property collector's managed object reference
+	Collector string `json:"collector"`
+	// Version is the version token of the last received update set
+	Version string `json:"version"`
+	// VMs that were synced in previous runs (VmKey -> vm)
+	VMs map[string]SyncedVM `json:"vms"`
+}
+
+// SyncedVM is a vm that was synced in previous runs of incremental sync.
+type SyncedVM struct {
+	// Name of the vm
+	Name string `json:"name"`
+	// SourceID is the value of vm's source_id custom field in netbox
+	SourceID string `json:"source_id"`
+	// Parent is the reference of vm's folder
+	Parent *types.ManagedObjectReference `json:"parent"`
+	// ResourcePool is the reference of vm's resource pool
+	ResourcePool *types.ManagedObjectReference `json:"resource_pool"`
+	// FolderPath is the synced full inventory folder path of the vm
+	FolderPath string `json:"folder_path"`
+	// ResourcePoolPath is the synced full resource pool path of the vm
+	ResourcePoolPath string `json:"resource_pool_path"`
+	// Missing is set, when the unchanged vm was not found in netbox (e.g. it was deleted
+	// by hand), so it is synced again on the next run
+	Missing bool `json:"missing,omitempty"`
+}
+
+// syncStatePath returns path of the file, where sync state of the source is persisted.
+func (vc *VmwareSource) syncStatePath() string {
+	return filepath.Join(vc.SourceConfig.SyncStateDir, fmt.Sprintf("%s.json", utils.Slugify(vc.SourceConfig.Name)))
+}
+
+// loadSyncState loads sync state from the previous run. If there is no
+// valid sync state, empty sync state is returned.
+func (vc *VmwareSource) loadSyncState() *SyncState {
+	state := &SyncState{VMs: make(map[string]SyncedVM)}
+	content, err := os.ReadFile(vc.syncStatePath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			vc.Logger.Warningf("failed reading sync state: %s", err)
+		}
+		return state
+	}
+	if err := json.Unmarshal(content, state); err != nil {
+		vc.Logger.Warningf("failed parsing sync state: %s", err)
+		return &SyncState{VMs: make(map[string]SyncedVM)}
+	}
+	if state.VMs == nil {
+		state.VMs = make(map[string]SyncedVM)
+	}
+	return state
+}
+
+// saveSyncState persists sync state, so the next run can continue from it.
+func (vc *VmwareSource) saveSyncState() error {
+	content, err := json.Marshal(vc.SyncState)
+	if err != nil {
+		return fmt.Errorf("marshal sync state: %s", err)
+	}
+	if err := os.MkdirAll(vc.SourceConfig.SyncStateDir, 0700); err != nil {
+		return fmt.Errorf("sync state dir: %s", err)
+	}
+	if err := os.WriteFile(vc.syncStatePath(), content, 0600); err != nil {
+		return fmt.Errorf("write sync state: %s", err)
+	}
+	return nil
+}
+
+// InitVmsIncremental retrieves only vms that have changed since the previous run.
+// Changes are received from property collector's WaitForUpdatesEx, using version token
+// of the previous run. When that is not possible (e.g. first run, or vsphere session
+// has expired in the meantime), all vms are retrieved and a new property collector is created.
+//
+// Vms deleted since the previous run are stored in DeletedVms, and are not retained
+// in orphan manager.
+func (vc *VmwareSource) InitVmsIncremental(ctx context.Context, containerView *view.ContainerView) error {
+	client := containerView.Client()
+	vc.SyncState = vc.loadSyncState()
+	vc.DeletedVms = make(map[string]string)
+
+	var changes map[string]types.ObjectUpdateKind
+	var err error
+	if vc.SyncState.Collector != "" {
+		collector := types.ManagedObjectReference{Type: "PropertyCollector", Value: vc.SyncState.Collector}
+		changes, vc.SyncState.Version, err = waitForUpdates(ctx, client, collector, vc.SyncState.Version)
+		if err != nil {
+			vc.Logger.Warningf("incremental sync is not possible, falling back to full sync: %s", err)
+		}
+	}
+	if vc.SyncState.Collector == "" || err != nil {
+		collector, err := createVMCollector(ctx, client)
+		if err != nil {
+			return fmt.Errorf("create property collector: %s", err)
+		}
+		vc.SyncState = &SyncState{Collector: collector.Value, VMs: vc.SyncState.VMs}
+		changes, vc.SyncState.Version, err = waitForUpdates(ctx, client, collector, "")
+		if err != nil {
+			return fmt.Errorf("initial vm updates: %s", err)
+		}
+		// Vms that don't exist anymore are not reported on the new collector
+		for vmKey := range vc.SyncState.VMs {
+			if _, ok := changes[vmKey]; !ok {
+				changes[vmKey] = types.ObjectUpdateKindLeave
+			}
+		}
+	}
+
+	for vmKey, syncedVM := range vc.SyncState.VMs {
+		if _, changed := changes[vmKey]; !changed && vc.outdatedVM(syncedVM) {
+			changes[vmKey] = types.ObjectUpdateKindModify
+		}
+	}
+
+	changedVMs := make([]types.ManagedObjectReference, 0, len(changes))
+	for vmKey, kind := range changes {
+		if kind == types.ObjectUpdateKindLeave {
+			if syncedVM, ok := vc.SyncState.VMs[vmKey]; ok {
+				vc.DeletedVms[vmKey] = syncedVM.Name
+				delete(vc.SyncState.VMs, vmKey)
+			}
+			continue
+		}
+		changedVMs = append(changedVMs, types.ManagedObjectReference{Type: "VirtualMachine", Value: vmKey})
+	}
+
+	var vms []mo.VirtualMachine
+	if len(changedVMs) > 0 {
+		err = property.DefaultCollector(client).Retrieve(ctx, changedVMs, vmProperties, &vms)
+		if err != nil {
+			return fmt.Errorf("failed retrieving changed vms: %s", err)
+		}
+	}
+	vc.addVms(vms)
+	for _, vm := range vms {
+		if vm.Config != nil && vm.Config.Template {
+			delete(vc.SyncState.VMs, vm.Self.Value)
+			continue
+		}
+		vc.SyncState.VMs[vm.Self.Value] = SyncedVM{
+			Name:             vm.Name,
+			SourceID:         vmSourceID(vm),
+			Parent:           vm.Parent,
+			ResourcePool:     vm.ResourcePool,
+			FolderPath:       vc.VM2FolderPath[vm.Self.Value],
+			ResourcePoolPath: vc.VM2ResourcePool[vm.Self.Value],
+		}
+	}
+	vc.Logger.Infof("Incremental sync: %d changed vms, %d deleted vms", len(vms), len(vc.DeletedVms))
+	return nil
+}
+
+// outdatedVM returns true if the vm has to be synced again, even though the property
+// collector didn't report any changes. Vms missing in netbox are synced again. Vsphere
+// tags can't be watched, so when they are synced, all vms are synced on every run. Renamed
+// folders and resource pools are detected by comparing their current paths with the synced ones.
+func (vc *VmwareSource) outdatedVM(syncedVM SyncedVM) bool {
+	if syncedVM.Missing || vc.SourceConfig.SyncTags || len(vc.TagCategoryMappings) > 0 {
+		return true
+	}
+	// Sync state from older versions doesn't contain vm's folder
+	if syncedVM.Parent == nil {
+		return true
+	}
+	resourcePoolPath := ""
+	if syncedVM.ResourcePool != nil {
+		resourcePoolPath = vc.resourcePoolPath(syncedVM.ResourcePool)
+	}
+	return vc.folderPath(syncedVM.Parent) != syncedVM.FolderPath || resourcePoolPath != syncedVM.ResourcePoolPath
+}
+
+// createVMCollector creates a new property collector, with a filter that watches
+// vmChangeProperties of all vms in the inventory.
+func createVMCollector(ctx context.Context, client *vim25.Client) (types.ManagedObjectReference, error) {
+	// Container view is not destroyed, because the filter depends on it.
+	// It is cleaned up together with the session.
+	vmView, err := view.NewManager(client).CreateContainerView(ctx, client.ServiceContent.RootFolder, []string{"VirtualMachine"}, true)
+	if err != nil {
+		return types.ManagedObjectReference{}, fmt.Errorf("container view: %s", err)
+	}
+	collector, err := property.DefaultCollector(client).Create(ctx)
+	if err != nil {
+		return types.ManagedObjectReference{}, err
+	}
+	_, err = collector.CreateFilter(ctx, types.CreateFilter{
+		Spec: types.PropertyFilterSpec{
+			ObjectSet: []types.ObjectSpec{
+				{
+					Obj:  vmView.Reference(),
+					Skip: types.NewBool(true),
+					SelectSet: []types.BaseSelectionSpec{
+						&types.TraversalSpec{Type: "ContainerView", Path: "view"},
+					},
+				},
+			},
+			PropSet: []types.PropertySpec{{Type: "VirtualMachine", PathSet: vmChangeProperties}},
+		},
+	})
+	if err != nil {
+		return types.ManagedObjectReference{}, fmt.Errorf("create filter: %s", err)
+	}
+	return collector.Reference(), nil
+}
+
+// waitForUpdates collects all pending updates of the property collector since version,
+// without waiting for new ones. It returns the last update kind of each changed object
+// (ObjectKey -> kind) and the new version token.
+func waitForUpdates(ctx context.Context, client *vim25.Client, collector types.ManagedObjectReference, version string) (map[string]types.ObjectUpdateKind, string, error) {
+	changes := make(map[string]types.ObjectUpdateKind)
+	maxWaitSeconds := int32(0)
+	for {
+		res, err := methods.WaitForUpdatesEx(ctx, client, &types.WaitForUpdatesEx{
+			This:    collector,
+			Version: version,
+			Options: &types.WaitOptions{MaxWaitSeconds: &maxWaitSeconds},
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		updateSet := res.Returnval
+		// There are no more pending updates
+		if updateSet == nil {
+			return changes, version, nil
+		}
+		version = updateSet.Version
+		for _, filterUpdate := range updateSet.FilterSet {
+			for _, objectUpdate := range filterUpdate.ObjectSet {
+				changes[objectUpdate.Obj.Value] = objectUpdate.Kind
+			}
+		}
+		if updateSet.Truncated == nil || !*updateSet.Truncated {
+			return changes, version, nil
+		}
+	}
+}
+
+// retainUnchangedVms retains vms that haven't changed since the previous run
+// (and objects that depend on them) in netbox, by removing them from orphan manager.
+// Unchanged vms, that are not found in netbox, are marked as missing in the sync state,
+// so they are synced again on the next run.
+func (vc *VmwareSource) retainUnchangedVms(nbi *inventory.NetboxInventory) error {
+	unchangedVMs := make([]*objects.VM, 0, len(vc.SyncState.VMs))
+	for vmKey, syncedVM := range vc.SyncState.VMs {
+		if _, changed := vc.Vms[vmKey]; changed {
+			continue
+		}
+		nbVM, ok := nbi.GetVM(&objects.VM{
+			NetboxObject: objects.NetboxObject{
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   vc.SourceConfig.Name,
+					constants.CustomFieldSourceIDName: syncedVM.SourceID,
+				},
+			},
+		})
+		if !ok {
+			vc.Logger.Infof("unchanged vm %s was not found in netbox, it will be synced again on the next run", syncedVM.Name)
+			syncedVM.Missing = true
+			vc.SyncState.VMs[vmKey] = syncedVM
+			continue
+		}
+		unchangedVMs = append(unchangedVMs, nbVM)
+	}
+	nbi.RetainVMs(unchangedVMs)
+	for _, vmName := range vc.DeletedVms {
+		vc.Logger.Infof("vm %s was deleted from vsphere, leaving it to orphan manager", vmName)
+	}
+	return nil
+}
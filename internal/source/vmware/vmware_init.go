@@ -3,6 +3,8 @@ package vmware
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
 	"github.com/vmware/govmomi/view"
@@ -105,7 +107,7 @@ func (vc *VmwareSource) InitDataCenters(ctx context.Context, containerView *view
 
 func (vc *VmwareSource) InitClusters(ctx context.Context, containerView *view.ContainerView) error {
 	var clusters []mo.ClusterComputeResource
-	err := containerView.Retrieve(ctx, []string{"ClusterComputeResource"}, []string{"summary", "host", "name"}, &clusters)
+	err := containerView.Retrieve(ctx, []string{"ClusterComputeResource"}, []string{"summary", "host", "name", "customValue"}, &clusters)
 	if err != nil {
 		return fmt.Errorf("failed retrieving clusters: %s", err)
 	}
@@ -122,7 +124,7 @@ func (vc *VmwareSource) InitClusters(ctx context.Context, containerView *view.Co
 
 func (vc *VmwareSource) InitHosts(ctx context.Context, containerView *view.ContainerView) error {
 	var hosts []mo.HostSystem
-	err := containerView.Retrieve(ctx, []string{"HostSystem"}, []string{"name", "summary.host", "summary.hardware", "summary.runtime", "summary.config", "vm", "config.network"}, &hosts)
+	err := containerView.Retrieve(ctx, []string{"HostSystem"}, []string{"name", "summary.host", "summary.hardware", "summary.runtime", "summary.config", "vm", "config.network", "customValue"}, &hosts)
 	if err != nil {
 		return fmt.Errorf("failed retrieving hosts: %s", err)
 	}
@@ -180,15 +182,94 @@ func (vc *VmwareSource) InitHosts(ctx context.Context, containerView *view.Conta
 	return nil
 }
 
+// InitFolders retrieves all inventory folders, so we can later resolve full folder path of each vm.
+func (vc *VmwareSource) InitFolders(ctx context.Context, containerView *view.ContainerView) error {
+	var folders []mo.Folder
+	err := containerView.Retrieve(ctx, []string{"Folder"}, []string{"name", "parent"}, &folders)
+	if err != nil {
+		return fmt.Errorf("failed retrieving folders: %s", err)
+	}
+	vc.Folders = make(map[string]mo.Folder, len(folders))
+	for _, folder := range folders {
+		vc.Folders[folder.Self.Value] = folder
+	}
+	return nil
+}
+
+// InitResourcePools retrieves all resource pools (including vApps),
+// so we can later resolve full resource pool path of each vm.
+func (vc *VmwareSource) InitResourcePools(ctx context.Context, containerView *view.ContainerView) error {
+	var resourcePools []mo.ResourcePool
+	err := containerView.Retrieve(ctx, []string{"ResourcePool"}, []string{"name", "parent"}, &resourcePools)
+	if err != nil {
+		return fmt.Errorf("failed retrieving resource pools: %s", err)
+	}
+	vc.ResourcePools = make(map[string]mo.ResourcePool, len(resourcePools))
+	for _, resourcePool := range resourcePools {
+		vc.ResourcePools[resourcePool.Self.Value] = resourcePool
+	}
+	return nil
+}
+
+// vmProperties are properties of vms, that are retrieved from vsphere API.
+var vmProperties = []string{"summary", "name", "runtime", "guest", "config.hardware", "config.guestFullName", "config.template", "parent", "resourcePool"}
+
 func (vc *VmwareSource) InitVms(ctx context.Context, containerView *view.ContainerView) error {
 	var vms []mo.VirtualMachine
-	err := containerView.Retrieve(ctx, []string{"VirtualMachine"}, []string{"summary", "name", "runtime", "guest", "config.hardware", "config.guestFullName"}, &vms)
+	err := containerView.Retrieve(ctx, []string{"VirtualMachine"}, vmProperties, &vms)
 	if err != nil {
 		return fmt.Errorf("failed retrieving vms: %s", err)
 	}
+	vc.addVms(vms)
+	return nil
+}
+
+// addVms stores vms to local storage, together with their folder path and resource pool.
+func (vc *VmwareSource) addVms(vms []mo.VirtualMachine) {
 	vc.Vms = make(map[string]mo.VirtualMachine, len(vms))
+	vc.VM2FolderPath = make(map[string]string, len(vms))
+	vc.VM2ResourcePool = make(map[string]string, len(vms))
 	for _, vm := range vms {
 		vc.Vms[vm.Self.Value] = vm
+		vc.VM2FolderPath[vm.Self.Value] = vc.folderPath(vm.Parent)
+		if vm.ResourcePool != nil {
+			vc.VM2ResourcePool[vm.Self.Value] = vc.resourcePoolPath(vm.ResourcePool)
+		}
 	}
-	return nil
+}
+
+// folderPath returns full inventory path of the folder (e.g. /Team A/Production).
+// Datacenter's root vm folder is not a part of the path, so vms placed directly
+// in the datacenter have path "/".
+func (vc *VmwareSource) folderPath(folderRef *types.ManagedObjectReference) string {
+	names := []string{}
+	for folderRef != nil && folderRef.Type == "Folder" {
+		folder, ok := vc.Folders[folderRef.Value]
+		// Root folder's parent is a datacenter
+		if !ok || folder.Parent == nil || folder.Parent.Type != "Folder" {
+			break
+		}
+		names = append(names, folder.Name)
+		folderRef = folder.Parent
+	}
+	slices.Reverse(names)
+	return "/" + strings.Join(names, "/")
+}
+
+// resourcePoolPath returns full path of the resource pool (e.g. /Team A/Databases).
+// Cluster's root resource pool (Resources) is not a part of the path,
+// so vms placed directly in the cluster have path "/".
+func (vc *VmwareSource) resourcePoolPath(resourcePoolRef *types.ManagedObjectReference) string {
+	names := []string{}
+	for resourcePoolRef != nil {
+		resourcePool, ok := vc.ResourcePools[resourcePoolRef.Value]
+		// Root resource pool's parent is a compute resource
+		if !ok || resourcePool.Parent == nil || (resourcePool.Parent.Type != "ResourcePool" && resourcePool.Parent.Type != "VirtualApp") {
+			break
+		}
+		names = append(names, resourcePool.Name)
+		resourcePoolRef = resourcePool.Parent
+	}
+	slices.Reverse(names)
+	return "/" + strings.Join(names, "/")
 }
@@ -2,13 +2,16 @@ package vmware
 
 import (
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bl4ko/netbox-ssot/internal/constants"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
 	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
 	"github.com/bl4ko/netbox-ssot/internal/source/common"
 	"github.com/bl4ko/netbox-ssot/internal/utils"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -32,7 +35,7 @@ func (vc *VmwareSource) syncNetworks(nbi *inventory.NetboxInventory) error {
 			_, err := nbi.AddVlan(&objects.Vlan{
 				NetboxObject: objects.NetboxObject{
 					Tags: vc.Config.SourceTags,
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldSourceName: vc.SourceConfig.Name,
 					},
 				},
@@ -56,7 +59,7 @@ func (vc *VmwareSource) syncDatacenters(nbi *inventory.NetboxInventory) error {
 			NetboxObject: objects.NetboxObject{
 				Description: fmt.Sprintf("Datacenter from source %s", vc.SourceConfig.Hostname),
 				Tags:        vc.Config.SourceTags,
-				CustomFields: map[string]string{
+				CustomFields: map[string]interface{}{
 					constants.CustomFieldSourceName: vc.SourceConfig.Name,
 				},
 			},
@@ -75,7 +78,7 @@ func (vc *VmwareSource) syncClusters(nbi *inventory.NetboxInventory) error {
 	clusterType := &objects.ClusterType{
 		NetboxObject: objects.NetboxObject{
 			Tags: vc.Config.SourceTags,
-			CustomFields: map[string]string{
+			CustomFields: map[string]interface{}{
 				constants.CustomFieldSourceName: vc.SourceConfig.Name,
 			},
 		},
@@ -122,12 +125,25 @@ func (vc *VmwareSource) syncClusters(nbi *inventory.NetboxInventory) error {
 			}
 		}
 
+		clusterTagData, err := vc.mapVsphereTags(nbi, clusterID)
+		if err != nil {
+			return fmt.Errorf("cluster's vsphere tags: %s", err)
+		}
+		if clusterTenant == nil {
+			clusterTenant = clusterTagData.Tenant
+		}
+		clusterAttributeData, err := vc.mapCustomAttributes(nbi, cluster.CustomValue, "virtualization.cluster")
+		if err != nil {
+			return fmt.Errorf("cluster's custom attributes: %s", err)
+		}
+		clusterCustomFields := clusterAttributeData.CustomFields
+		maps.Copy(clusterCustomFields, clusterTagData.CustomFields)
+		clusterCustomFields[constants.CustomFieldSourceName] = vc.SourceConfig.Name
+
 		nbCluster := &objects.Cluster{
 			NetboxObject: objects.NetboxObject{
-				Tags: vc.Config.SourceTags,
-				CustomFields: map[string]string{
-					constants.CustomFieldSourceName: vc.SourceConfig.Name,
-				},
+				Tags:         clusterTagData.Tags,
+				CustomFields: clusterCustomFields,
 			},
 			Name:   clusterName,
 			Type:   clusterType,
@@ -136,7 +152,7 @@ func (vc *VmwareSource) syncClusters(nbi *inventory.NetboxInventory) error {
 			Site:   clusterSite,
 			Tenant: clusterTenant,
 		}
-		err := nbi.AddCluster(nbCluster)
+		err = nbi.AddCluster(nbCluster)
 		if err != nil {
 			return fmt.Errorf("failed to add vmware cluster %s as Netbox cluster: %v", clusterName, err)
 		}
@@ -226,12 +242,26 @@ func (vc *VmwareSource) syncHosts(nbi *inventory.NetboxInventory) error {
 		hostCPUCores := host.Summary.Hardware.NumCpuCores
 		hostMemGB := host.Summary.Hardware.MemorySize / constants.KiB / constants.KiB / constants.KiB
 
+		hostTagData, err := vc.mapVsphereTags(nbi, hostID)
+		if err != nil {
+			return fmt.Errorf("host's vsphere tags: %s", err)
+		}
+		if hostTenant == nil {
+			hostTenant = hostTagData.Tenant
+		}
+		hostAttributeData, err := vc.mapCustomAttributes(nbi, host.CustomValue, "dcim.device")
+		if err != nil {
+			return fmt.Errorf("host's custom attributes: %s", err)
+		}
+		hostCustomFields := hostAttributeData.CustomFields
+		maps.Copy(hostCustomFields, hostTagData.CustomFields)
+		hostCustomFields[constants.CustomFieldSourceName] = vc.SourceConfig.Name
+		hostCustomFields[constants.CustomFieldSourceIDName] = host.Self.Value
+		hostCustomFields[constants.CustomFieldHostCPUCoresName] = fmt.Sprintf("%d", hostCPUCores)
+		hostCustomFields[constants.CustomFieldHostMemoryName] = fmt.Sprintf("%d GB", hostMemGB)
+
 		nbHost := &objects.Device{
-			NetboxObject: objects.NetboxObject{Tags: vc.Config.SourceTags, CustomFields: map[string]string{
-				constants.CustomFieldSourceName:       vc.SourceConfig.Name,
-				constants.CustomFieldHostCPUCoresName: fmt.Sprintf("%d", hostCPUCores),
-				constants.CustomFieldHostMemoryName:   fmt.Sprintf("%d GB", hostMemGB),
-			}},
+			NetboxObject: objects.NetboxObject{Tags: hostTagData.Tags, CustomFields: hostCustomFields},
 			Name:         hostName,
 			Status:       hostStatus,
 			Platform:     hostPlatform,
@@ -358,7 +388,7 @@ func (vc *VmwareSource) syncHostPhysicalNics(nbi *inventory.NetboxInventory, vcH
 						newVlan, err = nbi.AddVlan(&objects.Vlan{
 							NetboxObject: objects.NetboxObject{
 								Tags: vc.Config.SourceTags,
-								CustomFields: map[string]string{
+								CustomFields: map[string]interface{}{
 									constants.CustomFieldSourceName: vc.SourceConfig.Name,
 								},
 							},
@@ -408,8 +438,9 @@ func (vc *VmwareSource) syncHostPhysicalNics(nbi *inventory.NetboxInventory, vcH
 			NetboxObject: objects.NetboxObject{
 				Tags:        vc.Config.SourceTags,
 				Description: pnicDescription,
-				CustomFields: map[string]string{
-					constants.CustomFieldSourceName: vc.SourceConfig.Name,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   vc.SourceConfig.Name,
+					constants.CustomFieldSourceIDName: hostNicSourceID(vcHost, pnic.Key),
 				},
 			},
 			Device:      nbHost,
@@ -442,45 +473,60 @@ func (vc *VmwareSource) syncHostVirtualNics(nbi *inventory.NetboxInventory, vcHo
 			return err
 		}
 
-		// Get IPv4 address for this vnic. TODO: filter
+		// Get IPv4 address for this vnic
 		ipv4Address := vnic.Spec.Ip.IpAddress
-		ipv4MaskBits, err := utils.MaskToBits(vnic.Spec.Ip.SubnetMask)
-		if err != nil {
-			return fmt.Errorf("mask to bits: %s", err)
-		}
-		ipv4DNS := utils.ReverseLookup(ipv4Address)
-		nbIPv4Address, err := nbi.AddIPAddress(&objects.IPAddress{
-			NetboxObject: objects.NetboxObject{
-				Tags: vc.Config.SourceTags,
-				CustomFields: map[string]string{
-					constants.CustomFieldSourceName: vc.SourceConfig.Name,
+		if vc.IsPermittedIPAddress(ipv4Address) {
+			ipv4MaskBits, err := utils.MaskToBits(vnic.Spec.Ip.SubnetMask)
+			if err != nil {
+				return fmt.Errorf("mask to bits: %s", err)
+			}
+			ipv4DNS := vc.DNSName(ipv4Address, "")
+			ipv4VRF, err := vc.MatchIPToVRF(nbi, ipv4Address, nbHost.Cluster)
+			if err != nil {
+				return fmt.Errorf("match ip to vrf: %s", err)
+			}
+			nbIPv4Address, err := nbi.AddIPAddress(&objects.IPAddress{
+				NetboxObject: objects.NetboxObject{
+					Tags: vc.Config.SourceTags,
+					CustomFields: map[string]interface{}{
+						constants.CustomFieldSourceName: vc.SourceConfig.Name,
+					},
 				},
-			},
-			Address:            fmt.Sprintf("%s/%d", ipv4Address, ipv4MaskBits),
-			Status:             &objects.IPAddressStatusActive, // TODO
-			DNSName:            ipv4DNS,
-			Tenant:             nbHost.Tenant,
-			AssignedObjectType: objects.AssignedObjectTypeDeviceInterface,
-			AssignedObjectID:   nbVnic.ID,
-		})
-		if err != nil {
-			return err
+				Address:            fmt.Sprintf("%s/%d", ipv4Address, ipv4MaskBits),
+				VRF:                ipv4VRF,
+				Status:             &objects.IPAddressStatusActive, // TODO
+				DNSName:            ipv4DNS,
+				Tenant:             nbHost.Tenant,
+				AssignedObjectType: objects.AssignedObjectTypeDeviceInterface,
+				AssignedObjectID:   nbVnic.ID,
+			})
+			if err != nil {
+				return err
+			}
+			vc.CollectPrefix(nbIPv4Address.Address, ipv4VRF, nbVnic.UntaggedVlan, nbHost.Site, nbHost.Tenant)
+			hostIPv4Addresses = append(hostIPv4Addresses, nbIPv4Address)
 		}
-		hostIPv4Addresses = append(hostIPv4Addresses, nbIPv4Address)
 
 		if vnic.Spec.Ip.IpV6Config != nil {
 			for _, ipv6Entry := range vnic.Spec.Ip.IpV6Config.IpV6Address {
 				ipv6Address := ipv6Entry.IpAddress
 				ipv6Mask := ipv6Entry.PrefixLength
-				// TODO: Filter out ipv6 addresses
+				if !vc.IsPermittedIPAddress(ipv6Address) {
+					continue
+				}
+				ipv6VRF, err := vc.MatchIPToVRF(nbi, ipv6Address, nbHost.Cluster)
+				if err != nil {
+					return fmt.Errorf("match ip to vrf: %s", err)
+				}
 				nbIPv6Address, err := nbi.AddIPAddress(&objects.IPAddress{
 					NetboxObject: objects.NetboxObject{
 						Tags: vc.Config.SourceTags,
-						CustomFields: map[string]string{
+						CustomFields: map[string]interface{}{
 							constants.CustomFieldSourceName: vc.SourceConfig.Name,
 						},
 					},
 					Address:            fmt.Sprintf("%s/%d", ipv6Address, ipv6Mask),
+					VRF:                ipv6VRF,
 					Status:             &objects.IPAddressStatusActive, // TODO
 					Tenant:             nbHost.Tenant,
 					AssignedObjectType: objects.AssignedObjectTypeDeviceInterface,
@@ -489,6 +535,7 @@ func (vc *VmwareSource) syncHostVirtualNics(nbi *inventory.NetboxInventory, vcHo
 				if err != nil {
 					return err
 				}
+				vc.CollectPrefix(nbIPv6Address.Address, ipv6VRF, nbVnic.UntaggedVlan, nbHost.Site, nbHost.Tenant)
 				hostIPv6Addresses = append(hostIPv6Addresses, nbIPv6Address)
 			}
 		}
@@ -500,13 +547,13 @@ func (vc *VmwareSource) setHostPrimaryIPAddress(nbi *inventory.NetboxInventory,
 	if len(hostIPv4Addresses) > 0 || len(hostIPv6Addresses) > 0 {
 		var hostPrimaryIPv4 *objects.IPAddress
 		for _, addr := range hostIPv4Addresses {
-			if hostPrimaryIPv4 == nil || utils.Lookup(nbHost.Name) == addr.Address {
+			if hostPrimaryIPv4 == nil || vc.Lookup(nbHost.Name) == addr.Address {
 				hostPrimaryIPv4 = addr
 			}
 		}
 		var hostPrimaryIPv6 *objects.IPAddress
 		for _, addr := range hostIPv6Addresses {
-			if hostPrimaryIPv6 == nil || utils.Lookup(nbHost.Name) == addr.Address {
+			if hostPrimaryIPv6 == nil || vc.Lookup(nbHost.Name) == addr.Address {
 				hostPrimaryIPv6 = addr
 			}
 		}
@@ -603,8 +650,9 @@ func (vc *VmwareSource) collectHostVirtualNicData(nbi *inventory.NetboxInventory
 		NetboxObject: objects.NetboxObject{
 			Tags:        vc.Config.SourceTags,
 			Description: vnicDescription,
-			CustomFields: map[string]string{
-				constants.CustomFieldSourceName: vc.SourceConfig.Name,
+			CustomFields: map[string]interface{}{
+				constants.CustomFieldSourceName:   vc.SourceConfig.Name,
+				constants.CustomFieldSourceIDName: hostNicSourceID(vcHost, vnic.Key),
 			},
 		},
 		Device:       nbHost,
@@ -619,6 +667,8 @@ func (vc *VmwareSource) collectHostVirtualNicData(nbi *inventory.NetboxInventory
 }
 
 func (vc *VmwareSource) syncVms(nbi *inventory.NetboxInventory) error {
+	// Dns names of all guest ips are resolved concurrently upfront
+	vc.PrefetchDNSNames(vc.vmGuestIPAddresses())
 	for vmKey, vm := range vc.Vms {
 		// Check if vm is a template, we don't add templates into netbox.
 		if vm.Config != nil {
@@ -646,6 +696,27 @@ func (vc *VmwareSource) syncVms(nbi *inventory.NetboxInventory) error {
 		// Cluster of the vm is same as the host
 		vmCluster := vmHost.Cluster
 
+		// Folder path and resource pool can override tenant and site of the vm
+		vmFolderPath := vc.VM2FolderPath[vmKey]
+		vmResourcePool := vc.VM2ResourcePool[vmKey]
+		if vmTenant == nil {
+			vmTenant, err = vc.matchVMLocationToTenant(nbi, vmFolderPath, vmResourcePool)
+			if err != nil {
+				return fmt.Errorf("vm's Tenant: %s", err)
+			}
+		}
+		vmLocationSite, err := vc.matchVMLocationToSite(nbi, vmFolderPath, vmResourcePool)
+		if err != nil {
+			return fmt.Errorf("vm's Site: %s", err)
+		}
+		if vmLocationSite != nil {
+			if vmCluster != nil && vmCluster.Site != nil && vmCluster.Site.ID != vmLocationSite.ID {
+				vc.Logger.Warningf("vm %s matched site %s, but its cluster %s belongs to site %s. Keeping site of the cluster", vmName, vmLocationSite.Name, vmCluster.Name, vmCluster.Site.Name)
+			} else {
+				vmSite = vmLocationSite
+			}
+		}
+
 		// VM status
 		vmStatus := &objects.VMStatusOffline
 		vmPowerState := vm.Runtime.PowerState
@@ -659,12 +730,11 @@ func (vc *VmwareSource) syncVms(nbi *inventory.NetboxInventory) error {
 		// vmMemory
 		vmMemory := vm.Config.Hardware.MemoryMB
 
-		// DisksSize
-		vmDiskSizeB := int64(0)
-		for _, hwDevice := range vm.Config.Hardware.Device {
-			if disk, ok := hwDevice.(*types.VirtualDisk); ok {
-				vmDiskSizeB += disk.CapacityInBytes
-			}
+		// Virtual disks. Netbox requires vm's disk size to match sum of its virtual disks
+		vmVirtualDisks := vc.extractVirtualDisks(vm)
+		vmDiskSize := 0
+		for _, virtualDisk := range vmVirtualDisks {
+			vmDiskSize += virtualDisk.Size
 		}
 
 		// vmPlatform
@@ -683,46 +753,29 @@ func (vc *VmwareSource) syncVms(nbi *inventory.NetboxInventory) error {
 			return fmt.Errorf("failed adding vmware vm's Platform %v with error: %s", vmPlatform, err)
 		}
 
-		// Extract additional info from CustomFields
-		var vmOwners []string
-		var vmOwnerEmails []string
-		var vmDescription string
-		vmCustomFields := map[string]string{}
-		if len(vm.Summary.CustomValue) > 0 {
-			for _, field := range vm.Summary.CustomValue {
-				if field, ok := field.(*types.CustomFieldStringValue); ok {
-					fieldName := vc.CustomFieldID2Name[field.Key]
-
-					if mappedField, ok := vc.CustomFieldMappings[fieldName]; ok {
-						switch mappedField {
-						case "owner":
-							vmOwners = strings.Split(field.Value, ",")
-						case "email":
-							vmOwnerEmails = strings.Split(field.Value, ",")
-						case "description":
-							vmDescription = strings.TrimSpace(field.Value)
-						}
-					} else {
-						fieldName = utils.Alphanumeric(fieldName)
-						if _, ok := nbi.CustomFieldsIndexByName[fieldName]; !ok {
-							err := nbi.AddCustomField(&objects.CustomField{
-								Name:                  fieldName,
-								Type:                  objects.CustomFieldTypeText,
-								CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
-								CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
-								ContentTypes:          []string{"virtualization.virtualmachine"},
-							})
-							if err != nil {
-								return fmt.Errorf("vm's custom field %s: %s", fieldName, err)
-							}
-						}
-						vmCustomFields[fieldName] = field.Value
-					}
-				}
-			}
+		// Extract additional info from custom attributes
+		vmAttributeData, err := vc.mapCustomAttributes(nbi, vm.Summary.CustomValue, "virtualization.virtualmachine")
+		if err != nil {
+			return fmt.Errorf("vm's custom attributes: %s", err)
+		}
+		vmOwners := vmAttributeData.Owners
+		vmOwnerEmails := vmAttributeData.OwnerEmails
+		vmDescription := vmAttributeData.Description
+		vmCustomFields := vmAttributeData.CustomFields
+		vmTagData, err := vc.mapVsphereTags(nbi, vmKey)
+		if err != nil {
+			return fmt.Errorf("vm's vsphere tags: %s", err)
 		}
+		if vmTenant == nil {
+			vmTenant = vmTagData.Tenant
+		}
+		maps.Copy(vmCustomFields, vmTagData.CustomFields)
 		vmCustomFields[constants.CustomFieldSourceName] = vc.SourceConfig.Name
-		vmCustomFields[constants.CustomFieldSourceIDName] = vm.Self.Value
+		vmCustomFields[constants.CustomFieldSourceIDName] = vmSourceID(vm)
+		if vc.SourceConfig.FolderCustomFields {
+			vmCustomFields[constants.CustomFieldVMFolderName] = vmFolderPath
+			vmCustomFields[constants.CustomFieldVMResourcePoolName] = vmResourcePool
+		}
 
 		// netbox description has constraint <= len(200 characters)
 		// In this case we make a comment
@@ -734,7 +787,7 @@ func (vc *VmwareSource) syncVms(nbi *inventory.NetboxInventory) error {
 
 		newVM, err := nbi.AddVM(&objects.VM{
 			NetboxObject: objects.NetboxObject{
-				Tags:         vc.Config.SourceTags,
+				Tags:         vmTagData.Tags,
 				Description:  vmDescription,
 				CustomFields: vmCustomFields,
 			},
@@ -746,8 +799,8 @@ func (vc *VmwareSource) syncVms(nbi *inventory.NetboxInventory) error {
 			Host:     vmHost,
 			Platform: vmPlatform,
 			VCPUs:    float32(vmVCPUs),
-			Memory:   int(vmMemory),                                                    // MBs
-			Disk:     int(vmDiskSizeB / constants.KiB / constants.KiB / constants.KiB), // GBs
+			Memory:   int(vmMemory), // MBs
+			Disk:     vmDiskSize,    // GBs
 			Comments: vmComments,
 		})
 
@@ -760,6 +813,14 @@ func (vc *VmwareSource) syncVms(nbi *inventory.NetboxInventory) error {
 			return fmt.Errorf("adding vm's contact: %s", err)
 		}
 
+		// Sync vm virtual disks
+		for _, virtualDisk := range vmVirtualDisks {
+			virtualDisk.VM = newVM
+			if _, err := nbi.AddVirtualDisk(virtualDisk); err != nil {
+				return fmt.Errorf("failed to sync vmware vm's virtual disk %s: %v", virtualDisk.Name, err)
+			}
+		}
+
 		// Sync vm interfaces
 		err = vc.syncVMInterfaces(nbi, vm, newVM)
 		if err != nil {
@@ -769,6 +830,91 @@ func (vc *VmwareSource) syncVms(nbi *inventory.NetboxInventory) error {
 	return nil
 }
 
+// extractVirtualDisks returns virtual disks of the vm, together with their datastore
+// and provisioning type. Returned disks don't have VM set.
+func (vc *VmwareSource) extractVirtualDisks(vm mo.VirtualMachine) []*objects.VirtualDisk {
+	virtualDisks := make([]*objects.VirtualDisk, 0)
+	for _, hwDevice := range vm.Config.Hardware.Device {
+		disk, ok := hwDevice.(*types.VirtualDisk)
+		if !ok {
+			continue
+		}
+		diskName := fmt.Sprintf("Disk %d", disk.Key)
+		if deviceInfo := disk.GetVirtualDevice().DeviceInfo; deviceInfo != nil && deviceInfo.GetDescription().Label != "" {
+			diskName = deviceInfo.GetDescription().Label
+		}
+		diskCustomFields := map[string]interface{}{
+			constants.CustomFieldSourceName:           vc.SourceConfig.Name,
+			constants.CustomFieldProvisioningTypeName: diskProvisioningType(disk),
+		}
+		if fileBacking, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo); ok {
+			if datastoreRef := fileBacking.GetVirtualDeviceFileBackingInfo().Datastore; datastoreRef != nil {
+				if datastore, ok := vc.Disks[datastoreRef.Value]; ok {
+					diskCustomFields[constants.CustomFieldDatastoreName] = datastore.Summary.Name
+				}
+			}
+		}
+		virtualDisks = append(virtualDisks, &objects.VirtualDisk{
+			NetboxObject: objects.NetboxObject{
+				Tags:         vc.Config.SourceTags,
+				CustomFields: diskCustomFields,
+			},
+			Name: diskName,
+			Size: int(disk.CapacityInBytes / constants.KiB / constants.KiB / constants.KiB), // GBs
+		})
+	}
+	return virtualDisks
+}
+
+// diskProvisioningType returns provisioning type of the vmware virtual disk,
+// based on its backing.
+func diskProvisioningType(disk *types.VirtualDisk) string {
+	switch backing := disk.Backing.(type) {
+	case *types.VirtualDiskFlatVer2BackingInfo:
+		if backing.ThinProvisioned != nil && *backing.ThinProvisioned {
+			return constants.DiskProvisioningThin
+		}
+		if backing.EagerlyScrub != nil && *backing.EagerlyScrub {
+			return constants.DiskProvisioningThickEagerZeroed
+		}
+		return constants.DiskProvisioningThickLazyZeroed
+	case *types.VirtualDiskSeSparseBackingInfo, *types.VirtualDiskSparseVer2BackingInfo:
+		return constants.DiskProvisioningThin
+	case *types.VirtualDiskRawDiskMappingVer1BackingInfo:
+		return constants.DiskProvisioningRDM
+	default:
+		return ""
+	}
+}
+
+// matchVMLocationToTenant matches vm's folder path and resource pool to tenant,
+// using folderTenantRelations and resourcePoolTenantRelations.
+// Folder relations have precedence over resource pool relations.
+func (vc *VmwareSource) matchVMLocationToTenant(nbi *inventory.NetboxInventory, folderPath, resourcePool string) (*objects.Tenant, error) {
+	tenant, err := common.MatchVMToTenant(nbi, folderPath, vc.FolderTenantRelations)
+	if err != nil || tenant != nil {
+		return tenant, err
+	}
+	if resourcePool == "" {
+		return nil, nil
+	}
+	return common.MatchVMToTenant(nbi, resourcePool, vc.ResourcePoolTenantRelations)
+}
+
+// matchVMLocationToSite matches vm's folder path and resource pool to site,
+// using folderSiteRelations and resourcePoolSiteRelations.
+// Folder relations have precedence over resource pool relations.
+func (vc *VmwareSource) matchVMLocationToSite(nbi *inventory.NetboxInventory, folderPath, resourcePool string) (*objects.Site, error) {
+	site, err := common.MatchVMToSite(nbi, folderPath, vc.FolderSiteRelations)
+	if err != nil || site != nil {
+		return site, err
+	}
+	if resourcePool == "" {
+		return nil, nil
+	}
+	return common.MatchVMToSite(nbi, resourcePool, vc.ResourcePoolSiteRelations)
+}
+
 // Syncs VM's interfaces to Netbox.
 func (vc *VmwareSource) syncVMInterfaces(nbi *inventory.NetboxInventory, vmwareVM mo.VirtualMachine, netboxVM *objects.VM) error {
 	// Data to determine the primary IP address of the vm
@@ -837,7 +983,7 @@ func (vc *VmwareSource) syncVMInterfaces(nbi *inventory.NetboxInventory, vmwareV
 				return fmt.Errorf("adding VmInterface: %s", err)
 			}
 
-			err = vc.addVMInterfaceIPs(nbi, nbVMInterface, nicIPv4Addresses, nicIPv6Addresses, vmIPv4Addresses, vmIPv6Addresses)
+			err = vc.addVMInterfaceIPs(nbi, netboxVM, nbVMInterface, vmGuestHostname(vmwareVM), nicIPv4Addresses, nicIPv6Addresses, vmIPv4Addresses, vmIPv6Addresses)
 			if err != nil {
 				return err
 			}
@@ -974,7 +1120,7 @@ func (vc *VmwareSource) collectVMInterfaceData(nbi *inventory.NetboxInventory, n
 		NetboxObject: objects.NetboxObject{
 			Tags:        vc.Config.SourceTags,
 			Description: intDescription,
-			CustomFields: map[string]string{
+			CustomFields: map[string]interface{}{
 				constants.CustomFieldSourceName: vc.SourceConfig.Name,
 			},
 		},
@@ -989,19 +1135,71 @@ func (vc *VmwareSource) collectVMInterfaceData(nbi *inventory.NetboxInventory, n
 	}, nil
 }
 
+// vmSourceID returns id of the vm, that is stored in source_id custom field. Instance uuid
+// is used, because it doesn't change when vm is renamed and is unique across vcenters.
+func vmSourceID(vm mo.VirtualMachine) string {
+	if vm.Summary.Config.InstanceUuid != "" {
+		return vm.Summary.Config.InstanceUuid
+	}
+	return vm.Self.Value
+}
+
+// hostNicSourceID returns id of host's physical or virtual nic, that is stored in source_id
+// custom field. Nic keys are unique only within the host, so host's moref is prepended.
+func hostNicSourceID(host mo.HostSystem, nicKey string) string {
+	return fmt.Sprintf("%s/%s", host.Self.Value, nicKey)
+}
+
+// vmGuestIPAddresses returns ip addresses of all vms, reported by vmware tools.
+func (vc *VmwareSource) vmGuestIPAddresses() []string {
+	ipAddresses := []string{}
+	for _, vm := range vc.Vms {
+		if vm.Guest == nil {
+			continue
+		}
+		for _, guestNic := range vm.Guest.Net {
+			if guestNic.IpConfig == nil {
+				continue
+			}
+			for _, guestIP := range guestNic.IpConfig.IpAddress {
+				ipAddresses = append(ipAddresses, guestIP.IpAddress)
+			}
+		}
+	}
+	return ipAddresses
+}
+
+// vmGuestHostname returns hostname of the vm reported by vmware tools.
+func vmGuestHostname(vm mo.VirtualMachine) string {
+	if vm.Guest == nil {
+		return ""
+	}
+	return vm.Guest.HostName
+}
+
 // Function that adds all collected IPs for the vm's interface to netbox.
-func (vc *VmwareSource) addVMInterfaceIPs(nbi *inventory.NetboxInventory, nbVMInterface *objects.VMInterface, nicIPv4Addresses []string, nicIPv6Addresses []string, vmIPv4Addresses []*objects.IPAddress, vmIPv6Addresses []*objects.IPAddress) error {
+// Guest hostname is used as dns name of the ips, depending on source's dnsNamePolicy.
+func (vc *VmwareSource) addVMInterfaceIPs(nbi *inventory.NetboxInventory, netboxVM *objects.VM, nbVMInterface *objects.VMInterface, guestHostname string, nicIPv4Addresses []string, nicIPv6Addresses []string, vmIPv4Addresses []*objects.IPAddress, vmIPv6Addresses []*objects.IPAddress) error {
 	// Add all collected ipv4 addresses for the interface to netbox
 	for _, ipv4Address := range nicIPv4Addresses {
+		if !vc.IsPermittedIPAddress(ipv4Address) {
+			continue
+		}
+		ipv4VRF, err := vc.MatchIPToVRF(nbi, ipv4Address, netboxVM.Cluster)
+		if err != nil {
+			vc.Logger.Warningf("match ipv4 address to vrf: %s", err)
+			continue
+		}
 		nbIPv4Address, err := nbi.AddIPAddress(&objects.IPAddress{
 			NetboxObject: objects.NetboxObject{
 				Tags: vc.Config.SourceTags,
-				CustomFields: map[string]string{
+				CustomFields: map[string]interface{}{
 					constants.CustomFieldSourceName: vc.SourceConfig.Name,
 				},
 			},
 			Address:            ipv4Address,
-			DNSName:            utils.ReverseLookup(ipv4Address),
+			VRF:                ipv4VRF,
+			DNSName:            vc.DNSName(ipv4Address, guestHostname),
 			AssignedObjectType: objects.AssignedObjectTypeVMInterface,
 			AssignedObjectID:   nbVMInterface.ID,
 		})
@@ -1013,15 +1211,24 @@ func (vc *VmwareSource) addVMInterfaceIPs(nbi *inventory.NetboxInventory, nbVMIn
 
 	// Add all collected ipv6 addresses for the interface to netbox
 	for _, ipv6Address := range nicIPv6Addresses {
+		if !vc.IsPermittedIPAddress(ipv6Address) {
+			continue
+		}
+		ipv6VRF, err := vc.MatchIPToVRF(nbi, ipv6Address, netboxVM.Cluster)
+		if err != nil {
+			vc.Logger.Warningf("match ipv6 address to vrf: %s", err)
+			continue
+		}
 		nbIPv6Address, err := nbi.AddIPAddress(&objects.IPAddress{
 			NetboxObject: objects.NetboxObject{
 				Tags: vc.Config.SourceTags,
-				CustomFields: map[string]string{
+				CustomFields: map[string]interface{}{
 					constants.CustomFieldSourceName: vc.SourceConfig.Name,
 				},
 			},
 			Address:            ipv6Address,
-			DNSName:            utils.ReverseLookup(ipv6Address),
+			VRF:                ipv6VRF,
+			DNSName:            vc.DNSName(ipv6Address, guestHostname),
 			AssignedObjectType: objects.AssignedObjectTypeVMInterface,
 			AssignedObjectID:   nbVMInterface.ID,
 		})
@@ -1104,3 +1311,255 @@ func (vc *VmwareSource) addVMContact(nbi *inventory.NetboxInventory, nbVM *objec
 	}
 	return nil
 }
+
+// vsphereTagData holds netbox attributes, that are mapped from vsphere tags attached to an object.
+type vsphereTagData struct {
+	// Tags are source tags extended with netbox tags created from vsphere tags
+	Tags []*objects.Tag
+	// Tenant matched from the tag in the category mapped to tenant
+	Tenant *objects.Tenant
+	// CustomFields filled from tags in categories mapped to custom fields
+	CustomFields map[string]interface{}
+}
+
+// vsphereTagName returns name of the netbox tag created from the vsphere tag.
+func (vc *VmwareSource) vsphereTagName(tag VsphereTag) string {
+	if vc.SourceConfig.TagCategoryPrefix && tag.Category != "" {
+		return fmt.Sprintf("%s: %s", tag.Category, tag.Name)
+	}
+	return tag.Name
+}
+
+// mapVsphereTags maps vsphere tags attached to the object to netbox tags, tenant and custom fields.
+// Tags in categories defined in tagCategoryMappings are mapped to tenants or custom fields,
+// other tags are synced as netbox tags, if syncTags is enabled.
+func (vc *VmwareSource) mapVsphereTags(nbi *inventory.NetboxInventory, objectKey string) (*vsphereTagData, error) {
+	tagData := &vsphereTagData{
+		Tags:         slices.Clone(vc.Config.SourceTags),
+		CustomFields: make(map[string]interface{}),
+	}
+	for _, tag := range vc.Object2Tags[objectKey] {
+		switch vc.TagCategoryMappings[tag.Category] {
+		case parser.TagCategoryTenant:
+			tenant, ok := nbi.TenantsIndexByName[tag.Name]
+			if !ok {
+				vc.Logger.Warningf("vsphere tag %s is mapped to tenant, but tenant with this name doesn't exist", tag.Name)
+				continue
+			}
+			tagData.Tenant = tenant
+		case parser.TagCategoryCustomField:
+			fieldName := utils.Alphanumeric(tag.Category)
+			if _, ok := nbi.CustomFieldsIndexByName[fieldName]; !ok {
+				err := nbi.AddCustomField(&objects.CustomField{
+					Name:                  fieldName,
+					Label:                 tag.Category,
+					Type:                  objects.CustomFieldTypeText,
+					CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
+					CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
+					ContentTypes:          []string{"dcim.device", "virtualization.cluster", "virtualization.virtualmachine"},
+				})
+				if err != nil {
+					return nil, fmt.Errorf("tag category custom field %s: %s", fieldName, err)
+				}
+			}
+			if value, ok := tagData.CustomFields[fieldName]; ok {
+				tagData.CustomFields[fieldName] = fmt.Sprintf("%s, %s", value, tag.Name)
+			} else {
+				tagData.CustomFields[fieldName] = tag.Name
+			}
+		default:
+			if !vc.SourceConfig.SyncTags {
+				continue
+			}
+			tagName := vc.vsphereTagName(tag)
+			nbTag, err := nbi.AddTag(&objects.Tag{
+				Name:        tagName,
+				Slug:        utils.Slugify(tagName),
+				Color:       objects.Color(vc.SourceConfig.VsphereTagColor),
+				Description: tag.Description,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("vsphere tag %s: %s", tagName, err)
+			}
+			tagData.Tags = append(tagData.Tags, nbTag)
+		}
+	}
+	return tagData, nil
+}
+
+// Options of customFieldMappings, that map custom attributes to vm's contacts and description.
+// Custom attributes mapped to any other option are synced to the custom field with that name.
+const (
+	customAttributeOwner       = "owner"
+	customAttributeEmail       = "email"
+	customAttributeDescription = "description"
+)
+
+// customAttributeData holds netbox attributes, that are mapped from vsphere custom attributes of an object.
+type customAttributeData struct {
+	// Owners are names of contacts from the attribute mapped to owner
+	Owners []string
+	// OwnerEmails are emails of contacts from the attribute mapped to email
+	OwnerEmails []string
+	// Description from the attribute mapped to description
+	Description string
+	// CustomFields filled from all other custom attributes
+	CustomFields map[string]interface{}
+}
+
+// mapCustomAttributes maps vsphere custom attributes of the object with the given content type
+// (e.g. dcim.device) to netbox attributes. Custom attributes, that are not mapped to owner,
+// email or description, are synced as netbox custom fields, which are created if they don't exist yet.
+func (vc *VmwareSource) mapCustomAttributes(nbi *inventory.NetboxInventory, customValues []types.BaseCustomFieldValue, contentType string) (*customAttributeData, error) {
+	attributeData := &customAttributeData{CustomFields: make(map[string]interface{})}
+	for _, customValue := range customValues {
+		field, ok := customValue.(*types.CustomFieldStringValue)
+		if !ok {
+			continue
+		}
+		attributeName := vc.CustomFieldID2Name[field.Key]
+		switch vc.CustomFieldMappings[attributeName] {
+		case customAttributeOwner:
+			attributeData.Owners = strings.Split(field.Value, ",")
+			continue
+		case customAttributeEmail:
+			attributeData.OwnerEmails = strings.Split(field.Value, ",")
+			continue
+		case customAttributeDescription:
+			attributeData.Description = strings.TrimSpace(field.Value)
+			continue
+		}
+		fieldName := vc.customAttributeFieldName(attributeName)
+		if fieldName == "" || field.Value == "" {
+			continue
+		}
+		value, err := vc.addCustomAttributeField(nbi, fieldName, attributeName, contentType, field.Value)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			attributeData.CustomFields[fieldName] = value
+		}
+	}
+	return attributeData, nil
+}
+
+// customAttributeFieldName returns name of the netbox custom field, to which the custom
+// attribute (not mapped to owner, email or description) is synced.
+func (vc *VmwareSource) customAttributeFieldName(attributeName string) string {
+	if mappedField, ok := vc.CustomFieldMappings[attributeName]; ok {
+		return utils.Alphanumeric(mappedField)
+	}
+	return utils.Alphanumeric(attributeName)
+}
+
+// customAttributeValues returns values of all custom attributes of clusters, hosts and vms,
+// that are synced to the custom field with the given name.
+func (vc *VmwareSource) customAttributeValues(fieldName string) []string {
+	customValues := [][]types.BaseCustomFieldValue{}
+	for _, cluster := range vc.Clusters {
+		customValues = append(customValues, cluster.CustomValue)
+	}
+	for _, host := range vc.Hosts {
+		customValues = append(customValues, host.CustomValue)
+	}
+	for _, vm := range vc.Vms {
+		customValues = append(customValues, vm.Summary.CustomValue)
+	}
+	values := []string{}
+	for _, objectValues := range customValues {
+		for _, customValue := range objectValues {
+			field, ok := customValue.(*types.CustomFieldStringValue)
+			if !ok || field.Value == "" {
+				continue
+			}
+			attributeName := vc.CustomFieldID2Name[field.Key]
+			switch vc.CustomFieldMappings[attributeName] {
+			case customAttributeOwner, customAttributeEmail, customAttributeDescription:
+				continue
+			}
+			if vc.customAttributeFieldName(attributeName) == fieldName {
+				values = append(values, field.Value)
+			}
+		}
+	}
+	return values
+}
+
+// addCustomAttributeField ensures that custom field for the custom attribute exists and
+// is available for the given content type. Type of the new custom field is inferred from
+// all values of the attributes synced to it, so a single unusual value doesn't decide the
+// type. Returns the value converted to the type of the custom field, or nil if the value
+// can't be converted.
+func (vc *VmwareSource) addCustomAttributeField(nbi *inventory.NetboxInventory, fieldName, attributeName, contentType, value string) (interface{}, error) {
+	customField, ok := nbi.CustomFieldsIndexByName[fieldName]
+	switch {
+	case !ok:
+		fieldType := inferCustomFieldType(append(vc.customAttributeValues(fieldName), value))
+		err := nbi.AddCustomField(&objects.CustomField{
+			Name:                  fieldName,
+			Label:                 attributeName,
+			Type:                  fieldType,
+			CustomFieldUIVisible:  &objects.CustomFieldUIVisibleIfSet,
+			CustomFieldUIEditable: &objects.CustomFieldUIEditableYes,
+			ContentTypes:          []string{contentType},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("custom field %s: %s", fieldName, err)
+		}
+	case !slices.Contains(customField.ContentTypes, contentType):
+		updatedCustomField := *customField
+		updatedCustomField.ContentTypes = append(slices.Clone(customField.ContentTypes), contentType)
+		if err := nbi.AddCustomField(&updatedCustomField); err != nil {
+			return nil, fmt.Errorf("custom field %s content types: %s", fieldName, err)
+		}
+	}
+
+	fieldValue, err := customFieldValue(nbi.CustomFieldsIndexByName[fieldName].Type, value)
+	if err != nil {
+		vc.Logger.Warningf("custom attribute %s: %s", attributeName, err)
+		return nil, nil
+	}
+	return fieldValue, nil
+}
+
+// inferCustomFieldType infers netbox custom field type from the custom attribute values.
+// Text is used, when the values don't share a more specific type.
+func inferCustomFieldType(values []string) objects.CustomFieldType {
+	for _, fieldType := range []objects.CustomFieldType{objects.CustomFieldTypeInteger, objects.CustomFieldTypeBoolean, objects.CustomFieldTypeDate} {
+		if !slices.ContainsFunc(values, func(value string) bool {
+			_, err := customFieldValue(fieldType, value)
+			return err != nil
+		}) {
+			return fieldType
+		}
+	}
+	return objects.CustomFieldTypeText
+}
+
+// customFieldValue converts custom attribute value to the value of the custom field type.
+func customFieldValue(fieldType objects.CustomFieldType, value string) (interface{}, error) {
+	value = strings.TrimSpace(value)
+	switch fieldType.Value {
+	case objects.CustomFieldTypeInteger.Value:
+		intValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not an integer", value)
+		}
+		return intValue, nil
+	case objects.CustomFieldTypeBoolean.Value:
+		switch strings.ToLower(value) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%s is not a boolean", value)
+	case objects.CustomFieldTypeDate.Value:
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return nil, fmt.Errorf("%s is not a date in format YYYY-MM-DD", value)
+		}
+		return value, nil
+	}
+	return value, nil
+}
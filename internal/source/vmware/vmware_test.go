@@ -0,0 +1,511 @@
+package vmware
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory/inventorytest"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func newTestSource(t *testing.T, sourceConfig *parser.SourceConfig) *VmwareSource {
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &VmwareSource{
+		Config: common.Config{
+			Logger:       testLogger,
+			SourceConfig: sourceConfig,
+		},
+	}
+}
+
+func TestCreateTagRelation(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		password, _ := simulator.DefaultLogin.Password()
+		vc := newTestSource(t, &parser.SourceConfig{
+			Username: simulator.DefaultLogin.Username(),
+			Password: password,
+		})
+		containerView, err := view.NewManager(c).CreateContainerView(ctx, c.ServiceContent.RootFolder, []string{"ClusterComputeResource", "HostSystem", "VirtualMachine"}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vc.Networks = NetworkData{
+			HostVirtualSwitches: make(map[string]map[string]*HostVirtualSwitchData),
+			HostProxySwitches:   make(map[string]map[string]*HostProxySwitchData),
+			HostPortgroups:      make(map[string]map[string]*HostPortgroupData),
+		}
+		for _, initFunc := range []func(context.Context, *view.ContainerView) error{vc.InitClusters, vc.InitHosts, vc.InitVms} {
+			if err := initFunc(ctx, containerView); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Attach tag to one of the simulated vms
+		restClient := rest.NewClient(c)
+		if err := restClient.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		tagManager := tags.NewManager(restClient)
+		categoryID, err := tagManager.CreateCategory(ctx, &tags.Category{Name: "Environment", Cardinality: "SINGLE"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tagID, err := tagManager.CreateTag(ctx, &tags.Tag{Name: "production", CategoryID: categoryID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var vmKey string
+		for key, vm := range vc.Vms {
+			vmKey = key
+			if err := tagManager.AttachTag(ctx, tagID, vm.Self); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+
+		if err := vc.CreateTagRelation(ctx, c); err != nil {
+			t.Fatalf("CreateTagRelation() error = %v", err)
+		}
+		expected := VsphereTag{Name: "production", Category: "Environment"}
+		if len(vc.Object2Tags) != 1 || len(vc.Object2Tags[vmKey]) != 1 || vc.Object2Tags[vmKey][0] != expected {
+			t.Errorf("Object2Tags = %v, want {%s: [%v]}", vc.Object2Tags, vmKey, expected)
+		}
+	})
+}
+
+func TestMapVsphereTags(t *testing.T) {
+	vc := newTestSource(t, &parser.SourceConfig{Name: "vcenter"})
+	vc.SourceTags = []*objects.Tag{{ID: 1, Name: "Source: vcenter"}}
+	vc.TagCategoryMappings = map[string]string{"Owner": parser.TagCategoryTenant}
+	vc.Object2Tags = map[string][]VsphereTag{
+		"vm-1": {{Name: "acme", Category: "Owner"}, {Name: "production", Category: "Environment"}},
+		"vm-2": {{Name: "unknown", Category: "Owner"}},
+	}
+	acme := &objects.Tenant{NetboxObject: objects.NetboxObject{ID: 1}, Name: "acme"}
+	nbi := &inventory.NetboxInventory{
+		Logger:             vc.Logger,
+		TenantsIndexByName: map[string]*objects.Tenant{"acme": acme},
+	}
+
+	tests := []struct {
+		objectKey      string
+		expectedTenant *objects.Tenant
+	}{
+		{objectKey: "vm-1", expectedTenant: acme},
+		{objectKey: "vm-2", expectedTenant: nil},
+		{objectKey: "vm-3", expectedTenant: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.objectKey, func(t *testing.T) {
+			tagData, err := vc.mapVsphereTags(nbi, tt.objectKey)
+			if err != nil {
+				t.Fatalf("mapVsphereTags() error = %v", err)
+			}
+			if tagData.Tenant != tt.expectedTenant {
+				t.Errorf("mapVsphereTags() tenant = %v, want %v", tagData.Tenant, tt.expectedTenant)
+			}
+			// Tags outside of mapped categories are ignored, because syncTags is disabled
+			if len(tagData.Tags) != len(vc.SourceTags) {
+				t.Errorf("mapVsphereTags() tags = %v, want %v", tagData.Tags, vc.SourceTags)
+			}
+		})
+	}
+}
+
+func TestVsphereTagName(t *testing.T) {
+	tag := VsphereTag{Name: "production", Category: "Environment"}
+	tests := []struct {
+		name           string
+		categoryPrefix bool
+		expected       string
+	}{
+		{name: "Without category prefix", categoryPrefix: false, expected: "production"},
+		{name: "With category prefix", categoryPrefix: true, expected: "Environment: production"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vc := newTestSource(t, &parser.SourceConfig{TagCategoryPrefix: tt.categoryPrefix})
+			if got := vc.vsphereTagName(tag); got != tt.expected {
+				t.Errorf("vsphereTagName() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInferCustomFieldType(t *testing.T) {
+	tests := []struct {
+		name         string
+		values       []string
+		expectedType objects.CustomFieldType
+	}{
+		{name: "Integer", values: []string{"42", "7"}, expectedType: objects.CustomFieldTypeInteger},
+		{name: "Boolean", values: []string{"True", "false"}, expectedType: objects.CustomFieldTypeBoolean},
+		{name: "Date", values: []string{"2024-03-01"}, expectedType: objects.CustomFieldTypeDate},
+		{name: "Text", values: []string{"John Doe"}, expectedType: objects.CustomFieldTypeText},
+		{name: "Disagreeing values", values: []string{"1234", "CC-12"}, expectedType: objects.CustomFieldTypeText},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotType := inferCustomFieldType(tt.values); gotType != tt.expectedType {
+				t.Errorf("inferCustomFieldType() = %v, want %v", gotType, tt.expectedType)
+			}
+		})
+	}
+}
+
+func TestAddCustomAttributeFieldType(t *testing.T) {
+	vc := newTestSource(t, &parser.SourceConfig{Name: "vcenter"})
+	vc.CustomFieldID2Name = map[int32]string{1: "Cost Center"}
+	vc.Vms = map[string]mo.VirtualMachine{}
+	for i, costCenter := range []string{"1234", "CC-12"} {
+		vm := mo.VirtualMachine{}
+		vm.Summary.CustomValue = []types.BaseCustomFieldValue{
+			&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 1}, Value: costCenter},
+		}
+		vc.Vms[fmt.Sprintf("vm-%d", i)] = vm
+	}
+	nbi, _ := inventorytest.NewInventory(t)
+	nbi.CustomFieldsIndexByName = map[string]*objects.CustomField{}
+
+	// Field is created while syncing the first vm, but its type matches all vms
+	value, err := vc.addCustomAttributeField(nbi, "cost_center", "Cost Center", "virtualization.virtualmachine", "1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fieldType := nbi.CustomFieldsIndexByName["cost_center"].Type; fieldType.Value != objects.CustomFieldTypeText.Value {
+		t.Errorf("custom field type = %v, want %v", fieldType, objects.CustomFieldTypeText)
+	}
+	if value != "1234" {
+		t.Errorf("addCustomAttributeField() = %v, want 1234", value)
+	}
+	value, err = vc.addCustomAttributeField(nbi, "cost_center", "Cost Center", "virtualization.virtualmachine", "CC-12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "CC-12" {
+		t.Errorf("addCustomAttributeField() = %v, want CC-12", value)
+	}
+}
+
+func TestMapCustomAttributes(t *testing.T) {
+	vc := newTestSource(t, &parser.SourceConfig{Name: "vcenter"})
+	vc.CustomFieldID2Name = map[int32]string{1: "Owner", 2: "Cost Center", 3: "Backup", 4: "Created"}
+	vc.CustomFieldMappings = map[string]string{"Owner": "owner", "Cost Center": "cost_center_id"}
+	// Custom fields already exist, so no netbox api calls are needed
+	nbi := &inventory.NetboxInventory{
+		Logger: vc.Logger,
+		CustomFieldsIndexByName: map[string]*objects.CustomField{
+			"cost_center_id": {Name: "cost_center_id", Type: objects.CustomFieldTypeInteger, ContentTypes: []string{"dcim.device"}},
+			"backup":         {Name: "backup", Type: objects.CustomFieldTypeBoolean, ContentTypes: []string{"dcim.device"}},
+			"created":        {Name: "created", Type: objects.CustomFieldTypeDate, ContentTypes: []string{"dcim.device"}},
+		},
+	}
+	customValues := []types.BaseCustomFieldValue{
+		&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 1}, Value: "John Doe"},
+		&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 2}, Value: "1234"},
+		&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 3}, Value: "false"},
+		&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 4}, Value: "yesterday"},
+	}
+
+	attributeData, err := vc.mapCustomAttributes(nbi, customValues, "dcim.device")
+	if err != nil {
+		t.Fatalf("mapCustomAttributes() error = %v", err)
+	}
+	if !reflect.DeepEqual(attributeData.Owners, []string{"John Doe"}) {
+		t.Errorf("mapCustomAttributes() owners = %v, want [John Doe]", attributeData.Owners)
+	}
+	// Value of created is skipped, because it is not a valid date
+	expectedCustomFields := map[string]interface{}{"cost_center_id": int64(1234), "backup": false}
+	if !reflect.DeepEqual(attributeData.CustomFields, expectedCustomFields) {
+		t.Errorf("mapCustomAttributes() custom fields = %v, want %v", attributeData.CustomFields, expectedCustomFields)
+	}
+}
+
+func TestInitVmsFolderPath(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vc := newTestSource(t, &parser.SourceConfig{})
+		finder := find.NewFinder(c)
+		datacenter, err := finder.DefaultDatacenter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		finder.SetDatacenter(datacenter)
+		vmFolder, err := finder.Folder(ctx, "vm")
+		if err != nil {
+			t.Fatal(err)
+		}
+		teamFolder, err := vmFolder.CreateFolder(ctx, "Team A")
+		if err != nil {
+			t.Fatal(err)
+		}
+		prodFolder, err := teamFolder.CreateFolder(ctx, "Production")
+		if err != nil {
+			t.Fatal(err)
+		}
+		vms, err := finder.VirtualMachineList(ctx, "*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		movedVM, rootVM := vms[0], vms[1]
+		task, err := prodFolder.MoveInto(ctx, []types.ManagedObjectReference{movedVM.Reference()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		containerView, err := view.NewManager(c).CreateContainerView(ctx, c.ServiceContent.RootFolder, []string{"Folder", "ResourcePool", "VirtualMachine"}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, initFunc := range []func(context.Context, *view.ContainerView) error{vc.InitFolders, vc.InitResourcePools, vc.InitVms} {
+			if err := initFunc(ctx, containerView); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if got := vc.VM2FolderPath[movedVM.Reference().Value]; got != "/Team A/Production" {
+			t.Errorf("folder path of moved vm = %q, want %q", got, "/Team A/Production")
+		}
+		if got := vc.VM2FolderPath[rootVM.Reference().Value]; got != "/" {
+			t.Errorf("folder path of root vm = %q, want %q", got, "/")
+		}
+		if got := vc.VM2ResourcePool[rootVM.Reference().Value]; got != "/" {
+			t.Errorf("resource pool of root vm = %q, want %q", got, "/")
+		}
+	})
+}
+
+func TestResourcePoolPath(t *testing.T) {
+	vc := newTestSource(t, &parser.SourceConfig{})
+	vc.ResourcePools = map[string]mo.ResourcePool{
+		"resgroup-1": {ManagedEntity: mo.ManagedEntity{Name: "Resources", Parent: &types.ManagedObjectReference{Type: "ClusterComputeResource", Value: "domain-c1"}}},
+		"resgroup-2": {ManagedEntity: mo.ManagedEntity{Name: "Team A", Parent: &types.ManagedObjectReference{Type: "ResourcePool", Value: "resgroup-1"}}},
+		"resgroup-3": {ManagedEntity: mo.ManagedEntity{Name: "Databases", Parent: &types.ManagedObjectReference{Type: "ResourcePool", Value: "resgroup-2"}}},
+	}
+	tests := []struct {
+		name         string
+		resourcePool *types.ManagedObjectReference
+		want         string
+	}{
+		{name: "Root resource pool", resourcePool: &types.ManagedObjectReference{Type: "ResourcePool", Value: "resgroup-1"}, want: "/"},
+		{name: "Nested resource pool", resourcePool: &types.ManagedObjectReference{Type: "ResourcePool", Value: "resgroup-3"}, want: "/Team A/Databases"},
+		{name: "Unknown resource pool", resourcePool: &types.ManagedObjectReference{Type: "ResourcePool", Value: "resgroup-9"}, want: "/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vc.resourcePoolPath(tt.resourcePool); got != tt.want {
+				t.Errorf("resourcePoolPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiskProvisioningType(t *testing.T) {
+	thin, thick := true, false
+	tests := []struct {
+		name    string
+		backing types.BaseVirtualDeviceBackingInfo
+		want    string
+	}{
+		{name: "Thin provisioned", backing: &types.VirtualDiskFlatVer2BackingInfo{ThinProvisioned: &thin}, want: constants.DiskProvisioningThin},
+		{name: "Thick eager zeroed", backing: &types.VirtualDiskFlatVer2BackingInfo{ThinProvisioned: &thick, EagerlyScrub: &thin}, want: constants.DiskProvisioningThickEagerZeroed},
+		{name: "Thick lazy zeroed", backing: &types.VirtualDiskFlatVer2BackingInfo{}, want: constants.DiskProvisioningThickLazyZeroed},
+		{name: "Raw device mapping", backing: &types.VirtualDiskRawDiskMappingVer1BackingInfo{}, want: constants.DiskProvisioningRDM},
+		{name: "Unknown backing", backing: &types.VirtualDiskPartitionedRawDiskVer2BackingInfo{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			disk := &types.VirtualDisk{VirtualDevice: types.VirtualDevice{Backing: tt.backing}}
+			if got := diskProvisioningType(disk); got != tt.want {
+				t.Errorf("diskProvisioningType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVMSourceID(t *testing.T) {
+	vmRef := types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-42"}
+	tests := []struct {
+		name string
+		vm   mo.VirtualMachine
+		want string
+	}{
+		{
+			name: "Instance uuid",
+			vm: mo.VirtualMachine{
+				ManagedEntity: mo.ManagedEntity{ExtensibleManagedObject: mo.ExtensibleManagedObject{Self: vmRef}},
+				Summary:       types.VirtualMachineSummary{Config: types.VirtualMachineConfigSummary{InstanceUuid: "5012b5c4-7e7f-4a2e-9d4c-1b0c6a3f2e11"}},
+			},
+			want: "5012b5c4-7e7f-4a2e-9d4c-1b0c6a3f2e11",
+		},
+		{
+			name: "Managed object reference without instance uuid",
+			vm:   mo.VirtualMachine{ManagedEntity: mo.ManagedEntity{ExtensibleManagedObject: mo.ExtensibleManagedObject{Self: vmRef}}},
+			want: "vm-42",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vmSourceID(tt.vm); got != tt.want {
+				t.Errorf("vmSourceID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInitVmsIncremental(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		sourceConfig := &parser.SourceConfig{Name: "testvmware", IncrementalSync: true, SyncStateDir: t.TempDir()}
+		containerView, err := view.NewManager(c).CreateContainerView(ctx, c.ServiceContent.RootFolder, []string{"Folder", "ResourcePool", "VirtualMachine"}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		runIncremental := func() *VmwareSource {
+			vc := newTestSource(t, sourceConfig)
+			for _, initFunc := range []func(context.Context, *view.ContainerView) error{vc.InitFolders, vc.InitResourcePools, vc.InitVmsIncremental} {
+				if err := initFunc(ctx, containerView); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := vc.saveSyncState(); err != nil {
+				t.Fatal(err)
+			}
+			return vc
+		}
+
+		vms, err := find.NewFinder(c).VirtualMachineList(ctx, "*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		changedVM, deletedVM := vms[0], vms[1]
+		folder := moveToNewFolder(ctx, t, c, changedVM, "team")
+
+		// First run retrieves all vms
+		vc := runIncremental()
+		if len(vc.Vms) != len(vms) || len(vc.SyncState.VMs) != len(vms) {
+			t.Fatalf("first run retrieved %d vms, want %d", len(vc.Vms), len(vms))
+		}
+
+		// Second run without changes retrieves no vms
+		vc = runIncremental()
+		if len(vc.Vms) != 0 || len(vc.DeletedVms) != 0 {
+			t.Fatalf("run without changes retrieved %d changed and %d deleted vms", len(vc.Vms), len(vc.DeletedVms))
+		}
+
+		// Third run retrieves only changed vm and reports deleted vm
+		for _, taskFunc := range []func(context.Context) (*object.Task, error){changedVM.PowerOff, deletedVM.PowerOff, deletedVM.Destroy} {
+			task, err := taskFunc(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := task.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		vc = runIncremental()
+		if _, ok := vc.Vms[changedVM.Reference().Value]; !ok || len(vc.Vms) != 1 {
+			t.Errorf("changed vms = %v, want only %s", vc.Vms, changedVM.Reference().Value)
+		}
+		if _, ok := vc.DeletedVms[deletedVM.Reference().Value]; !ok || len(vc.DeletedVms) != 1 {
+			t.Errorf("deleted vms = %v, want only %s", vc.DeletedVms, deletedVM.Reference().Value)
+		}
+		if _, ok := vc.SyncState.VMs[deletedVM.Reference().Value]; ok {
+			t.Errorf("deleted vm %s is still in sync state", deletedVM.Reference().Value)
+		}
+
+		// Invalid collector falls back to full sync
+		vc.SyncState.Collector = "session[invalid]"
+		if err := vc.saveSyncState(); err != nil {
+			t.Fatal(err)
+		}
+		vc = runIncremental()
+		if len(vc.Vms) != len(vms)-1 {
+			t.Errorf("full sync fallback retrieved %d vms, want %d", len(vc.Vms), len(vms)-1)
+		}
+
+		// Renamed folder of the vm is not reported by the property collector, but vm is still synced
+		task, err := folder.Rename(ctx, "team-renamed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		vc = runIncremental()
+		if syncedVM := vc.SyncState.VMs[changedVM.Reference().Value]; len(vc.Vms) != 1 || syncedVM.FolderPath != "/team-renamed" {
+			t.Errorf("vm in renamed folder was synced with folder path %q, changed vms = %d", syncedVM.FolderPath, len(vc.Vms))
+		}
+
+		// Vsphere tags can't be watched, so all vms are synced, when tags are synced
+		sourceConfig.SyncTags = true
+		vc = runIncremental()
+		if len(vc.Vms) != len(vms)-1 {
+			t.Errorf("run with synced tags retrieved %d vms, want %d", len(vc.Vms), len(vms)-1)
+		}
+		sourceConfig.SyncTags = false
+
+		// Unchanged vms, that were deleted from netbox by hand, are synced again on the next run
+		vc = runIncremental()
+		nbi, _ := inventorytest.NewInventory(t)
+		if err := vc.retainUnchangedVms(nbi); err != nil {
+			t.Fatal(err)
+		}
+		if err := vc.saveSyncState(); err != nil {
+			t.Fatal(err)
+		}
+		vc = runIncremental()
+		if len(vc.Vms) != len(vms)-1 {
+			t.Errorf("run after vms were missing in netbox retrieved %d vms, want %d", len(vc.Vms), len(vms)-1)
+		}
+		if syncedVM := vc.SyncState.VMs[changedVM.Reference().Value]; syncedVM.Missing {
+			t.Errorf("retrieved vm %s is still marked as missing", syncedVM.Name)
+		}
+	})
+}
+
+// moveToNewFolder moves vm to a new folder in the datacenter's vm folder.
+func moveToNewFolder(ctx context.Context, t *testing.T, c *vim25.Client, vm *object.VirtualMachine, name string) *object.Folder {
+	t.Helper()
+	dc, err := find.NewFinder(c).DefaultDatacenter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dcFolders, err := dc.Folders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	folder, err := dcFolders.VmFolder.CreateFolder(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := folder.MoveInto(ctx, []types.ManagedObjectReference{vm.Reference()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := task.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return folder
+}
@@ -0,0 +1,124 @@
+package xen
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// Source represents a XCP-ng or Citrix Hypervisor (XenServer) pool source.
+type Source struct {
+	common.Config
+
+	// XAPI fetched data, indexed by object references. Initialized in init functions.
+	Pools          map[string]record
+	Hosts          map[string]record
+	HostMetrics    map[string]record
+	Networks       map[string]record
+	PIFs           map[string]record
+	Vms            map[string]record
+	VMGuestMetrics map[string]record
+	VIFs           map[string]record
+	VBDs           map[string]record
+	VDIs           map[string]record
+	// Relations between XAPI data. Initialized in init functions.
+	Network2Vid map[string]int // NetworkRef -> VLAN ID
+
+	// Netbox related data for easier access. Initialized in sync functions.
+	PoolRef2nbCluster map[string]*objects.Cluster // PoolRef -> nbCluster
+	HostRef2nbHost    map[string]*objects.Device  // HostRef -> nbDevice
+
+	// User defined relations
+	HostSiteRelations      map[string]string
+	ClusterSiteRelations   map[string]string
+	ClusterTenantRelations map[string]string
+	HostTenantRelations    map[string]string
+	VMTenantRelations      map[string]string
+	VlanGroupRelations     map[string]string
+	VlanTenantRelations    map[string]string
+}
+
+// Function that initializes state from XAPI to local storage.
+func (xs *Source) Init() error {
+	// Initialize regex relations
+	xs.Logger.Debug("Initializing regex relations for xen source ", xs.SourceConfig.Name)
+	xs.HostSiteRelations = utils.ConvertStringsToRegexPairs(xs.SourceConfig.HostSiteRelations)
+	xs.Logger.Debug("HostSiteRelations: ", xs.HostSiteRelations)
+	xs.ClusterSiteRelations = utils.ConvertStringsToRegexPairs(xs.SourceConfig.ClusterSiteRelations)
+	xs.Logger.Debug("ClusterSiteRelations: ", xs.ClusterSiteRelations)
+	xs.ClusterTenantRelations = utils.ConvertStringsToRegexPairs(xs.SourceConfig.ClusterTenantRelations)
+	xs.Logger.Debug("ClusterTenantRelations: ", xs.ClusterTenantRelations)
+	xs.HostTenantRelations = utils.ConvertStringsToRegexPairs(xs.SourceConfig.HostTenantRelations)
+	xs.Logger.Debug("HostTenantRelations: ", xs.HostTenantRelations)
+	xs.VMTenantRelations = utils.ConvertStringsToRegexPairs(xs.SourceConfig.VMTenantRelations)
+	xs.Logger.Debug("VMTenantRelations: ", xs.VMTenantRelations)
+	xs.VlanGroupRelations = utils.ConvertStringsToRegexPairs(xs.SourceConfig.VlanGroupRelations)
+	xs.Logger.Debug("VlanGroupRelations: ", xs.VlanGroupRelations)
+	xs.VlanTenantRelations = utils.ConvertStringsToRegexPairs(xs.SourceConfig.VlanTenantRelations)
+	xs.Logger.Debug("VlanTenantRelations: ", xs.VlanTenantRelations)
+	xs.ClusterVRFRelations = utils.ConvertStringsToRegexPairs(xs.SourceConfig.ClusterVRFRelations)
+	xs.Logger.Debug("ClusterVRFRelations: ", xs.ClusterVRFRelations)
+	xs.SubnetVRFRelations = utils.ConvertStringsToPairs(xs.SourceConfig.SubnetVRFRelations)
+	xs.Logger.Debug("SubnetVRFRelations: ", xs.SubnetVRFRelations)
+
+	// XAPI calls must be sent to the pool master
+	xapiURL := fmt.Sprintf("%s://%s:%d", xs.SourceConfig.HTTPScheme, xs.SourceConfig.Hostname, xs.SourceConfig.Port)
+	client, err := newAPIClient(xapiURL, xs.SourceConfig.ValidateCert)
+	if err != nil {
+		return fmt.Errorf("creating xapi client: %s", err)
+	}
+	if err := client.login(xs.SourceConfig.Username, xs.SourceConfig.Password); err != nil {
+		return fmt.Errorf("xapi login: %s", err)
+	}
+	defer func() {
+		if err := client.logout(); err != nil {
+			xs.Logger.Warningf("xapi logout: %s", err)
+		}
+	}()
+
+	// Initialize items from XAPI to local storage
+	initFunctions := []func(*apiClient) error{
+		xs.InitPools,
+		xs.InitNetworks,
+		xs.InitHosts,
+		xs.InitVms,
+	}
+
+	for _, initFunc := range initFunctions {
+		startTime := time.Now()
+		if err := initFunc(client); err != nil {
+			return fmt.Errorf("xen initialization failure: %v", err)
+		}
+		duration := time.Since(startTime)
+		xs.Logger.Infof("Successfully initialized %s in %f seconds", utils.ExtractFunctionName(initFunc), duration.Seconds())
+	}
+	return nil
+}
+
+// Function that syncs all data from XAPI to Netbox.
+func (xs *Source) Sync(nbi *inventory.NetboxInventory) error {
+	// initialize variables, that are shared between sync functions
+	xs.PoolRef2nbCluster = make(map[string]*objects.Cluster)
+	xs.HostRef2nbHost = make(map[string]*objects.Device)
+
+	syncFunctions := []func(*inventory.NetboxInventory) error{
+		xs.syncNetworks,
+		xs.syncPools,
+		xs.syncHosts,
+		xs.syncVms,
+	}
+	for _, syncFunc := range syncFunctions {
+		startTime := time.Now()
+		err := syncFunc(nbi)
+		if err != nil {
+			return err
+		}
+		duration := time.Since(startTime)
+		xs.Logger.Infof("Successfully synced %s in %f seconds", utils.ExtractFunctionName(syncFunc), duration.Seconds())
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package xen
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kolo/xmlrpc"
+)
+
+// Reference used by XAPI for empty relations.
+const nullRef = "OpaqueRef:NULL"
+
+// Minimal XAPI XML-RPC client.
+type apiClient struct {
+	rpc     *xmlrpc.Client
+	session string
+}
+
+func newAPIClient(url string, validateCert bool) (*apiClient, error) {
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: !validateCert}, //nolint:gosec
+	}
+	rpc, err := xmlrpc.NewClient(url, transport)
+	if err != nil {
+		return nil, err
+	}
+	return &apiClient{rpc: rpc}, nil
+}
+
+// call calls XAPI method and returns Value of the response,
+// if the response Status is Success.
+func (c *apiClient) call(method string, args ...interface{}) (interface{}, error) {
+	var response map[string]interface{}
+	if err := c.rpc.Call(method, args, &response); err != nil {
+		return nil, fmt.Errorf("%s: %s", method, err)
+	}
+	if status, _ := response["Status"].(string); status != "Success" {
+		return nil, fmt.Errorf("%s: %v", method, response["ErrorDescription"])
+	}
+	return response["Value"], nil
+}
+
+func (c *apiClient) login(username string, password string) error {
+	session, err := c.call("session.login_with_password", username, password)
+	if err != nil {
+		return err
+	}
+	sessionRef, ok := session.(string)
+	if !ok {
+		return fmt.Errorf("unexpected session reference: %v", session)
+	}
+	c.session = sessionRef
+	return nil
+}
+
+func (c *apiClient) logout() error {
+	_, err := c.call("session.logout", c.session)
+	c.rpc.Close()
+	return err
+}
+
+// getAllRecords returns all records of a XAPI class (e.g. VM, host, pool), indexed by their references.
+func (c *apiClient) getAllRecords(class string) (map[string]record, error) {
+	value, err := c.call(class+".get_all_records", c.session)
+	if err != nil {
+		return nil, err
+	}
+	rawRecords, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s.get_all_records: unexpected response %v", class, value)
+	}
+	records := make(map[string]record, len(rawRecords))
+	for ref, rawRecord := range rawRecords {
+		if rec, ok := rawRecord.(map[string]interface{}); ok {
+			records[ref] = rec
+		}
+	}
+	return records, nil
+}
+
+// record represents one XAPI object, as returned by get_all_records.
+type record map[string]interface{}
+
+func (r record) String(key string) string {
+	s, _ := r[key].(string)
+	return s
+}
+
+func (r record) Bool(key string) bool {
+	b, _ := r[key].(bool)
+	return b
+}
+
+// XAPI encodes int64 values as strings, because XML-RPC only supports int32.
+func (r record) Int(key string) int64 {
+	switch v := r[key].(type) {
+	case int64:
+		return v
+	case string:
+		i, _ := strconv.ParseInt(v, 10, 64)
+		return i
+	}
+	return 0
+}
+
+func (r record) StringMap(key string) map[string]string {
+	result := map[string]string{}
+	if m, ok := r[key].(map[string]interface{}); ok {
+		for k, v := range m {
+			if s, ok := v.(string); ok {
+				result[k] = s
+			}
+		}
+	}
+	return result
+}
+
+func (r record) StringSlice(key string) []string {
+	result := []string{}
+	if slice, ok := r[key].([]interface{}); ok {
+		for _, v := range slice {
+			if s, ok := v.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
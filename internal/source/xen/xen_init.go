@@ -0,0 +1,84 @@
+package xen
+
+import (
+	"fmt"
+)
+
+func (xs *Source) InitPools(c *apiClient) error {
+	pools, err := c.getAllRecords("pool")
+	if err != nil {
+		return fmt.Errorf("init pools: %s", err)
+	}
+	xs.Pools = pools
+	return nil
+}
+
+// Networks are initialized together with PIFs, because
+// vlan ids of the networks are stored on PIFs.
+func (xs *Source) InitNetworks(c *apiClient) error {
+	networks, err := c.getAllRecords("network")
+	if err != nil {
+		return fmt.Errorf("init networks: %s", err)
+	}
+	pifs, err := c.getAllRecords("PIF")
+	if err != nil {
+		return fmt.Errorf("init pifs: %s", err)
+	}
+	xs.Networks = networks
+	xs.PIFs = pifs
+	xs.Network2Vid = make(map[string]int)
+	for _, pif := range pifs {
+		// VLAN is -1 for pifs without vlan tag
+		if vid := pif.Int("VLAN"); vid > 0 {
+			xs.Network2Vid[pif.String("network")] = int(vid)
+		}
+	}
+	return nil
+}
+
+func (xs *Source) InitHosts(c *apiClient) error {
+	hosts, err := c.getAllRecords("host")
+	if err != nil {
+		return fmt.Errorf("init hosts: %s", err)
+	}
+	hostMetrics, err := c.getAllRecords("host_metrics")
+	if err != nil {
+		return fmt.Errorf("init host metrics: %s", err)
+	}
+	xs.Hosts = hosts
+	xs.HostMetrics = hostMetrics
+	return nil
+}
+
+// Templates, snapshots and control domains (dom0) are not
+// real vms, so we don't store them.
+func (xs *Source) InitVms(c *apiClient) error {
+	vms, err := c.getAllRecords("VM")
+	if err != nil {
+		return fmt.Errorf("init vms: %s", err)
+	}
+	xs.Vms = make(map[string]record, len(vms))
+	for vmRef, vm := range vms {
+		if vm.Bool("is_a_template") || vm.Bool("is_a_snapshot") || vm.Bool("is_control_domain") {
+			continue
+		}
+		xs.Vms[vmRef] = vm
+	}
+	initRecords := []struct {
+		class   string
+		records *map[string]record
+	}{
+		{"VM_guest_metrics", &xs.VMGuestMetrics},
+		{"VIF", &xs.VIFs},
+		{"VBD", &xs.VBDs},
+		{"VDI", &xs.VDIs},
+	}
+	for _, initRecord := range initRecords {
+		records, err := c.getAllRecords(initRecord.class)
+		if err != nil {
+			return fmt.Errorf("init %s: %s", initRecord.class, err)
+		}
+		*initRecord.records = records
+	}
+	return nil
+}
@@ -0,0 +1,443 @@
+package xen
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/bl4ko/netbox-ssot/internal/utils"
+)
+
+// Syncs xen networks with vlan ids as netbox vlans.
+func (xs *Source) syncNetworks(nbi *inventory.NetboxInventory) error {
+	for networkRef, vid := range xs.Network2Vid {
+		networkName := xs.Networks[networkRef].String("name_label")
+		vlanGroup, err := common.MatchVlanToGroup(nbi, networkName, xs.VlanGroupRelations)
+		if err != nil {
+			return fmt.Errorf("vlan group: %s", err)
+		}
+		vlanTenant, err := common.MatchVlanToTenant(nbi, networkName, xs.VlanTenantRelations)
+		if err != nil {
+			return fmt.Errorf("vlan tenant: %s", err)
+		}
+		_, err = nbi.AddVlan(&objects.Vlan{
+			NetboxObject: objects.NetboxObject{
+				Description: xs.Networks[networkRef].String("name_description"),
+				Tags:        xs.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: xs.SourceConfig.Name,
+				},
+			},
+			Name:   networkName,
+			Group:  vlanGroup,
+			Vid:    vid,
+			Status: &objects.VlanStatusActive,
+			Tenant: vlanTenant,
+		})
+		if err != nil {
+			return fmt.Errorf("adding vlan: %s", err)
+		}
+	}
+	return nil
+}
+
+// Xen pools are represented as netbox clusters.
+func (xs *Source) syncPools(nbi *inventory.NetboxInventory) error {
+	for poolRef, pool := range xs.Pools {
+		master := xs.Hosts[pool.String("master")]
+
+		// Pool name is empty for standalone hosts, so we use master's name instead
+		clusterName := pool.String("name_label")
+		if clusterName == "" {
+			clusterName = master.String("name_label")
+		}
+
+		clusterTypeName := master.StringMap("software_version")["product_brand"]
+		if clusterTypeName == "" {
+			clusterTypeName = "Xen"
+		}
+		clusterType, err := nbi.AddClusterType(&objects.ClusterType{
+			NetboxObject: objects.NetboxObject{
+				Tags: xs.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName: xs.SourceConfig.Name,
+				},
+			},
+			Name: clusterTypeName,
+			Slug: utils.Slugify(clusterTypeName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add xen cluster type: %v", err)
+		}
+
+		clusterSite, err := common.MatchClusterToSite(nbi, clusterName, xs.ClusterSiteRelations)
+		if err != nil {
+			return fmt.Errorf("cluster site: %s", err)
+		}
+		clusterTenant, err := common.MatchClusterToTenant(nbi, clusterName, xs.ClusterTenantRelations)
+		if err != nil {
+			return fmt.Errorf("cluster tenant: %s", err)
+		}
+		err = nbi.AddCluster(&objects.Cluster{
+			NetboxObject: objects.NetboxObject{
+				Description: pool.String("name_description"),
+				Tags:        xs.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   xs.SourceConfig.Name,
+					constants.CustomFieldSourceIDName: pool.String("uuid"),
+				},
+			},
+			Name:   clusterName,
+			Type:   clusterType,
+			Status: objects.ClusterStatusActive,
+			Site:   clusterSite,
+			Tenant: clusterTenant,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add xen pool %s as Netbox cluster: %v", clusterName, err)
+		}
+		xs.PoolRef2nbCluster[poolRef] = nbi.ClustersIndexByName[clusterName]
+	}
+	return nil
+}
+
+// Returns netbox cluster of the xen pool. XAPI connection
+// is always bound to exactly one pool.
+func (xs *Source) getCluster() *objects.Cluster {
+	for _, cluster := range xs.PoolRef2nbCluster {
+		return cluster
+	}
+	return nil
+}
+
+// Host in xen is represented as device in netbox with a
+// custom role Server.
+func (xs *Source) syncHosts(nbi *inventory.NetboxInventory) error {
+	hostCluster := xs.getCluster()
+	for hostRef, host := range xs.Hosts {
+		hostName := host.String("name_label")
+
+		hostSite, err := common.MatchHostToSite(nbi, hostName, xs.HostSiteRelations)
+		if err != nil {
+			return fmt.Errorf("hostSite: %s", err)
+		}
+		if hostSite == nil && hostCluster != nil {
+			hostSite = hostCluster.Site
+		}
+		if hostSite == nil {
+			xs.Logger.Warningf("xen host %s can't be matched to any site, so it will be skipped", hostName)
+			continue
+		}
+		hostTenant, err := common.MatchHostToTenant(nbi, hostName, xs.HostTenantRelations)
+		if err != nil {
+			return fmt.Errorf("hostTenant: %s", err)
+		}
+
+		biosStrings := host.StringMap("bios_strings")
+		manufacturerName, err := utils.MatchStringToValue(biosStrings["system-manufacturer"], objects.ManufacturerMap)
+		if err != nil {
+			return fmt.Errorf("error occurred when matching xen host %s to a Netbox manufacturer: %v", hostName, err)
+		}
+		if manufacturerName == "" {
+			manufacturerName = constants.DefaultManufacturer
+		}
+		hostManufacturer, err := nbi.AddManufacturer(&objects.Manufacturer{
+			Name: manufacturerName,
+			Slug: utils.Slugify(manufacturerName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed adding xen Manufacturer %v with error: %s", hostManufacturer, err)
+		}
+
+		hostModel := biosStrings["system-product-name"]
+		if hostModel == "" {
+			hostModel = constants.DefaultModel // Model is also required for adding device type into netbox
+		}
+		hostDeviceType, err := nbi.AddDeviceType(&objects.DeviceType{
+			Manufacturer: hostManufacturer,
+			Model:        hostModel,
+			Slug:         utils.Slugify(hostModel),
+		})
+		if err != nil {
+			return fmt.Errorf("failed adding xen DeviceType %v with error: %s", hostDeviceType, err)
+		}
+
+		softwareVersion := host.StringMap("software_version")
+		platformName := utils.GeneratePlatformName(softwareVersion["product_brand"], softwareVersion["product_version"])
+		hostPlatform, err := nbi.AddPlatform(&objects.Platform{
+			Name: platformName,
+			Slug: utils.Slugify(platformName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed adding xen Platform %v with error: %s", hostPlatform, err)
+		}
+
+		hostStatus := &objects.DeviceStatusOffline
+		if host.Bool("enabled") {
+			hostStatus = &objects.DeviceStatusActive
+		}
+
+		mem := xs.HostMetrics[host.String("metrics")].Int("memory_total")
+		mem /= (constants.KiB * constants.KiB * constants.KiB) // Value is in Bytes, we convert to GB
+
+		nbHost, err := nbi.AddDevice(&objects.Device{
+			NetboxObject: objects.NetboxObject{
+				Description: host.String("name_description"),
+				Tags:        xs.Config.SourceTags,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:       xs.SourceConfig.Name,
+					constants.CustomFieldSourceIDName:     host.String("uuid"),
+					constants.CustomFieldHostCPUCoresName: host.StringMap("cpu_info")["cpu_count"],
+					constants.CustomFieldHostMemoryName:   fmt.Sprintf("%d GB", mem),
+				},
+			},
+			Name:         hostName,
+			Status:       hostStatus,
+			Platform:     hostPlatform,
+			DeviceRole:   nbi.DeviceRolesIndexByName["Server"],
+			Site:         hostSite,
+			Tenant:       hostTenant,
+			Cluster:      hostCluster,
+			SerialNumber: biosStrings["system-serial-number"],
+			DeviceType:   hostDeviceType,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add xen host %s with error: %v", hostName, err)
+		}
+		xs.HostRef2nbHost[hostRef] = nbHost
+	}
+	return nil
+}
+
+func (xs *Source) syncVms(nbi *inventory.NetboxInventory) error {
+	vmCluster := xs.getCluster()
+	for _, vm := range xs.Vms {
+		vmName := vm.String("name_label")
+		// Host is only set for running vms
+		vmHost := xs.HostRef2nbHost[vm.String("resident_on")]
+
+		// Tenant is received from VmTenantRelations
+		vmTenant, err := common.MatchVMToTenant(nbi, vmName, xs.VMTenantRelations)
+		if err != nil {
+			return fmt.Errorf("vm's Tenant: %s", err)
+		}
+
+		// Site is the same as the Host or the Cluster
+		var vmSite *objects.Site
+		if vmHost != nil {
+			vmSite = vmHost.Site
+		} else if vmCluster != nil {
+			vmSite = vmCluster.Site
+		}
+
+		vmStatus := &objects.VMStatusOffline
+		if vm.String("power_state") == "Running" {
+			vmStatus = &objects.VMStatusActive
+		}
+
+		// Disk size is the sum of all vdis attached as disks
+		vmDiskSizeB := int64(0)
+		for _, vbdRef := range vm.StringSlice("VBDs") {
+			vbd := xs.VBDs[vbdRef]
+			if vbd.String("type") != "Disk" || vbd.String("VDI") == nullRef {
+				continue
+			}
+			vmDiskSizeB += xs.VDIs[vbd.String("VDI")].Int("virtual_size")
+		}
+
+		guestMetrics := xs.VMGuestMetrics[vm.String("guest_metrics")]
+		vmPlatformName := guestMetrics.StringMap("os_version")["name"]
+		if vmPlatformName == "" {
+			vmPlatformName = utils.GeneratePlatformName(constants.DefaultOSName, constants.DefaultOSVersion)
+		}
+		vmPlatform, err := nbi.AddPlatform(&objects.Platform{
+			Name: vmPlatformName,
+			Slug: utils.Slugify(vmPlatformName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed adding xen vm's Platform %v with error: %s", vmPlatform, err)
+		}
+
+		// netbox description has constraint <= len(200 characters)
+		// In this case we make a comment
+		vmDescription := vm.String("name_description")
+		var vmComments string
+		if len(vmDescription) >= objects.MaxDescriptionLength {
+			vmComments = vmDescription
+			vmDescription = "See comments."
+		}
+
+		nbVM, err := nbi.AddVM(&objects.VM{
+			NetboxObject: objects.NetboxObject{
+				Tags:        xs.Config.SourceTags,
+				Description: vmDescription,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   xs.SourceConfig.Name,
+					constants.CustomFieldSourceIDName: vm.String("uuid"),
+				},
+			},
+			Name:     vmName,
+			Cluster:  vmCluster,
+			Site:     vmSite,
+			Tenant:   vmTenant,
+			Status:   vmStatus,
+			Host:     vmHost,
+			Platform: vmPlatform,
+			VCPUs:    float32(vm.Int("VCPUs_max")),
+			Memory:   int(vm.Int("memory_static_max") / constants.MiB),                 // MBs
+			Disk:     int(vmDiskSizeB / constants.KiB / constants.KiB / constants.KiB), // GBs
+			Comments: vmComments,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to sync xen vm: %v", err)
+		}
+
+		err = xs.syncVMInterfaces(nbi, vm, guestMetrics, nbVM)
+		if err != nil {
+			return fmt.Errorf("failed to sync xen vm's interfaces: %v", err)
+		}
+	}
+	return nil
+}
+
+// Syncs VM's VIFs to Netbox, together with ip addresses reported by guest metrics.
+func (xs *Source) syncVMInterfaces(nbi *inventory.NetboxInventory, vm record, guestMetrics record, nbVM *objects.VM) error {
+	device2IPs := collectGuestIPs(guestMetrics.StringMap("networks"))
+
+	var vmIPv4PrimaryAddress *objects.IPAddress
+	var vmIPv6PrimaryAddress *objects.IPAddress
+	for _, vifRef := range vm.StringSlice("VIFs") {
+		vif, ok := xs.VIFs[vifRef]
+		if !ok {
+			continue
+		}
+		vifDevice := vif.String("device")
+		networkRef := vif.String("network")
+		networkName := xs.Networks[networkRef].String("name_label")
+
+		intName := fmt.Sprintf("vNic %s", vifDevice)
+		if networkName != "" {
+			intName = fmt.Sprintf("%s (%s)", intName, networkName)
+		}
+
+		var intMode *objects.VMInterfaceMode
+		var intUntaggedVlan *objects.Vlan
+		var intDescription string
+		if vid, ok := xs.Network2Vid[networkRef]; ok {
+			vlanGroup, err := common.MatchVlanToGroup(nbi, networkName, xs.VlanGroupRelations)
+			if err != nil {
+				return fmt.Errorf("vlan group: %s", err)
+			}
+			intMode = &objects.VMInterfaceModeAccess
+			intUntaggedVlan = nbi.VlansIndexByVlanGroupIDAndVID[vlanGroup.ID][vid]
+			intDescription = fmt.Sprintf("vlan ID: %d", vid)
+		}
+
+		nbVMInterface, err := nbi.AddVMInterface(&objects.VMInterface{
+			NetboxObject: objects.NetboxObject{
+				Tags:        xs.Config.SourceTags,
+				Description: intDescription,
+				CustomFields: map[string]interface{}{
+					constants.CustomFieldSourceName:   xs.SourceConfig.Name,
+					constants.CustomFieldSourceIDName: vif.String("uuid"),
+				},
+			},
+			VM:           nbVM,
+			Name:         intName,
+			MACAddress:   strings.ToUpper(vif.String("MAC")),
+			MTU:          int(vif.Int("MTU")),
+			Mode:         intMode,
+			Enabled:      vif.Bool("currently_attached"),
+			UntaggedVlan: intUntaggedVlan,
+		})
+		if err != nil {
+			return fmt.Errorf("adding VmInterface: %s", err)
+		}
+
+		for _, ip := range device2IPs[vifDevice] {
+			if !xs.IsPermittedIPAddress(ip) {
+				continue
+			}
+			// Guest agent doesn't report prefix lengths, so we use host masks
+			ipVersion := utils.GetIPVersion(ip)
+			ipAddress := fmt.Sprintf("%s/32", ip)
+			if ipVersion == constants.IPv6 {
+				ipAddress = fmt.Sprintf("%s/128", ip)
+			}
+			vrf, err := xs.MatchIPToVRF(nbi, ipAddress, nbVM.Cluster)
+			if err != nil {
+				xs.Logger.Warningf("matching ip address to vrf: %s", err)
+				continue
+			}
+			nbIPAddress, err := nbi.AddIPAddress(&objects.IPAddress{
+				NetboxObject: objects.NetboxObject{
+					Tags: xs.Config.SourceTags,
+					CustomFields: map[string]interface{}{
+						constants.CustomFieldSourceName: xs.SourceConfig.Name,
+					},
+				},
+				Address:            ipAddress,
+				VRF:                vrf,
+				Status:             &objects.IPAddressStatusActive,
+				DNSName:            xs.DNSName(ip, ""),
+				AssignedObjectType: objects.AssignedObjectTypeVMInterface,
+				AssignedObjectID:   nbVMInterface.ID,
+			})
+			if err != nil {
+				xs.Logger.Warningf("adding ip address: %s", err)
+				continue
+			}
+			switch ipVersion {
+			case constants.IPv4:
+				if vmIPv4PrimaryAddress == nil {
+					vmIPv4PrimaryAddress = nbIPAddress
+				}
+			case constants.IPv6:
+				if vmIPv6PrimaryAddress == nil {
+					vmIPv6PrimaryAddress = nbIPAddress
+				}
+			}
+		}
+	}
+
+	// Primary ips are the first collected ipv4 and ipv6 addresses
+	if vmIPv4PrimaryAddress != nil || vmIPv6PrimaryAddress != nil {
+		newNbVM := *nbVM
+		newNbVM.PrimaryIPv4 = vmIPv4PrimaryAddress
+		newNbVM.PrimaryIPv6 = vmIPv6PrimaryAddress
+		_, err := nbi.AddVM(&newNbVM)
+		if err != nil {
+			return fmt.Errorf("updating vm's primary ip: %s", err)
+		}
+	}
+	return nil
+}
+
+// collectGuestIPs converts networks map from VM_guest_metrics
+// (e.g. {"0/ip": "10.0.0.5", "0/ipv4/0": "10.0.0.5", "0/ipv6/0": "fe80::1"})
+// into map of vif device to sorted list of unique ip addresses. Link local addresses are skipped.
+func collectGuestIPs(networks map[string]string) map[string][]string {
+	device2IPs := make(map[string][]string)
+	for key, ip := range networks {
+		device, _, found := strings.Cut(key, "/")
+		if !found {
+			continue
+		}
+		parsedIP := net.ParseIP(ip)
+		if parsedIP == nil || parsedIP.IsLinkLocalUnicast() {
+			continue
+		}
+		if !slices.Contains(device2IPs[device], ip) {
+			device2IPs[device] = append(device2IPs[device], ip)
+		}
+	}
+	for device := range device2IPs {
+		slices.Sort(device2IPs[device])
+	}
+	return device2IPs
+}
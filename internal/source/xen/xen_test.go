@@ -0,0 +1,254 @@
+package xen
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bl4ko/netbox-ssot/internal/constants"
+	"github.com/bl4ko/netbox-ssot/internal/logger"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/inventory/inventorytest"
+	"github.com/bl4ko/netbox-ssot/internal/netbox/objects"
+	"github.com/bl4ko/netbox-ssot/internal/parser"
+	"github.com/bl4ko/netbox-ssot/internal/source/common"
+	"github.com/kolo/xmlrpc"
+)
+
+// XAPI records of a standalone host, returned by get_all_records and indexed by XAPI class.
+var xapiRecords = map[string]map[string]interface{}{
+	"pool": {
+		"OpaqueRef:pool1": map[string]interface{}{"uuid": "p1", "name_label": "", "master": "OpaqueRef:host1"},
+	},
+	"host": {
+		"OpaqueRef:host1": map[string]interface{}{
+			"uuid":             "h1",
+			"name_label":       "xcp1",
+			"enabled":          true,
+			"metrics":          "OpaqueRef:hm1",
+			"software_version": map[string]interface{}{"product_brand": "XCP-ng", "product_version": "8.2.1"},
+			"cpu_info":         map[string]interface{}{"cpu_count": "16"},
+			"bios_strings":     map[string]interface{}{"system-manufacturer": "Dell Inc.", "system-product-name": "PowerEdge R640", "system-serial-number": "ABC123"},
+		},
+	},
+	"host_metrics": {
+		"OpaqueRef:hm1": map[string]interface{}{"memory_total": "137438953472"},
+	},
+	"network": {
+		"OpaqueRef:net1": map[string]interface{}{"name_label": "prod", "PIFs": []interface{}{"OpaqueRef:pif1"}},
+		"OpaqueRef:net2": map[string]interface{}{"name_label": "Pool-wide network associated with eth0", "PIFs": []interface{}{"OpaqueRef:pif2"}},
+	},
+	"PIF": {
+		"OpaqueRef:pif1": map[string]interface{}{"network": "OpaqueRef:net1", "VLAN": "100"},
+		"OpaqueRef:pif2": map[string]interface{}{"network": "OpaqueRef:net2", "VLAN": "-1"},
+	},
+	"VM": {
+		"OpaqueRef:vm1": map[string]interface{}{
+			"uuid":          "v1",
+			"name_label":    "vm1",
+			"power_state":   "Running",
+			"resident_on":   "OpaqueRef:host1",
+			"guest_metrics": "OpaqueRef:gm1",
+			"VIFs":          []interface{}{"OpaqueRef:vif1"},
+			"VBDs":          []interface{}{"OpaqueRef:vbd1", "OpaqueRef:vbd2", "OpaqueRef:cd"},
+		},
+		"OpaqueRef:tmpl": map[string]interface{}{"uuid": "t1", "name_label": "Debian Template", "is_a_template": true},
+		"OpaqueRef:dom0": map[string]interface{}{"uuid": "d0", "name_label": "Control domain on host: xcp1", "is_control_domain": true},
+		"OpaqueRef:snap": map[string]interface{}{"uuid": "s1", "name_label": "vm1 snapshot", "is_a_snapshot": true},
+	},
+	"VM_guest_metrics": {
+		"OpaqueRef:gm1": map[string]interface{}{"networks": map[string]interface{}{"0/ip": "10.0.0.5", "0/ipv4/0": "10.0.0.5", "0/ipv6/0": "fe80::1", "0/ipv6/1": "2001:db8::5"}},
+	},
+	"VIF": {
+		"OpaqueRef:vif1": map[string]interface{}{"uuid": "vif1", "device": "0", "MAC": "aa:bb:cc:dd:ee:ff", "network": "OpaqueRef:net1"},
+	},
+	"VBD": {
+		"OpaqueRef:vbd1": map[string]interface{}{"type": "Disk", "VDI": "OpaqueRef:vdi1"},
+		"OpaqueRef:vbd2": map[string]interface{}{"type": "Disk", "VDI": "OpaqueRef:vdi2"},
+		"OpaqueRef:cd":   map[string]interface{}{"type": "CD", "VDI": "OpaqueRef:iso"},
+	},
+	"VDI": {
+		"OpaqueRef:vdi1": map[string]interface{}{"virtual_size": "10737418240"},
+		"OpaqueRef:vdi2": map[string]interface{}{"virtual_size": "21474836480"},
+		"OpaqueRef:iso":  map[string]interface{}{"virtual_size": "4294967296"},
+	},
+}
+
+var methodNameRegex = regexp.MustCompile(`<methodName>(.*)</methodName>`)
+
+// newXAPI returns client of XAPI server, that serves xapiRecords. Client is logged in with password.
+func newXAPI(t *testing.T, password string) (*apiClient, error) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request: %s", err)
+			return
+		}
+		method := methodNameRegex.FindStringSubmatch(string(body))[1]
+		response := map[string]interface{}{"Status": "Success"}
+		switch {
+		case method == "session.login_with_password" && !strings.Contains(string(body), "<string>secret</string>"):
+			response = map[string]interface{}{"Status": "Failure", "ErrorDescription": []interface{}{"SESSION_AUTHENTICATION_FAILED"}}
+		case method == "session.login_with_password":
+			response["Value"] = "OpaqueRef:session"
+		case strings.HasSuffix(method, ".get_all_records"):
+			response["Value"] = xapiRecords[strings.TrimSuffix(method, ".get_all_records")]
+		default:
+			response = map[string]interface{}{"Status": "Failure", "ErrorDescription": []interface{}{"MESSAGE_METHOD_UNKNOWN"}}
+		}
+		// Reuse method call encoding for the response params
+		encoded, err := xmlrpc.EncodeMethodCall(method, response)
+		if err != nil {
+			t.Errorf("encoding response: %s", err)
+			return
+		}
+		params := string(encoded)[strings.Index(string(encoded), "<params>"):strings.LastIndex(string(encoded), "</methodCall>")]
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse>` + params + `</methodResponse>`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := newAPIClient(server.URL, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, client.login("root", password)
+}
+
+// syncXAPI collects all records of XAPI and syncs them to a new inventory.
+func syncXAPI(t *testing.T) *inventory.NetboxInventory {
+	t.Helper()
+	testLogger, err := logger.New("", logger.ERROR, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	xs := &Source{
+		Config: common.Config{
+			Logger:       testLogger,
+			SourceConfig: &parser.SourceConfig{Name: "xen", DisableReverseLookup: true},
+		},
+		HostSiteRelations: map[string]string{".*": "site1"},
+	}
+	client, err := newXAPI(t, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, initFunc := range []func(*apiClient) error{xs.InitPools, xs.InitNetworks, xs.InitHosts, xs.InitVms} {
+		if err := initFunc(client); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nbi, _ := inventorytest.NewInventory(t)
+	if _, err := nbi.AddSite(&objects.Site{Name: "site1", Slug: "site1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nbi.AddVlanGroup(&objects.VlanGroup{Name: objects.DefaultVlanGroupName, Slug: "default"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := xs.Sync(nbi); err != nil {
+		t.Fatal(err)
+	}
+	return nbi
+}
+
+func TestSyncStandaloneHost(t *testing.T) {
+	nbi := syncXAPI(t)
+	// Pool of a standalone host has no name, so it is named after its master
+	cluster, ok := nbi.ClustersIndexByName["xcp1"]
+	if !ok {
+		t.Fatalf("pool of xcp1 was not synced as a cluster")
+	}
+	host := nbi.DevicesIndexBySourceAndSourceID["xen"]["h1"]
+	if host == nil {
+		t.Fatal("host xcp1 was not synced")
+	}
+	if host.Cluster == nil || host.Cluster.ID != cluster.ID || host.SerialNumber != "ABC123" || host.CustomFields[constants.CustomFieldHostMemoryName] != "128 GB" {
+		t.Errorf("host xcp1 = %+v, want serial number ABC123 and 128 GB memory in cluster xcp1", host)
+	}
+	if vlan := nbi.VlansIndexByVlanGroupIDAndVID[nbi.VlanGroupsIndexByName[objects.DefaultVlanGroupName].ID][100]; vlan == nil || vlan.Name != "prod" {
+		t.Errorf("vlan 100 = %v, want prod", vlan)
+	}
+}
+
+func TestSyncSkipsTemplatesSnapshotsAndDom0(t *testing.T) {
+	nbi := syncXAPI(t)
+	vms := nbi.VMsIndexBySourceAndSourceID["xen"]
+	if len(vms) != 1 || vms["v1"] == nil {
+		t.Fatalf("synced vms = %v, want only vm1", vms)
+	}
+	// Cds are not counted, only disks (10 GB + 20 GB)
+	if vm := vms["v1"]; vm.Disk != 30 || vm.Host == nil || vm.Host.ID != nbi.DevicesIndexBySourceAndSourceID["xen"]["h1"].ID {
+		t.Errorf("vm1 = %+v, want 30 GB disk on host xcp1", vm)
+	}
+}
+
+func TestSyncVMIPAddresses(t *testing.T) {
+	nbi := syncXAPI(t)
+	vm := nbi.VMsIndexBySourceAndSourceID["xen"]["v1"]
+	vmInterface := nbi.VMInterfacesIndexByVMIdAndName[vm.ID]["vNic 0 (prod)"]
+	if vmInterface == nil {
+		t.Fatalf("interfaces of vm1 = %v, want vNic 0 (prod)", nbi.VMInterfacesIndexByVMIdAndName[vm.ID])
+	}
+	address2ID := make(map[string]int)
+	for _, vrfIPAddresses := range nbi.IPAddressesIndexByVRFIDAndAddress {
+		for address, ipAddress := range vrfIPAddresses {
+			if ipAddress.AssignedObjectID == vmInterface.ID {
+				address2ID[address] = ipAddress.ID
+			}
+		}
+	}
+	// Duplicated guest addresses are synced once and link local addresses are skipped
+	if len(address2ID) != 2 || address2ID["10.0.0.5/32"] == 0 || address2ID["2001:db8::5/128"] == 0 {
+		t.Fatalf("ip addresses of vNic 0 = %v, want 10.0.0.5/32 and 2001:db8::5/128", address2ID)
+	}
+	if vm.PrimaryIPv4 == nil || vm.PrimaryIPv4.ID != address2ID["10.0.0.5/32"] || vm.PrimaryIPv6 == nil || vm.PrimaryIPv6.ID != address2ID["2001:db8::5/128"] {
+		t.Errorf("primary ips of vm1 = %v, %v, want 10.0.0.5/32 and 2001:db8::5/128", vm.PrimaryIPv4, vm.PrimaryIPv6)
+	}
+}
+
+func TestLoginWrongCredentials(t *testing.T) {
+	if _, err := newXAPI(t, "wrong"); err == nil {
+		t.Errorf("login() expected error for wrong credentials")
+	}
+}
+
+func TestCollectGuestIPs(t *testing.T) {
+	tests := []struct {
+		name     string
+		networks map[string]string
+		expected map[string][]string
+	}{
+		{
+			name:     "Empty networks",
+			networks: map[string]string{},
+			expected: map[string][]string{},
+		},
+		{
+			name:     "Duplicated ipv4 keys",
+			networks: map[string]string{"0/ip": "10.0.0.5", "0/ipv4/0": "10.0.0.5"},
+			expected: map[string][]string{"0": {"10.0.0.5"}},
+		},
+		{
+			name:     "Multiple devices with ipv6 and link local",
+			networks: map[string]string{"0/ip": "10.0.0.5", "0/ipv6/0": "fe80::1", "1/ipv6/0": "2001:db8::5", "1/ipv4/0": "192.168.1.5"},
+			expected: map[string][]string{"0": {"10.0.0.5"}, "1": {"192.168.1.5", "2001:db8::5"}},
+		},
+		{
+			name:     "Invalid keys and values",
+			networks: map[string]string{"ip": "10.0.0.5", "0/ip": "not an ip"},
+			expected: map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collectGuestIPs(tt.networks)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("collectGuestIPs() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
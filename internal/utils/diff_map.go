@@ -43,14 +43,16 @@ func hasPriorityOver(newObj, existingObj reflect.Value, source2priority map[stri
 
 	// Check if fields are valid and present in the sourcePriority map
 	if newObjCustomFields.IsValid() && existingObjCustomFields.IsValid() {
-		if newCustomFields, ok := newObjCustomFields.Interface().(map[string]string); ok {
-			if existingCustomFields, ok := existingObjCustomFields.Interface().(map[string]string); ok {
+		if newCustomFields, ok := newObjCustomFields.Interface().(map[string]interface{}); ok {
+			if existingCustomFields, ok := existingObjCustomFields.Interface().(map[string]interface{}); ok {
+				newSource, _ := newCustomFields[constants.CustomFieldSourceName].(string)
+				existingSource, _ := existingCustomFields[constants.CustomFieldSourceName].(string)
 				newPriority := int(^uint(0) >> 1) // max int
-				if priority, newOk := source2priority[newCustomFields[constants.CustomFieldSourceName]]; newOk {
+				if priority, newOk := source2priority[newSource]; newOk {
 					newPriority = priority
 				}
 				existingPriority := int(^uint(0) >> 1)
-				if priority, existingOk := source2priority[existingCustomFields[constants.CustomFieldSourceName]]; existingOk {
+				if priority, existingOk := source2priority[existingSource]; existingOk {
 					existingPriority = priority
 				}
 
@@ -337,7 +339,7 @@ func addMapDiff(newMap reflect.Value, existingMap reflect.Value, jsonTag string,
 		if keyValue, ok := key.Interface().(string); ok {
 			if !existingMap.MapIndex(key).IsValid() {
 				mapsDiff[keyValue] = newMap.MapIndex(key).Interface()
-			} else if newMap.MapIndex(key).Interface() != existingMap.MapIndex(key).Interface() {
+			} else if !mapValuesEqual(newMap.MapIndex(key).Interface(), existingMap.MapIndex(key).Interface()) {
 				if hasPriority {
 					mapsDiff[keyValue] = newMap.MapIndex(key).Interface()
 				}
@@ -362,6 +364,30 @@ func addMapDiff(newMap reflect.Value, existingMap reflect.Value, jsonTag string,
 	return nil
 }
 
+// mapValuesEqual compares two map values. Numbers are compared by their value,
+// because numbers unmarshalled from json are always float64.
+func mapValuesEqual(newValue, existingValue interface{}) bool {
+	if reflect.DeepEqual(newValue, existingValue) {
+		return true
+	}
+	newNumber, newOk := toFloat(reflect.ValueOf(newValue))
+	existingNumber, existingOk := toFloat(reflect.ValueOf(existingValue))
+	return newOk && existingOk && newNumber == existingNumber
+}
+
+// toFloat converts numeric value to float64.
+func toFloat(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
 func addPrimaryDiff(newField reflect.Value, existingField reflect.Value, jsonTag string, hasPriority bool, diffMap map[string]interface{}) {
 	switch {
 	case newField.IsZero():
@@ -332,7 +332,7 @@ func TestMapAttributeDiff(t *testing.T) {
 			resetFields: true,
 			newStruct: &objects.Device{
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldHostCPUCoresName: "10 cpu cores",
 						constants.CustomFieldHostMemoryName:   "10 GB",
 						constants.CustomFieldSourceIDName:     "123456789",
@@ -341,7 +341,7 @@ func TestMapAttributeDiff(t *testing.T) {
 			},
 			existingStruct: &objects.Device{
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldHostCPUCoresName: "5 cpu cores",
 						"existing_tag1":                       "existing_tag1",
 						"existing_tag2":                       "existing_tag2",
@@ -363,7 +363,7 @@ func TestMapAttributeDiff(t *testing.T) {
 			resetFields: true,
 			newStruct: &objects.Device{
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldHostCPUCoresName: "10 cpu cores",
 						constants.CustomFieldHostMemoryName:   "10 GB",
 					},
@@ -372,7 +372,7 @@ func TestMapAttributeDiff(t *testing.T) {
 
 			existingStruct: &objects.Device{
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldHostCPUCoresName: "10 cpu cores",
 						constants.CustomFieldHostMemoryName:   "10 GB",
 						"existing_tag1":                       "existing_tag1",
@@ -387,7 +387,7 @@ func TestMapAttributeDiff(t *testing.T) {
 			resetFields: true,
 			newStruct: &objects.Device{
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldHostCPUCoresName: "5 cpu cores",
 						constants.CustomFieldHostMemoryName:   "10 GB",
 					},
@@ -395,7 +395,7 @@ func TestMapAttributeDiff(t *testing.T) {
 			},
 			existingStruct: &objects.Device{
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldHostCPUCoresName: "10 cpu cores",
 						constants.CustomFieldHostMemoryName:   "10 GB",
 						"existing_tag1":                       "existing_tag1",
@@ -411,6 +411,27 @@ func TestMapAttributeDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "Map no diff with typed values",
+			resetFields: true,
+			newStruct: &objects.Device{
+				NetboxObject: objects.NetboxObject{
+					CustomFields: map[string]interface{}{
+						"cost_center": int64(42),
+						"backup":      true,
+					},
+				},
+			},
+			existingStruct: &objects.Device{
+				NetboxObject: objects.NetboxObject{
+					CustomFields: map[string]interface{}{
+						"cost_center": float64(42), // numbers are unmarshalled as float64
+						"backup":      true,
+					},
+				},
+			},
+			expectedDiff: map[string]interface{}{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -442,7 +463,7 @@ func TestPriorityMergeDiff(t *testing.T) {
 				Name: "Vlan1000",
 				Vid:  1000,
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldSourceName: "test1",
 					},
 					Tags: []*objects.Tag{
@@ -455,7 +476,7 @@ func TestPriorityMergeDiff(t *testing.T) {
 				Name: "1000Vlan",
 				Vid:  1000,
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldSourceName: "test2",
 					},
 					Tags: []*objects.Tag{
@@ -484,7 +505,7 @@ func TestPriorityMergeDiff(t *testing.T) {
 				Vid:      1000,
 				Comments: "Added comment",
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldSourceName: "test1",
 					},
 					Tags: []*objects.Tag{
@@ -497,7 +518,7 @@ func TestPriorityMergeDiff(t *testing.T) {
 				Name: "1000Vlan",
 				Vid:  1000,
 				NetboxObject: objects.NetboxObject{
-					CustomFields: map[string]string{
+					CustomFields: map[string]interface{}{
 						constants.CustomFieldSourceName: "test2",
 					},
 					Tags: []*objects.Tag{
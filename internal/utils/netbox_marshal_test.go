@@ -164,6 +164,37 @@ func TestNetboxJsonMarshalWithChoiceAttr(t *testing.T) {
 	}
 }
 
+func TestNetboxJsonMarshalCable(t *testing.T) {
+	cable := &objects.Cable{
+		NetboxObject: objects.NetboxObject{
+			Tags: []*objects.Tag{
+				{ID: 1, Name: "Test", Slug: "test", Color: "000000", Description: "Test tag"},
+			},
+		},
+		ATerminations: []*objects.CableTermination{{ObjectType: objects.CableTerminationTypeInterface, ObjectID: 10}},
+		BTerminations: []*objects.CableTermination{{ObjectType: objects.CableTerminationTypeInterface, ObjectID: 20}},
+		Status:        &objects.CableStatusConnected,
+	}
+
+	expectedMap := map[string]interface{}{
+		"tags":           []int{1},
+		"a_terminations": []objects.CableTermination{{ObjectType: "dcim.interface", ObjectID: 10}},
+		"b_terminations": []objects.CableTermination{{ObjectType: "dcim.interface", ObjectID: 20}},
+		"status":         "connected",
+	}
+	expectedJSON, err := json.Marshal(expectedMap)
+	if err != nil {
+		t.Errorf("NetboxMarshal() error = %v", err)
+	}
+	responseJSON, err := NetboxJSONMarshal(cable)
+	if err != nil {
+		t.Errorf("NetboxMarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(expectedJSON, responseJSON) {
+		t.Errorf("NetboxMarshal() = %s\nwant %s", string(responseJSON), string(expectedJSON))
+	}
+}
+
 // func TestNetboxJsonMarshalComplex(t *testing.T) {
 // 	testDevice := objects.Interface{
 // 		NetboxObject: objects.NetboxObject{
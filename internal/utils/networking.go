@@ -8,37 +8,6 @@ import (
 	"github.com/bl4ko/netbox-ssot/internal/constants"
 )
 
-// Function that receives ipAddress and performs a reverse lookup
-// to get the hostname. If the reverse lookup fails, it returns an empty string.
-func ReverseLookup(ipAddress string) string {
-	names, err := net.LookupAddr(ipAddress)
-	if err != nil {
-		return ""
-	}
-
-	if len(names) > 0 {
-		domain := strings.TrimSuffix(names[0], ".")
-		return domain
-	}
-
-	return ""
-}
-
-// Function that receives hostname and performs a forward lookup
-// to get the IP address. If the forward lookup fails, it returns an empty string.
-func Lookup(hostname string) string {
-	ips, err := net.LookupIP(hostname)
-	if err != nil {
-		return ""
-	}
-
-	if len(ips) > 0 {
-		return ips[0].String()
-	}
-
-	return ""
-}
-
 // Function that converts string representation of ipv4 mask (e.g. 255.255.255.128) to
 // bit representation (e.g. 25).
 func MaskToBits(mask string) (int, error) {
@@ -78,3 +47,86 @@ func SubnetContainsIPAddress(ipAddress string, subnet string) bool {
 	}
 	return ipnet.Contains(ip)
 }
+
+// IsPermittedIPAddress checks if given IP address (with or without mask)
+// is part of at least one of permittedSubnets and is not part of any of ignoredSubnets.
+// If permittedSubnets is empty, all IP addresses are permitted.
+// e.g. ipAddress "172.31.4.129/24", permittedSubnets ["172.31.0.0/16"] and ignoredSubnets ["172.31.4.0/24"]
+// Return false.
+func IsPermittedIPAddress(ipAddress string, permittedSubnets []string, ignoredSubnets []string) bool {
+	ipAddress = strings.Split(ipAddress, "/")[0]
+	for _, subnet := range ignoredSubnets {
+		if SubnetContainsIPAddress(ipAddress, subnet) {
+			return false
+		}
+	}
+	if len(permittedSubnets) == 0 {
+		return true
+	}
+	for _, subnet := range permittedSubnets {
+		if SubnetContainsIPAddress(ipAddress, subnet) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchIPAddressToSubnet returns the most specific subnet (longest prefix) of subnets,
+// that contains given IP address (with or without mask). If no subnet contains it,
+// empty string is returned.
+// e.g. ipAddress "10.1.2.3/24" and subnets ["10.0.0.0/8", "10.1.0.0/16"]
+// Return "10.1.0.0/16".
+func MatchIPAddressToSubnet(ipAddress string, subnets []string) string {
+	ipAddress = strings.Split(ipAddress, "/")[0]
+	match := ""
+	matchBits := -1
+	for _, subnet := range subnets {
+		_, ipnet, err := net.ParseCIDR(subnet)
+		if err != nil || !SubnetContainsIPAddress(ipAddress, subnet) {
+			continue
+		}
+		if bits, _ := ipnet.Mask.Size(); bits > matchBits {
+			match = subnet
+			matchBits = bits
+		}
+	}
+	return match
+}
+
+// GetPrefix returns network prefix of the given IP address with mask.
+// e.g. ipAddress "172.31.4.129/25"
+// Return "172.31.4.128/25".
+func GetPrefix(ipAddress string) (string, error) {
+	_, ipnet, err := net.ParseCIDR(ipAddress)
+	if err != nil {
+		return "", err
+	}
+	return ipnet.String(), nil
+}
+
+// IsHostPrefix returns true if prefix contains only a single ip address (/32 for ipv4 and /128 for ipv6).
+func IsHostPrefix(prefix string) bool {
+	_, ipnet, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return false
+	}
+	ones, bits := ipnet.Mask.Size()
+	return ones == bits
+}
+
+// SubnetContainsSubnet checks if given prefix is fully contained in the given subnet.
+// e.g. prefix "10.1.2.0/24" and subnet "10.1.0.0/16"
+// Return true.
+func SubnetContainsSubnet(prefix string, subnet string) bool {
+	_, prefixNet, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return false
+	}
+	_, subnetNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return false
+	}
+	prefixOnes, prefixBits := prefixNet.Mask.Size()
+	subnetOnes, subnetBits := subnetNet.Mask.Size()
+	return prefixBits == subnetBits && prefixOnes >= subnetOnes && subnetNet.Contains(prefixNet.IP)
+}
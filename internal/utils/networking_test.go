@@ -1 +1,143 @@
 package utils
+
+import "testing"
+
+func TestIsPermittedIPAddress(t *testing.T) {
+	tests := []struct {
+		name             string
+		ipAddress        string
+		permittedSubnets []string
+		ignoredSubnets   []string
+		expected         bool
+	}{
+		{
+			name:      "No subnets",
+			ipAddress: "172.17.0.1/16",
+			expected:  true,
+		},
+		{
+			name:             "Permitted ip address",
+			ipAddress:        "10.0.1.5/24",
+			permittedSubnets: []string{"192.168.0.0/16", "10.0.0.0/8"},
+			expected:         true,
+		},
+		{
+			name:             "Ip address outside of permitted subnets",
+			ipAddress:        "169.254.10.1/16",
+			permittedSubnets: []string{"10.0.0.0/8"},
+			expected:         false,
+		},
+		{
+			name:           "Ignored ip address without mask",
+			ipAddress:      "172.17.0.1",
+			ignoredSubnets: []string{"172.17.0.0/16"},
+			expected:       false,
+		},
+		{
+			name:             "Ignored subnet has precedence over permitted subnet",
+			ipAddress:        "10.10.0.5/24",
+			permittedSubnets: []string{"10.0.0.0/8"},
+			ignoredSubnets:   []string{"10.10.0.0/16"},
+			expected:         false,
+		},
+		{
+			name:             "Ipv6 address",
+			ipAddress:        "fe80::1/64",
+			permittedSubnets: []string{"2001:db8::/32"},
+			ignoredSubnets:   []string{"fe80::/10"},
+			expected:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermittedIPAddress(tt.ipAddress, tt.permittedSubnets, tt.ignoredSubnets); got != tt.expected {
+				t.Errorf("IsPermittedIPAddress() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchIPAddressToSubnet(t *testing.T) {
+	tests := []struct {
+		name      string
+		ipAddress string
+		subnets   []string
+		expected  string
+	}{
+		{
+			name:      "No subnets",
+			ipAddress: "10.1.2.3/24",
+			expected:  "",
+		},
+		{
+			name:      "Most specific subnet",
+			ipAddress: "10.1.2.3/24",
+			subnets:   []string{"10.0.0.0/8", "10.1.0.0/16", "192.168.0.0/16"},
+			expected:  "10.1.0.0/16",
+		},
+		{
+			name:      "Prefix",
+			ipAddress: "10.1.2.0/24",
+			subnets:   []string{"10.1.0.0/16"},
+			expected:  "10.1.0.0/16",
+		},
+		{
+			name:      "No matching subnet",
+			ipAddress: "172.16.0.1",
+			subnets:   []string{"10.0.0.0/8"},
+			expected:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchIPAddressToSubnet(tt.ipAddress, tt.subnets); got != tt.expected {
+				t.Errorf("MatchIPAddressToSubnet() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetPrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		ipAddress string
+		expected  string
+		wantErr   bool
+	}{
+		{name: "Ipv4 address", ipAddress: "172.31.4.129/25", expected: "172.31.4.128/25"},
+		{name: "Ipv6 address", ipAddress: "2001:db8::1:2/64", expected: "2001:db8::/64"},
+		{name: "Address without mask", ipAddress: "172.31.4.129", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetPrefix(tt.ipAddress)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetPrefix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("GetPrefix() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSubnetContainsSubnet(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		subnet   string
+		expected bool
+	}{
+		{name: "Contained prefix", prefix: "10.1.2.0/24", subnet: "10.1.0.0/16", expected: true},
+		{name: "Same prefix", prefix: "10.1.0.0/16", subnet: "10.1.0.0/16", expected: true},
+		{name: "Larger prefix", prefix: "10.0.0.0/8", subnet: "10.1.0.0/16", expected: false},
+		{name: "Different ip version", prefix: "::/0", subnet: "0.0.0.0/0", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SubnetContainsSubnet(tt.prefix, tt.subnet); got != tt.expected {
+				t.Errorf("SubnetContainsSubnet() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}